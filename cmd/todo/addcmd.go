@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runAddCommand adds a todo from one quick-add line:
+//
+//	todo add 'Fix login bug #auth !high due:fri ^t42 project:web'
+//
+// Quote the line: shells treat a word starting with # as a comment.
+func runAddCommand(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	var (
+		file   = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		dryRun = fs.Bool("n", false, "show how the line is read without adding it")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	line := strings.Join(fs.Args(), " ")
+	in, toks := commands.ParseQuickAdd(line)
+	if in.Title == "" {
+		return errors.New(`usage: todo add 'TITLE [#tag] [!high|!medium|!low] [due:WHEN] [^PARENT] [project:NAME]'`)
+	}
+
+	if *dryRun {
+		for _, tok := range toks {
+			if tok.Kind != commands.QuickTitle {
+				fmt.Printf("%-8s %s\n", tok.Kind, tok.Value)
+			}
+		}
+		fmt.Printf("%-8s %s\n", commands.QuickTitle, in.Title)
+		return nil
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	res := e.addTodo().Execute(context.Background(), in)
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("%w: check the due date, project and parent of %q", res.Err, line)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	fmt.Println(summarize(res.Value, e))
+	return nil
+}
+
+// summarize is a one-line description of td, with what quick-add resolved.
+func summarize(td todo.Todo, e *env) string {
+	parts := []string{td.ID.String() + ":", td.Title.String(), "[" + td.Priority.String() + "]"}
+	for _, tag := range td.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if !td.Project.IsInbox() {
+		parts = append(parts, "project:"+td.Project.String())
+	}
+	if td.ParentID != "" {
+		parts = append(parts, "^"+td.ParentID.String())
+	}
+	if td.DueDate != nil {
+		parts = append(parts, "due "+describeDate(*td.DueDate, e.clock.Now(), e.cfg.Dates.Format))
+	}
+	return strings.Join(parts, " ")
+}
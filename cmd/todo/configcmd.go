@@ -0,0 +1,102 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/config"
+)
+
+// runConfigCommand reads and edits the config file:
+//
+//	todo config get [KEY]      effective value(s), profile and env applied
+//	todo config set KEY VALUE  list keys take comma-separated values
+//	todo config validate       report bad values and unknown keys
+//	todo config path           print the config file's path
+//
+// With --profile (before "config"), set writes into that profile.
+func runConfigCommand(args []string) error {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: todo config get [KEY] | set KEY VALUE | validate | path")
+	}
+
+	switch sub, rest := fs.Arg(0), fs.Args()[1:]; sub {
+	case "get":
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		keys := config.Keys()
+		if len(rest) > 0 {
+			keys = rest
+		}
+		for _, k := range keys {
+			v, err := cfg.Get(k)
+			if err != nil {
+				return err
+			}
+			switch x := v.(type) {
+			case string:
+				if len(rest) == 1 {
+					fmt.Println(x) // bare, for $(todo config get store.path)
+					continue
+				}
+			case []string:
+				if len(rest) == 1 {
+					fmt.Println(strings.Join(x, ","))
+					continue
+				}
+			}
+			fmt.Printf("%s = %s\n", k, config.Format(v))
+		}
+		return nil
+
+	case "set":
+		if len(rest) != 2 {
+			return errors.New("usage: todo config set KEY VALUE")
+		}
+		file, err := config.FilePath(globalOpts.File)
+		if err != nil {
+			return err
+		}
+		return config.Set(file, cmp.Or(globalOpts.Profile, os.Getenv("GOTODO_PROFILE")), rest[0], rest[1])
+
+	case "validate":
+		file, err := config.FilePath(globalOpts.File)
+		if err != nil {
+			return err
+		}
+		doc, err := config.ReadDocument(file)
+		if err != nil {
+			return err
+		}
+		problems := config.Validate(doc)
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, p)
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d problem(s)", len(problems))
+		}
+		fmt.Fprintf(os.Stderr, "%s: ok\n", file)
+		return nil
+
+	case "path":
+		file, err := config.FilePath(globalOpts.File)
+		if err != nil {
+			return err
+		}
+		fmt.Println(file)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown config command %q", sub)
+	}
+}
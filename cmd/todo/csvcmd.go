@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/csvimport"
+)
+
+type csvFlags struct {
+	mapping   *string
+	header    *string
+	delimiter *string
+	tagSep    *string
+	layouts   stringList
+	priority  *string
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, " ") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+func registerCSVFlags(fs *flag.FlagSet) *csvFlags {
+	f := &csvFlags{
+		mapping:   fs.String("map", "", "csv: field=column pairs, e.g. title=Action Item,due=Deadline,tags=3"),
+		header:    fs.String("header", "auto", "csv: first row is a header (auto, yes, no)"),
+		delimiter: fs.String("delimiter", ",", "csv: field delimiter"),
+		tagSep:    fs.String("tag-sep", ";", "csv: separator between tags in the tags column"),
+		priority:  fs.String("priority", "low", "csv: priority for rows without one"),
+	}
+	fs.Var(&f.layouts, "date-layout", "csv: Go date layout for the due column (repeatable, default 2006-01-02)")
+	return f
+}
+
+// importCSV decodes r and prints the validation report. Rejected rows are
+// reported, never imported; titles already in the store count as duplicates.
+func importCSV(ctx context.Context, e *env, r io.Reader, f *csvFlags) ([]todo.Todo, error) {
+	mapping, err := csvimport.ParseMapping(*f.mapping)
+	if err != nil {
+		return nil, err
+	}
+	comma, size := utf8.DecodeRuneInString(*f.delimiter)
+	if size == 0 || size != len(*f.delimiter) {
+		return nil, errors.New("--delimiter must be a single character")
+	}
+	priority, err := todo.NewPriority(*f.priority)
+	if err != nil {
+		return nil, fmt.Errorf("--priority: %w", err)
+	}
+	header := csvimport.HeaderMode(*f.header)
+	switch header {
+	case csvimport.HeaderAuto, csvimport.HeaderYes, csvimport.HeaderNo:
+	default:
+		return nil, fmt.Errorf("unknown --header %q (want auto, yes or no)", *f.header)
+	}
+
+	existing := queries.ListTodos{Repo: e.repo}.Execute(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if existing.Err != nil {
+		return nil, existing.Err
+	}
+	titles := make([]string, len(existing.Value))
+	for i, td := range existing.Value {
+		titles[i] = td.Title
+	}
+
+	res, err := csvimport.Decode(r, csvimport.Options{
+		Mapping:         mapping,
+		Header:          header,
+		Comma:           comma,
+		TagSeparator:    *f.tagSep,
+		DateLayouts:     f.layouts,
+		DefaultPriority: priority,
+		ExistingTitles:  titles,
+	}, e.ids, e.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rej := range res.Rejected {
+		fmt.Fprintf(os.Stderr, "line %d: rejected: %s\n", rej.Line, rej.Reason)
+	}
+	if len(res.Rejected) > 0 {
+		fmt.Fprintf(os.Stderr, "%d rows rejected, %d accepted.\n", len(res.Rejected), len(res.Todos))
+	}
+	return res.Todos, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/hooks"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/notify"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/reminder"
+)
+
+// runDaemonCommand fires reminders until interrupted:
+//
+//	todo daemon                      ring the bell and print them
+//	todo daemon --notify hook        run the on-remind hook
+//	todo daemon --notify bell,file --out /tmp/reminders.fifo
+//
+// It re-plans when the store changes, and reminders missed while the
+// daemon was stopped or the machine slept fire late, marked as missed.
+func runDaemonCommand(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	var (
+		file  = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		via   = fs.String("notify", "bell", "comma-separated notifiers: bell, hook (on-remind), file")
+		out   = fs.String("out", "", "file or FIFO for the file notifier, one JSON object per reminder")
+		poll  = fs.Duration("poll", reminder.DefaultPoll, "longest time between checks")
+		watch = fs.Duration("watch", 2*time.Second, "how often to check the store for changes")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+
+	var notifiers notify.Multi
+	for _, name := range strings.Split(*via, ",") {
+		var n ports.Notifier
+		switch strings.TrimSpace(name) {
+		case "bell":
+			n = notify.Bell{W: os.Stdout}
+		case "hook":
+			n = hooks.Notifier{Runner: e.hooks}
+		case "file":
+			if *out == "" {
+				return fmt.Errorf("--notify file needs --out")
+			}
+			n = notify.File{Path: *out}
+		default:
+			return fmt.Errorf("unknown notifier %q (want bell, hook or file)", name)
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := &reminder.Scheduler{
+		Reminders: queries.Reminders{Repo: e.repo, Clock: e.clock},
+		Notifier:  notifiers,
+		Changes:   reminder.Watch(ctx, e.dbPath, *watch),
+		Poll:      *poll,
+		State:     filepath.Join(e.dir, "reminders.state"),
+		Logger:    e.logger,
+	}
+	fmt.Fprintf(os.Stderr, "Watching %s for reminders (Ctrl-C to stop)\n", e.dbPath)
+	return s.Run(ctx)
+}
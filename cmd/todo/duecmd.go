@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runDueCommand sets or clears a due date: `todo due ID WHEN`, where WHEN
+// is a date, something like "next fri", "+2w" or "fri 17:00", or "none".
+// The resolved date is printed.
+func runDueCommand(args []string) error {
+	fs := flag.NewFlagSet("due", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: todo due ID WHEN (2026-11-20, tomorrow, fri 17:00, next fri, in 3 days, +2w, eow, eom, none)")
+	}
+	id, when := todo.TodoID(fs.Arg(0)), strings.Join(fs.Args()[1:], " ")
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	var due *string
+	if when != "none" {
+		due = &when
+	}
+	res := e.editTodo().Execute(context.Background(), commands.EditTodoInput{ID: id, DueDate: &due})
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("cannot read %q as a date", when)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	if res.Value.DueDate == nil {
+		fmt.Printf("%s: no due date\n", id)
+		return nil
+	}
+	fmt.Printf("%s: due %s\n", id, describeDate(*res.Value.DueDate, e.clock.Now(), e.cfg.Dates.Format))
+	return nil
+}
+
+// describeDate shows a due date with its weekday and distance from today,
+// like "Fri 2026-10-23 17:00 (in 4 days)", noting when it is overdue.
+func describeDate(d todo.DueDate, now time.Time, layout string) string {
+	s := describeDay(d, now, layout)
+	if d.IsOverdue(now) {
+		s = strings.TrimSuffix(s, ")") + ", overdue)"
+	}
+	return s
+}
+
+// describeDay is describeDate for dates that can't be missed.
+func describeDay(d todo.DueDate, now time.Time, layout string) string {
+	t := d.In(time.UTC)
+	if d.HasTime() {
+		layout += " 15:04"
+	}
+
+	var rel string
+	switch n := d.DaysFrom(now); {
+	case n == 0:
+		rel = "today"
+	case n == 1:
+		rel = "tomorrow"
+	case n == -1:
+		rel = "yesterday"
+	case n > 1:
+		rel = fmt.Sprintf("in %d days", n)
+	default:
+		rel = fmt.Sprintf("%d days ago", -n)
+	}
+	return fmt.Sprintf("%s %s (%s)", t.Format("Mon"), t.Format(layout), rel)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/gitstore"
+)
+
+// runGitCommand manages git-backed history: `todo git init [--remote URL]`
+// and `todo git sync [--remote NAME]`.
+func runGitCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: todo git init|sync [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("git "+sub, flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	var remote *string
+	switch sub {
+	case "init":
+		remote = fs.String("remote", "", "URL of a remote to sync with (added as origin)")
+	case "sync":
+		remote = fs.String("remote", "origin", "remote to sync with")
+	default:
+		return fmt.Errorf("unknown git command %q (want init or sync)", sub)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if sub == "init" {
+		repo, err := gitstore.Init(ctx, e.dbPath)
+		if err != nil {
+			return err
+		}
+		if *remote != "" {
+			if err := repo.AddRemote(ctx, "origin", *remote); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Recording history of %s in %s.\n", e.dbPath, repo.Dir)
+		return nil
+	}
+
+	repo, err := gitstore.Open(e.dbPath)
+	if err != nil {
+		return err
+	}
+	res, err := repo.Sync(ctx, *remote)
+	if err != nil {
+		return err
+	}
+	for _, c := range res.Conflicts {
+		fmt.Printf("conflict %s %q: %s kept %s, dropped %s\n", c.ID, c.Title, c.Field, c.Kept, c.Lost)
+	}
+	fmt.Fprintf(os.Stderr, "Synced %s with %s: %d pulled, pushed: %v, %d conflicts.\n",
+		res.Branch, *remote, res.Pulled, res.Pushed, len(res.Conflicts))
+	return nil
+}
+
+// runLogCommand lists the store's history, newest first.
+func runLogCommand(args []string) error {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	var (
+		n    = fs.Int("n", 20, "number of entries (0: all)")
+		file = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	repo, err := gitstore.Open(e.dbPath)
+	if err != nil {
+		return err
+	}
+	entries, err := repo.Log(context.Background(), *n)
+	if err != nil {
+		return err
+	}
+	for _, en := range entries {
+		fmt.Printf("%s  %s  %s\n", en.Hash[:7], en.Time.Local().Format(e.cfg.Dates.Format+" 15:04"), en.Subject)
+	}
+	return nil
+}
+
+// runDiffCommand shows what changed, todo by todo: `todo diff` since the
+// last commit's parent (i.e. the latest change plus anything pending),
+// `todo diff REV` since REV, `todo diff FROM TO` between two revisions.
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 2 {
+		return errors.New("usage: todo diff [FROM [TO]]")
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	repo, err := gitstore.Open(e.dbPath)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	from, to := fs.Arg(0), fs.Arg(1)
+	if from == "" {
+		from = "HEAD~1"
+		if log, err := repo.Log(ctx, 2); err == nil && len(log) < 2 {
+			from = "" // first commit: everything is new
+		}
+	}
+
+	var changes []gitstore.Change
+	if from == "" {
+		cur, err := repo.Load(ctx, to)
+		if err != nil {
+			return err
+		}
+		changes = gitstore.Diff(nil, cur)
+	} else if changes, err = repo.DiffRevs(ctx, from, to); err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(os.Stderr, "No changes.")
+	}
+	for _, ch := range changes {
+		mark := map[string]string{"added": "+", "removed": "-", "changed": "~"}[ch.Kind]
+		fmt.Printf("%s %s %s\n", mark, ch.ID, ch.Title)
+		for _, f := range ch.Fields {
+			fmt.Printf("    %s: %s → %s\n", f.Field, orNone(f.Old), orNone(f.New))
+		}
+	}
+	return nil
+}
+
+func orNone(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/ical"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/markdown"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/taskwarrior"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/todotxt"
+)
+
+// codec is one import/export format. Either side may be nil when a format
+// only goes one way. Fields are what it carries: importing onto an
+// existing todo changes only those.
+type codec struct {
+	decode func(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error)
+	encode func(w io.Writer, tds []todo.Todo) error
+	fields []commands.ImportField
+}
+
+var codecs = map[string]codec{
+	"todotxt": {decode: todotxt.Decode, encode: todotxt.Encode, fields: []commands.ImportField{
+		commands.ImportTitle, commands.ImportStatus, commands.ImportPriority, commands.ImportTags,
+		commands.ImportDueDate, commands.ImportScheduled, commands.ImportMeta,
+	}},
+	"ical": {decode: ical.Decode, encode: ical.Encode, fields: []commands.ImportField{
+		commands.ImportTitle, commands.ImportStatus, commands.ImportPriority, commands.ImportTags,
+		commands.ImportDueDate, commands.ImportScheduled,
+	}},
+	"markdown": {decode: markdown.Decode, encode: markdown.Encode, fields: []commands.ImportField{
+		commands.ImportTitle, commands.ImportStatus, commands.ImportTags, commands.ImportDueDate,
+		commands.ImportParent,
+	}},
+	"taskwarrior": {decode: taskwarrior.Decode, encode: taskwarrior.Encode, fields: []commands.ImportField{
+		commands.ImportTitle, commands.ImportStatus, commands.ImportPriority, commands.ImportTags,
+		commands.ImportDueDate, commands.ImportScheduled, commands.ImportMeta,
+	}},
+	// import only, with its own options: see importCSV
+	"csv": {fields: []commands.ImportField{
+		commands.ImportTitle, commands.ImportPriority, commands.ImportTags, commands.ImportDueDate,
+	}},
+}
+
+// zoned returns c reading and writing dates in loc, for the formats that
+// keep due dates as UTC times.
+func zoned(name string, c codec, loc *time.Location) codec {
+	switch name {
+	case "ical":
+		c.decode = ical.Decoder{Location: loc}.Decode
+	case "taskwarrior":
+		tw := taskwarrior.Codec{Location: loc}
+		c.decode, c.encode = tw.Decode, tw.Encode
+	}
+	return c
+}
+
+func lookupCodec(name string) (codec, error) {
+	if c, ok := codecs[name]; ok {
+		return c, nil
+	}
+	return codec{}, fmt.Errorf("unknown format %q (want one of: %s)", name, codecNames())
+}
+
+func codecNames() string {
+	names := make([]string, 0, len(codecs))
+	for n := range codecs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func runImportCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+
+	var (
+		format = fs.String("format", "", "input format ("+codecNames()+")")
+		file   = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		dryRun = fs.Bool("dry-run", false, "only report what would be imported")
+		csvOpt = registerCSVFlags(fs)
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := lookupCodec(*format)
+	if err != nil {
+		return err
+	}
+	if c.decode == nil && *format != "csv" {
+		return fmt.Errorf("format %q cannot be imported", *format)
+	}
+
+	in, closeIn, err := openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer closeIn()
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+
+	c = zoned(*format, c, e.cfg.Dates.Location)
+
+	var tds []todo.Todo
+	if *format == "csv" {
+		tds, err = importCSV(context.Background(), e, in, csvOpt)
+	} else {
+		tds, err = c.decode(in, e.ids, e.clock.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		for _, td := range tds {
+			fmt.Printf("would import: %s\n", td.Title)
+		}
+		fmt.Fprintf(os.Stderr, "Dry run: %d todos would be imported.\n", len(tds))
+		return nil
+	}
+
+	imp := commands.ImportTodos{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	res := imp.Execute(context.Background(), commands.ImportTodosInput{Todos: tds, Fields: c.fields})
+	if res.Err != nil {
+		return res.Err
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported %d todos (%d new, %d updated, %d unchanged).\n",
+		len(tds), res.Value.Created, res.Value.Updated, res.Value.Unchanged)
+	return nil
+}
+
+func runExportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+
+	var (
+		format = fs.String("format", "", "output format ("+codecNames()+")")
+		file   = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		output = fs.String("o", "", "write to this file instead of stdout")
+		group  = fs.String("group", "status", "markdown: group by status or tag")
+	)
+	project := optionalString(fs, "project", `only todos in this project ("" for the inbox)`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := lookupCodec(*format)
+	if err != nil {
+		return err
+	}
+	if c.encode == nil {
+		return fmt.Errorf("format %q cannot be exported", *format)
+	}
+	if *format == "markdown" {
+		switch g := markdown.GroupBy(*group); g {
+		case markdown.GroupByStatus, markdown.GroupByTag:
+			c.encode = markdown.Encoder{GroupBy: g}.Encode
+		default:
+			return fmt.Errorf("unknown group %q (want status or tag)", *group)
+		}
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	c = zoned(*format, c, e.cfg.Dates.Location)
+
+	spec := ports.ListSpec{IncludeUnstarted: true}
+	if *project != nil {
+		p, err := todo.NewProject(**project)
+		if err != nil {
+			return fmt.Errorf("invalid project %q", **project)
+		}
+		spec.Project = &p
+	}
+
+	res := queries.ExportTodos{Repo: e.todos()}.Execute(context.Background(), spec)
+	if res.Err != nil {
+		return res.Err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	return c.encode(out, res.Value)
+}
+
+// openInput opens path, or stdin for "" and "-".
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "" || path == "-" {
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			return nil, nil, errors.New("no input: pass a file or pipe data on stdin")
+		}
+		return os.Stdin, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { _ = f.Close() }, nil
+}
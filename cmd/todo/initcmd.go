@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/workspace"
+)
+
+// runInitCommand makes a directory (default: the current one) a workspace
+// with its own store: `todo init [DIR]`.
+func runInitCommand(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: todo init [DIR]")
+	}
+	dir := fs.Arg(0)
+	if dir == "" {
+		dir = "."
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	root, created, err := workspace.Init(dir)
+	if err != nil {
+		return err
+	}
+	if err := workspaces(cfg).Add(root); err != nil {
+		return err
+	}
+
+	if !created {
+		fmt.Fprintf(os.Stderr, "%s is already a workspace.\n", root)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Initialized a workspace in %s; todos go to %s.\n", root, workspace.StorePath(root))
+	return nil
+}
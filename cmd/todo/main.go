@@ -1,70 +1,128 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	_ "time/tzdata" // dates.timezone works without a system zone database
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/rojanmagar2001/gotodo/internal/application/commands"
 	"github.com/rojanmagar2001/gotodo/internal/application/queries"
-	"github.com/rojanmagar2001/gotodo/internal/infrastructure/clock"
-	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
-	"github.com/rojanmagar2001/gotodo/internal/infrastructure/idgen"
-	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
-	"github.com/rojanmagar2001/gotodo/internal/infrastructure/logging"
 	"github.com/rojanmagar2001/gotodo/internal/interfaces/tui"
 )
 
+var subcommands = map[string]func(args []string) error{
+	"seed":     runSeedCommand,
+	"import":   runImportCommand,
+	"export":   runExportCommand,
+	"sync":     runSyncCommand,
+	"scan":     runScanCommand,
+	"serve":    runServeCommand,
+	"rpc":      runRPCCommand,
+	"mcp":      runMCPCommand,
+	"git":      runGitCommand,
+	"log":      runLogCommand,
+	"diff":     runDiffCommand,
+	"config":   runConfigCommand,
+	"project":  runProjectCommand,
+	"init":     runInitCommand,
+	"mv":       runMoveCommand,
+	"due":      runDueCommand,
+	"add":      runAddCommand,
+	"snooze":   runSnoozeCommand,
+	"remind":   runRemindCommand,
+	"daemon":   runDaemonCommand,
+	"start":    runStartCommand,
+	"stop":     runStopCommand,
+	"track":    runTrackCommand,
+	"report":   runReportCommand,
+	"estimate": runEstimateCommand,
+	"plan":     runPlanCommand,
+}
+
 func main() {
-	if len(os.Args) > 1 && os.Args[1] == "seed" {
-		if err := runSeedCommand(os.Args[2:]); err != nil {
-			fmt.Fprintln(os.Stderr, "seed error:", err)
-			os.Exit(1)
-		}
+	// global flags go before the subcommand: todo --profile work serve
+	global := flag.NewFlagSet("todo", flag.ContinueOnError)
+	global.StringVar(&globalOpts.File, "config", "", "config file (default $XDG_CONFIG_HOME/gotodo/config.toml)")
+	global.StringVar(&globalOpts.Profile, "profile", "", "config profile to use (default $GOTODO_PROFILE)")
+	noWorkspace := global.Bool("global", false, "use the global store even inside a workspace")
+	global.BoolVar(&allWorkspaces, "all", false, "show the global store and every known workspace (TUI, export, project list, report)")
+	if err := global.Parse(os.Args[1:]); err == flag.ErrHelp {
 		return
+	} else if err != nil {
+		os.Exit(2)
 	}
+	args := global.Args()
 
-	logger := logging.New()
+	if !*noWorkspace {
+		globalOpts.Dir, _ = os.Getwd()
+	}
+
+	if len(args) > 0 {
+		if run, ok := subcommands[args[0]]; ok {
+			if allWorkspaces && !slices.Contains([]string{"export", "project", "report"}, args[0]) {
+				fmt.Fprintf(os.Stderr, "%s error: --all is not supported\n", args[0])
+				os.Exit(2)
+			}
+			err := run(args[1:])
+			flushWebhooks(webhookGrace)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s error: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
 
-	// storage path (for now: ~/.gotodo/todos.json)
-	home, err := os.UserHomeDir()
+	e, err := newEnv("")
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "error:", err)
 		os.Exit(1)
 	}
-	dir := filepath.Join(home, ".gotodo")
-	_ = os.MkdirAll(dir, 0o700)
-	dbPath := filepath.Join(dir, "todos.json")
-
-	repo := jsonstore.NewRepository(dbPath)
-
-	clk := clock.RealClock{}
-	ids := idgen.RandomIDGen{}
-	pub := events.LogPublisher{L: logger}
 
 	// undo := commands.NewUndoManager()
 
 	// Commands
-	add := commands.AddTodo{Repo: repo, Clock: clk, IDGen: ids, Publisher: pub}
-	complete := commands.CompleteTodo{Repo: repo, Clock: clk, Publisher: pub}
+	add := e.addTodo()
+	complete := commands.CompleteTodo{Repo: e.todos(), Clock: e.clock, Publisher: e.pub}
+	start := commands.StartTimer{Repo: e.todos(), Clock: e.clock, Publisher: e.pub}
+	stop := commands.StopTimer{Repo: e.todos(), Clock: e.clock, Publisher: e.pub}
 
 	// Queries
-	list := queries.ListTodos{Repo: repo}
-	get := queries.GetTodo{Repo: repo}
-	stats := queries.Stats{Repo: repo, Clock: clk}
+	list := queries.ListTodos{Repo: e.todos(), Clock: e.clock}
+	get := queries.GetTodo{Repo: e.todos()}
+	stats := queries.Stats{Repo: e.todos(), Clock: e.clock}
+	projects := queries.ListProjects{Repo: e.todos(), Projects: e.todos(), Clock: e.clock}
+	timer := queries.RunningTimer{Repo: e.todos()}
+
+	keys := tui.DefaultKeymap()
+	for action, k := range e.cfg.TUI.Keys {
+		keys = keys.Bind(action, k)
+	}
 
 	app := tui.App{
 		Add:      add,
 		Complete: complete,
+		Start:    start,
+		Stop:     stop,
 		List:     list,
 		Get:      get,
 		Stats:    stats,
+		Projects: projects,
+		Timer:    timer,
+
+		Keys:       &keys,
+		Theme:      tui.ThemeNamed(e.cfg.TUI.Theme),
+		DateFormat: e.cfg.Dates.Format,
 	}
 
 	p := tea.NewProgram(tui.NewModel(app), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	flushWebhooks(webhookGrace)
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "error:", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/mcp"
+)
+
+// runMCPCommand serves the Model Context Protocol on stdin/stdout for AI
+// assistants. Saved filters are read from filters.json in the data dir.
+func runMCPCommand(args []string) error {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+
+	var (
+		file     = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		readOnly = fs.Bool("read-only", false, "only expose tools and resources that don't change todos")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	filters, err := mcp.LoadFilters(filepath.Join(e.dir, "filters.json"))
+	if err != nil {
+		return err
+	}
+
+	srv := &mcp.Server{
+		Add:      e.addTodo(),
+		Edit:     e.editTodo(),
+		Complete: commands.CompleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+
+		List:     queries.ListTodos{Repo: e.repo, Clock: e.clock},
+		Get:      queries.GetTodo{Repo: e.repo},
+		Stats:    queries.Stats{Repo: e.repo, Clock: e.clock},
+		Projects: queries.ListProjects{Repo: e.repo, Projects: e.repo, Clock: e.clock},
+
+		ReadOnly: *readOnly,
+		Filters:  filters,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return srv.Serve(ctx, os.Stdin, os.Stdout)
+}
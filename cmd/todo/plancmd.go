@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runEstimateCommand sets or clears a todo's estimate: `todo estimate ID
+// 90m|2h|3pt|none`.
+func runEstimateCommand(args []string) error {
+	fs := flag.NewFlagSet("estimate", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: todo estimate ID EFFORT (90m, 2h, 1h30m, 3pt, none)")
+	}
+	id, effort := todo.TodoID(fs.Arg(0)), fs.Arg(1)
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	est := effort
+	if effort == "none" {
+		est = ""
+	}
+	res := e.editTodo().Execute(context.Background(), commands.EditTodoInput{ID: id, Estimate: &est})
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("cannot read %q as an estimate; try \"90m\", \"2h\" or \"3pt\"", effort)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+	if res.Value.Estimate == nil {
+		fmt.Printf("%s: no estimate\n", id)
+		return nil
+	}
+	fmt.Printf("%s: estimated at %s\n", id, res.Value.Estimate)
+	return nil
+}
+
+// runPlanCommand lays out the active todos over the coming days: `todo
+// plan [--capacity 6h] [--from WHEN]`. Capacity and days off default to
+// plan.capacity and plan.days_off from the config.
+func runPlanCommand(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var (
+		file     = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		capacity = fs.String("capacity", "", "a day's work, a time (6h) or story points (8pt) (default: plan.capacity)")
+		from     = fs.String("from", "", "first day of the plan (default: today)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	now := e.clock.Now()
+	spec := queries.PlanSpec{Capacity: e.cfg.Plan.Capacity, Off: e.cfg.Plan.DaysOff}
+	if *capacity != "" {
+		if spec.Capacity, err = todo.ParseEstimate(*capacity); err != nil {
+			return fmt.Errorf("--capacity: cannot read %q; try \"6h\" or \"8pt\"", *capacity)
+		}
+	}
+	if *from != "" {
+		d, err := todo.ResolveDueDate(*from, now, e.cfg.Dates.WeekStart)
+		if err != nil {
+			return fmt.Errorf("--from: cannot read %q as a date", *from)
+		}
+		spec.Start = d.Date().In(now.Location())
+	}
+
+	res := queries.Plan{Repo: e.todos(), Clock: e.clock}.Execute(context.Background(), spec)
+	if res.Err != nil {
+		return res.Err
+	}
+	plan := res.Value
+	effort := func(v int) string { return todo.Estimate{Value: v, Unit: spec.Capacity.Unit}.String() }
+
+	var over, late int
+	for _, day := range plan.Days {
+		date, _ := time.Parse("2006-01-02", day.Date)
+		head := date.Format("Mon ") + date.Format(e.cfg.Dates.Format)
+		switch {
+		case day.Capacity == 0 && len(day.Items) == 0:
+			fmt.Printf("%s  off\n", head)
+			continue
+		case day.Over:
+			over++
+			fmt.Printf("%s  %s / %s  over capacity\n", head, effort(day.Load), effort(day.Capacity))
+		default:
+			fmt.Printf("%s  %s / %s\n", head, effort(day.Load), effort(day.Capacity))
+		}
+		for _, it := range day.Items {
+			line := fmt.Sprintf("  %s: %s  %s", it.Todo.ID, it.Todo.Title, effort(it.Effort))
+			if it.Todo.DueDate != nil {
+				line += "  due " + formatDay(*it.Todo.DueDate, e.cfg.Dates.Format)
+			}
+			if it.Late {
+				late++
+				line += "  LATE"
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(plan.Days) == 0 {
+		fmt.Println("nothing to plan")
+	}
+	if n := len(plan.Unestimated); n > 0 {
+		fmt.Printf("\nnot planned: %s without an estimate in %s\n", count(n, "todo"), unitName(spec.Capacity.Unit))
+	}
+	if over > 0 || late > 0 {
+		fmt.Printf("\n%s over capacity, %s cannot meet the due date\n", count(over, "day"), count(late, "todo"))
+	}
+	return nil
+}
+
+func formatDay(iso, layout string) string {
+	d, err := todo.ParseDueDate(iso)
+	if err != nil {
+		return iso
+	}
+	return d.AsTimeUTC().Format(layout)
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func unitName(u todo.EstimateUnit) string {
+	if u == todo.EstimatePoints {
+		return "story points"
+	}
+	return "time"
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runProjectCommand manages projects: `todo project list|add|rename|rm`.
+func runProjectCommand(args []string) error {
+	const usage = "usage: todo project list | add NAME | rename OLD NEW | rm NAME [--move-to PROJECT]"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("project "+sub, flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	moveTo := new(*string)
+	if sub == "rm" {
+		moveTo = optionalString(fs, "move-to", `move the project's todos here first ("" for the inbox)`)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	want := map[string]int{"list": 0, "add": 1, "rename": 2, "rm": 1}
+	n, ok := want[sub]
+	if !ok {
+		return fmt.Errorf("unknown project command %q (want list, add, rename or rm)", sub)
+	}
+	if fs.NArg() != n {
+		return errors.New(usage)
+	}
+
+	if allWorkspaces && sub != "list" {
+		return errors.New("--all only works with project list")
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch sub {
+	case "list":
+		res := queries.ListProjects{Repo: e.todos(), Projects: e.todos(), Clock: e.clock}.Execute(ctx)
+		if res.Err != nil {
+			return res.Err
+		}
+		for _, p := range res.Value {
+			fmt.Printf("%-24s %3d active  %3d done  %3d archived  %3d overdue\n",
+				projectLabel(p.Name), p.Active, p.Done, p.Archived, p.Overdue)
+		}
+
+	case "add":
+		res := commands.AddProject{Projects: e.repo}.Execute(ctx, fs.Arg(0))
+		if res.Err != nil {
+			return projectError(res.Err, fs.Arg(0))
+		}
+		fmt.Fprintf(os.Stderr, "Added project %s.\n", res.Value)
+
+	case "rename":
+		res := commands.RenameProject{Repo: e.repo, Projects: e.repo, Clock: e.clock, Publisher: e.pub}.
+			Execute(ctx, commands.RenameProjectInput{From: fs.Arg(0), To: fs.Arg(1)})
+		if res.Err != nil {
+			return projectError(res.Err, fs.Arg(0))
+		}
+		fmt.Fprintf(os.Stderr, "Renamed %s to %s, %d todos moved.\n", fs.Arg(0), fs.Arg(1), res.Value)
+
+	case "rm":
+		res := commands.RemoveProject{Repo: e.repo, Projects: e.repo, Clock: e.clock, Publisher: e.pub}.
+			Execute(ctx, commands.RemoveProjectInput{Name: fs.Arg(0), MoveTo: *moveTo})
+		if errors.Is(res.Err, appErr.ErrConflict) {
+			return fmt.Errorf("project %s still has todos; use --move-to", fs.Arg(0))
+		}
+		if res.Err != nil {
+			return projectError(res.Err, fs.Arg(0))
+		}
+		fmt.Fprintf(os.Stderr, "Removed project %s, %d todos moved.\n", fs.Arg(0), res.Value)
+	}
+	return nil
+}
+
+// runMoveCommand moves todos to a project: `todo mv ID... PROJECT`, with
+// PROJECT "" for the inbox.
+func runMoveCommand(args []string) error {
+	fs := flag.NewFlagSet("mv", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New(`usage: todo mv ID... PROJECT ("" for the inbox)`)
+	}
+	ids, project := fs.Args()[:fs.NArg()-1], fs.Arg(fs.NArg()-1)
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	edit := e.editTodo()
+	for _, id := range ids {
+		res := edit.Execute(context.Background(), commands.EditTodoInput{ID: todo.TodoID(id), Project: &project})
+		if res.Err != nil {
+			return fmt.Errorf("%s: %w", id, projectError(res.Err, project))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Moved %d todos to %s.\n", len(ids), projectLabel(project))
+	return nil
+}
+
+func projectLabel(name string) string {
+	if name == "" {
+		return "(inbox)"
+	}
+	return name
+}
+
+func projectError(err error, name string) error {
+	if errors.Is(err, appErr.ErrValidation) {
+		return fmt.Errorf("invalid project %q (letters, digits, - _ . and /)", name)
+	}
+	return err
+}
+
+// optionalString is a string flag that tells unset apart from "".
+func optionalString(fs *flag.FlagSet, name, usage string) **string {
+	v := new(*string)
+	fs.Func(name, usage, func(s string) error {
+		*v = &s
+		return nil
+	})
+	return v
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runRemindCommand sets or clears a reminder: `todo remind ID WHEN`, where
+// WHEN is read like a due date but needs a time ("fri 9am", "+2h"), or is
+// "none". `todo daemon` does the reminding.
+func runRemindCommand(args []string) error {
+	fs := flag.NewFlagSet("remind", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: todo remind ID WHEN (9am, fri 17:00, tomorrow@9am, +2h, 2026-11-20T09:00, none)")
+	}
+	id, when := todo.TodoID(fs.Arg(0)), strings.Join(fs.Args()[1:], " ")
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	var at *string
+	if when != "none" {
+		at = &when
+	}
+	res := e.editTodo().Execute(context.Background(), commands.EditTodoInput{ID: id, RemindAt: &at})
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("cannot read %q as a time; try \"fri 9am\" or \"+2h\"", when)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	d := res.Value.RemindAt
+	if d == nil {
+		fmt.Printf("%s: no reminder\n", id)
+		return nil
+	}
+	now := e.clock.Now()
+	fmt.Printf("%s: reminder %s\n", id, describeDay(*d, now, e.cfg.Dates.Format))
+	if !d.In(now.Location()).After(now) {
+		fmt.Fprintln(os.Stderr, "warning: that time has passed; the daemon will not remind you")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/rpcapi"
+)
+
+// runRPCCommand speaks JSON-RPC 2.0 on stdin/stdout. Logs go to stderr so
+// they never interleave with protocol messages.
+func runRPCCommand(args []string) error {
+	fs := flag.NewFlagSet("rpc", flag.ContinueOnError)
+
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+
+	srv := &rpcapi.Server{
+		List:  queries.ListTodos{Repo: e.repo, Clock: e.clock},
+		Get:   queries.GetTodo{Repo: e.repo},
+		Stats: queries.Stats{Repo: e.repo, Clock: e.clock},
+
+		Projects: queries.ListProjects{Repo: e.repo, Projects: e.repo, Clock: e.clock},
+	}
+	e.pub = append(e.pub, srv)
+
+	srv.Add = e.addTodo()
+	srv.Edit = e.editTodo()
+	srv.Complete = commands.CompleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	srv.Reopen = commands.ReopenTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	srv.Archive = commands.ArchiveTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	srv.Restore = commands.RestoreTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	srv.Delete = commands.SoftDeleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	srv.HardDelete = commands.HardDeleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return srv.Serve(ctx, os.Stdin, os.Stdout)
+}
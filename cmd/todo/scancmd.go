@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codescan"
+)
+
+func runScanCommand(args []string) error {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+
+	var (
+		syntaxes stringList
+		file     = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		dryRun   = fs.Bool("dry-run", false, "only list the comments found")
+	)
+	fs.Var(&syntaxes, "comment", "extra comment syntax, e.g. .py,.sh=# or .css=/*...*/ (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	syn := codescan.DefaultSyntaxes()
+	for _, s := range syntaxes {
+		if err := codescan.ParseSyntax(s, syn); err != nil {
+			return err
+		}
+	}
+
+	root, err := codescan.FindRoot(".")
+	if err != nil {
+		return err
+	}
+	targets, err := codescan.ParseTargets(root, fs.Args())
+	if err != nil {
+		return err
+	}
+	findings, err := codescan.Scanner{Root: root, Syntaxes: syn}.Scan(targets)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		for _, f := range findings {
+			fmt.Printf("%s:%d: %s %s\n", f.File, f.Line, f.Kind, f.Text)
+		}
+		fmt.Fprintf(os.Stderr, "Dry run: %d comments found.\n", len(findings))
+		return nil
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	return syncFindings(context.Background(), e, root, targets, findings)
+}
+
+func syncFindings(ctx context.Context, e *env, root string, targets []codescan.Target, findings []codescan.Finding) error {
+	store := queries.ExportTodos{Repo: e.repo}.Execute(ctx, ports.ListSpec{IncludeDeleted: true, IncludeUnstarted: true})
+	if store.Err != nil {
+		return store.Err
+	}
+
+	plan, err := codescan.Sync(findings, store.Value, codescan.SyncOptions{
+		Root:    root,
+		Targets: targets,
+		Now:     e.clock.Now(),
+		IDs:     e.ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	imp := commands.ImportTodos{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	if res := imp.Execute(ctx, commands.ImportTodosInput{Todos: append(plan.Create, plan.Update...)}); res.Err != nil {
+		return res.Err
+	}
+
+	complete := commands.CompleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	for _, id := range plan.Complete {
+		if r := complete.Execute(ctx, id); r.Err != nil {
+			return fmt.Errorf("complete %s: %w", id, r.Err)
+		}
+	}
+	reopen := commands.ReopenTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	for _, id := range plan.Reopen {
+		if r := reopen.Execute(ctx, id); r.Err != nil {
+			return fmt.Errorf("reopen %s: %w", id, r.Err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Scanned %s: %d comments, %d new, %d resolved, %d reopened.\n",
+		root, len(findings), len(plan.Create), len(plan.Complete), len(plan.Reopen))
+	return nil
+}
@@ -20,7 +20,7 @@ func runSeedCommand(args []string) error {
 	var (
 		n         = fs.Int("n", 1000, "number of todos to generate")
 		seed      = fs.Int64("seed", 42, "random seed (deterministic datasets)")
-		file      = fs.String("file", "", "path to todos.json (default ~/.gotodo/todos.json)")
+		file      = fs.String("file", "", "path to todos.json (default: store.path from the config)")
 		overwrite = fs.Bool("overwrite", false, "overwrite existing file (DANGEROUS)")
 	)
 
@@ -28,10 +28,11 @@ func runSeedCommand(args []string) error {
 		return err
 	}
 
-	dbPath, err := defaultDBPath(*file)
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
+	dbPath := cfg.DBPath(*file)
 
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o700); err != nil {
 		return err
@@ -70,17 +71,6 @@ func runSeedCommand(args []string) error {
 	return nil
 }
 
-func defaultDBPath(p string) (string, error) {
-	if p != "" {
-		return p, nil
-	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".gotodo", "todos.json"), nil
-}
-
 func genTodo(rng *rand.Rand, now time.Time, i int) (todo.Todo, error) {
 	// Deterministic ID: t000001, t000002...
 	id := todo.TodoID(fmt.Sprintf("t%06d", i+1))
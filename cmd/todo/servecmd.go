@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/reminder"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/httpapi"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/webui"
+)
+
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+
+	var (
+		addr = fs.String("addr", "127.0.0.1:8080", "listen address")
+		file = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the follower also streams what the CLI and TUI change in the store
+	broker := events.NewBroker(events.DefaultBufferSize)
+	follow := &events.Follower{Broker: broker, Repo: e.repo}
+	e.pub = append(e.pub, follow)
+	go func() { _ = follow.Run(ctx, reminder.Watch(ctx, e.dbPath, time.Second)) }()
+	api := newAPIServer(e)
+	api.Events = broker
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", api.Handler())
+	mux.Handle("/", webui.Handler())
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           httpapi.LocalOnly(*addr, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+		// end event streams on shutdown instead of waiting for them
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s/ (API under /api)\n", e.dbPath, *addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func newAPIServer(e *env) *httpapi.Server {
+	return &httpapi.Server{
+		Add:        e.addTodo(),
+		Edit:       e.editTodo(),
+		Complete:   commands.CompleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+		Reopen:     commands.ReopenTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+		Archive:    commands.ArchiveTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+		Restore:    commands.RestoreTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+		Delete:     commands.SoftDeleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+		HardDelete: commands.HardDeleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub},
+
+		List:     queries.ListTodos{Repo: e.repo, Clock: e.clock},
+		Get:      queries.GetTodo{Repo: e.repo},
+		Stats:    queries.Stats{Repo: e.repo, Clock: e.clock},
+		Projects: queries.ListProjects{Repo: e.repo, Projects: e.repo, Clock: e.clock},
+
+		Logger: e.logger,
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runSnoozeCommand hides a todo until later: `todo snooze ID WHEN`, where
+// WHEN is read like a due date ("3d", "mon", "tomorrow 9am"). "none"
+// makes the todo start now.
+func runSnoozeCommand(args []string) error {
+	fs := flag.NewFlagSet("snooze", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: todo snooze ID WHEN (3d, 2w, mon, next fri, tomorrow 9am, 2026-11-20, none)")
+	}
+	id, when := todo.TodoID(fs.Arg(0)), strings.Join(fs.Args()[1:], " ")
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	var until *string
+	if when != "none" {
+		until = &when
+	}
+	res := e.editTodo().Execute(context.Background(), commands.EditTodoInput{ID: id, Scheduled: &until})
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("cannot read %q as a date", when)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	now := e.clock.Now()
+	if res.Value.IsStarted(now) {
+		fmt.Printf("%s: not snoozed\n", id)
+		return nil
+	}
+	fmt.Printf("%s: snoozed until %s\n", id, describeDay(*res.Value.Scheduled, now, e.cfg.Dates.Format))
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/codec/markdown"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+func runSyncCommand(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+
+	var (
+		with   = fs.String("with", "", "merge with another todos.json (or a directory holding one)")
+		mdFile = fs.String("markdown", "", "two-way sync with this Markdown checklist file")
+		tag    = fs.String("tag", "", "markdown: only sync todos with this tag")
+		file   = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*with == "") == (*mdFile == "") {
+		return errors.New("pass exactly one of --with PATH or --markdown FILE")
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	if *with != "" {
+		return syncReplica(e, *with)
+	}
+	return syncMarkdown(context.Background(), e, *mdFile, strings.TrimSpace(*tag))
+}
+
+// syncReplica merges the store with another copy of it (on a share, a USB
+// stick, ...) so both end up with everyone's changes.
+func syncReplica(e *env, path string) error {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		path = filepath.Join(path, "todos.json")
+	}
+	self, _ := filepath.Abs(e.dbPath)
+	if abs, err := filepath.Abs(path); err == nil && abs == self {
+		return errors.New("cannot sync a store with itself")
+	}
+
+	rep, err := jsonstore.SyncFiles(e.dbPath, path)
+	if err != nil {
+		return err
+	}
+
+	if rep.Rekeyed {
+		fmt.Fprintf(os.Stderr, "%s was a copy of this store; gave it its own replica ID.\n", path)
+	}
+	for _, c := range rep.Conflicts {
+		fmt.Printf("conflict %s %q: %s kept %s, dropped %s\n", c.ID, c.Title, c.Field, c.Kept, c.Lost)
+	}
+	fmt.Fprintf(os.Stderr, "Synced with %s: %d pulled, %d pushed, %d conflicts.\n",
+		path, rep.Pulled, rep.Pushed, len(rep.Conflicts))
+	return nil
+}
+
+func syncMarkdown(ctx context.Context, e *env, path, tag string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	doc := &markdown.Doc{}
+	var modTime time.Time
+	if f, err := os.Open(abs); err == nil {
+		doc, err = markdown.Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		if fi, err := os.Stat(abs); err == nil {
+			modTime = fi.ModTime()
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	store := queries.ExportTodos{Repo: e.repo}.Execute(ctx, ports.ListSpec{IncludeDeleted: true, IncludeUnstarted: true})
+	if store.Err != nil {
+		return store.Err
+	}
+
+	plan, err := markdown.Sync(doc, store.Value, markdown.SyncOptions{
+		File:    abs,
+		ModTime: modTime,
+		Now:     e.clock.Now(),
+		IDs:     e.ids,
+		Tag:     tag,
+	})
+	if err != nil {
+		return err
+	}
+
+	imp := commands.ImportTodos{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	res := imp.Execute(ctx, commands.ImportTodosInput{Todos: append(plan.Create, plan.Update...)})
+	if res.Err != nil {
+		return res.Err
+	}
+
+	del := commands.SoftDeleteTodo{Repo: e.repo, Clock: e.clock, Publisher: e.pub}
+	for _, id := range plan.Delete {
+		if r := del.Execute(ctx, id); r.Err != nil {
+			return fmt.Errorf("delete %s: %w", id, r.Err)
+		}
+	}
+
+	tmp := abs + ".tmp"
+	if err := os.WriteFile(tmp, []byte(plan.Output), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, abs); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Synced %s: %d new, %d updated, %d deleted.\n",
+		path, res.Value.Created, res.Value.Updated, len(plan.Delete))
+	return nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// runStartCommand starts the timer on a todo: `todo start ID`. A timer
+// running on another todo is stopped.
+func runStartCommand(args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: todo start ID")
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	res := commands.StartTimer{Repo: e.repo, Clock: e.clock, Publisher: e.pub}.Execute(context.Background(), todo.TodoID(fs.Arg(0)))
+	switch {
+	case errors.Is(res.Err, appErr.ErrConflict):
+		return fmt.Errorf("the timer is already running on %s", fs.Arg(0))
+	case errors.Is(res.Err, appErr.ErrValidation):
+		return fmt.Errorf("%s is not active", fs.Arg(0))
+	case res.Err != nil:
+		return res.Err
+	}
+
+	now := e.clock.Now()
+	if s := res.Value.Stopped; s != nil {
+		fmt.Printf("stopped %s: %s (%s in total)\n", s.ID, s.Title, formatTracked(s.Tracked(now)))
+	}
+	fmt.Printf("started %s: %s\n", res.Value.Started.ID, res.Value.Started.Title)
+	return nil
+}
+
+// runStopCommand stops the running timer: `todo stop`.
+func runStopCommand(args []string) error {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	file := fs.String("file", "", "path to todos.json (default: store.path from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	res := commands.StopTimer{Repo: e.repo, Clock: e.clock, Publisher: e.pub}.Execute(context.Background())
+	if errors.Is(res.Err, appErr.ErrNotFound) {
+		return errors.New("no timer is running")
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	td, now := res.Value, e.clock.Now()
+	last := td.TimeEntries[len(td.TimeEntries)-1]
+	fmt.Printf("stopped %s: %s after %s (%s in total)\n", td.ID, td.Title, formatTracked(last.Duration(now)), formatTracked(td.Tracked(now)))
+	return nil
+}
+
+// runTrackCommand logs time by hand: `todo track ID 1h30m`, ending now
+// unless --at says when it started.
+func runTrackCommand(args []string) error {
+	fs := flag.NewFlagSet("track", flag.ContinueOnError)
+	var (
+		file = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		at   = fs.String("at", "", "when the work started, e.g. \"9am\" or \"2026-10-16 14:00\" (default: it ends now)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: todo track [--at WHEN] ID DURATION (45m, 1h30m)")
+	}
+	d, err := time.ParseDuration(fs.Arg(1))
+	if err != nil || d <= 0 {
+		return fmt.Errorf("cannot read %q as a duration like 45m or 1h30m", fs.Arg(1))
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	now := e.clock.Now()
+	in := commands.LogTimeInput{ID: todo.TodoID(fs.Arg(0)), Duration: d}
+	if *at != "" {
+		when, err := todo.ResolveDueDate(*at, now, e.cfg.Dates.WeekStart)
+		if err != nil || !when.HasTime() {
+			return fmt.Errorf("cannot read %q as a time; try \"9am\" or \"2026-10-16 14:00\"", *at)
+		}
+		in.Start = when.In(now.Location())
+	}
+
+	res := commands.LogTime{Repo: e.repo, Clock: e.clock, Publisher: e.pub}.Execute(context.Background(), in)
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return errors.New("time entries can't end in the future")
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+	fmt.Printf("logged %s on %s: %s (%s in total)\n", formatTracked(d), res.Value.ID, res.Value.Title, formatTracked(res.Value.Tracked(now)))
+	return nil
+}
+
+// runReportCommand adds up tracked time:
+//
+//	todo report                      by tag
+//	todo report --by week --from 2026-10-01 --csv -o october.csv
+func runReportCommand(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	var (
+		file   = fs.String("file", "", "path to todos.json (default: store.path from the config)")
+		by     = fs.String("by", "tag", "group by tag, day or week (weeks start on dates.week_start)")
+		from   = fs.String("from", "", "only time started on or after this day")
+		to     = fs.String("to", "", "only time started before the end of this day")
+		asCSV  = fs.Bool("csv", false, "write CSV instead of a table")
+		output = fs.String("o", "", "write to this file instead of stdout")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := newEnv(*file)
+	if err != nil {
+		return err
+	}
+	now := e.clock.Now()
+	spec := queries.TimeReportSpec{By: queries.ReportBy(*by)}
+	for _, f := range []struct {
+		flag, value string
+		dst         *time.Time
+		days        int
+	}{{"from", *from, &spec.From, 0}, {"to", *to, &spec.To, 1}} {
+		if f.value == "" {
+			continue
+		}
+		d, err := todo.ResolveDueDate(f.value, now, e.cfg.Dates.WeekStart)
+		if err != nil {
+			return fmt.Errorf("--%s: cannot read %q as a date", f.flag, f.value)
+		}
+		*f.dst = d.Date().In(now.Location()).AddDate(0, 0, f.days)
+	}
+
+	q := queries.TimeReport{Repo: e.todos(), Clock: e.clock, WeekStart: e.cfg.Dates.WeekStart}
+	res := q.Execute(context.Background(), spec)
+	if errors.Is(res.Err, appErr.ErrValidation) {
+		return fmt.Errorf("--by must be tag, day or week, not %q", *by)
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	if *asCSV {
+		return writeReportCSV(out, spec.By, res.Value)
+	}
+
+	var total int64
+	for _, r := range res.Value {
+		key := r.Key
+		if key == "" {
+			key = "(untagged)"
+		}
+		fmt.Fprintf(out, "%-20s %8s  %d todos\n", key, formatTracked(time.Duration(r.Seconds)*time.Second), r.Todos)
+		total += r.Seconds
+	}
+	fmt.Fprintf(out, "%-20s %8s\n", "total", formatTracked(time.Duration(total)*time.Second))
+	return nil
+}
+
+func writeReportCSV(w io.Writer, by queries.ReportBy, rows []queries.TimeReportRow) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{string(by), "seconds", "hours", "todos"})
+	for _, r := range rows {
+		_ = cw.Write([]string{
+			r.Key,
+			strconv.FormatInt(r.Seconds, 10),
+			strconv.FormatFloat(float64(r.Seconds)/3600, 'f', 2, 64),
+			strconv.Itoa(r.Todos),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatTracked shows a duration to the minute, like 1h05m or 0h45m.
+func formatTracked(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/clock"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/config"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/gitstore"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/hooks"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/idgen"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/logging"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/webhook"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/workspace"
+)
+
+// env holds the infrastructure shared by the TUI and every subcommand.
+type env struct {
+	dir    string // directory of the opened store
+	dbPath string
+	cfg    config.Config
+
+	repo   *jsonstore.Repository
+	all    workspace.Multi // with --all: the global store and every known workspace
+	clock  ports.Clock
+	ids    ports.IDGenerator
+	pub    events.MultiPublisher
+	hooks  hooks.Runner
+	logger *log.Logger
+}
+
+// globalOpts and allWorkspaces are set from the flags given before the
+// subcommand.
+var (
+	globalOpts    config.Options
+	allWorkspaces bool
+)
+
+func loadConfig() (config.Config, error) {
+	return config.Load(globalOpts)
+}
+
+// newEnv opens the store at file (default: the configured store.path) and
+// wires the event publishers. Hooks and webhooks always come from the
+// global data directory: a workspace's .gotodo is checked in with the
+// repository it belongs to, and must not run code or send todos anywhere.
+func newEnv(file string) (*env, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	dbPath := cfg.DBPath(file)
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	e := &env{
+		dir:    dir,
+		dbPath: dbPath,
+		cfg:    cfg,
+		repo:   jsonstore.NewRepository(dbPath),
+		clock:  clock.RealClock{Location: cfg.Dates.Location},
+		ids:    idgen.RandomIDGen{},
+		logger: logging.New(),
+	}
+	if cfg.Workspace != "" && file == "" {
+		if err := workspaces(cfg).Add(cfg.Workspace); err != nil {
+			e.logger.Printf("workspace not registered: %v", err)
+		}
+	}
+	if allWorkspaces {
+		if e.all, err = openAll(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	data := filepath.Dir(cfg.Store.Global)
+	e.hooks = hooks.Runner{Dir: filepath.Join(data, "hooks"), Timeout: cfg.Hooks.Timeout, Logger: e.logger}
+	e.pub = events.MultiPublisher{
+		events.LogPublisher{L: e.logger},
+		hooks.Publisher{Runner: e.hooks, Repo: e.todos()},
+	}
+
+	endpoints, err := webhook.LoadEndpoints(filepath.Join(data, "webhooks.json"))
+	if err != nil {
+		e.logger.Printf("webhooks disabled: %v", err)
+	}
+	if len(endpoints) > 0 {
+		wh := &webhook.Publisher{
+			Endpoints: endpoints,
+			Queue:     webhook.NewQueue(filepath.Join(data, "webhook-queue.json")),
+			Repo:      e.todos(),
+			Clock:     e.clock,
+			Logger:    e.logger,
+		}
+		e.pub = append(e.pub, wh)
+		webhooks = append(webhooks, wh)
+		// retries for as long as the process lives: all along for serve
+		// and the TUI, not at all for a one-off command
+		go wh.Run(context.Background(), time.Minute)
+	}
+
+	if repo, err := gitstore.Open(dbPath); err == nil {
+		e.pub = append(e.pub, gitstore.Committer{Repo: repo, Todos: e.repo})
+	}
+
+	return e, nil
+}
+
+// todos is the store use cases work on: every workspace with --all,
+// otherwise the one that was opened.
+func (e *env) todos() workspace.Repository {
+	if e.all != nil {
+		return e.all
+	}
+	return e.repo
+}
+
+// workspaces is the list of known workspaces, kept next to the global store.
+func workspaces(cfg config.Config) workspace.Registry {
+	return workspace.Registry{Path: filepath.Join(filepath.Dir(cfg.Store.Global), "workspaces.json")}
+}
+
+// openAll opens the global store and every known workspace.
+func openAll(cfg config.Config) (workspace.Multi, error) {
+	roots, err := workspaces(cfg).Roots()
+	if err != nil {
+		return nil, err
+	}
+	all := workspace.Multi{{Repo: jsonstore.NewRepository(cfg.Store.Global)}}
+	for i, label := range workspace.Labels(roots) {
+		all = append(all, workspace.Source{Label: label, Repo: jsonstore.NewRepository(workspace.StorePath(roots[i]))})
+	}
+	return all, nil
+}
+
+// addTodo is the AddTodo use case with the configured defaults.
+func (e *env) addTodo() commands.AddTodo {
+	return commands.AddTodo{
+		Repo: e.todos(), Clock: e.clock, IDGen: e.ids, Publisher: e.pub,
+		PreAdd:    hooks.PreAdd{Runner: e.hooks},
+		Defaults:  commands.AddDefaults{Priority: e.cfg.Defaults.Priority, Tags: e.cfg.Defaults.Tags},
+		WeekStart: e.cfg.Dates.WeekStart,
+	}
+}
+
+// editTodo is the EditTodo use case with the configured week start.
+func (e *env) editTodo() commands.EditTodo {
+	return commands.EditTodo{Repo: e.todos(), Clock: e.clock, Publisher: e.pub, WeekStart: e.cfg.Dates.WeekStart}
+}
+
+// webhooks are the webhook publishers of every env opened.
+var webhooks []*webhook.Publisher
+
+// webhookGrace is how long a command waits at exit for its webhooks.
+const webhookGrace = 3 * time.Second
+
+// flushWebhooks gives deliveries still being sent up to timeout to
+// finish before the process exits; the rest stay queued for next time.
+func flushWebhooks(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, wh := range webhooks {
+		wh.Wait(ctx)
+	}
+}
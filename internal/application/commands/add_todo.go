@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"cmp"
 	"context"
+	"time"
 
 	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
 	"github.com/rojanmagar2001/gotodo/internal/application/ports"
@@ -14,13 +16,25 @@ type AddTodo struct {
 	Clock     ports.Clock
 	IDGen     ports.IDGenerator
 	Publisher ports.EventPublisher
+	PreAdd    ports.PreAddHook // optional
+	Defaults  AddDefaults      // optional
+	WeekStart time.Weekday     // for "eow" and "next mon" due dates
+}
+
+// AddDefaults fill in what AddTodoInput leaves empty. Without a default
+// priority, todos are added as low priority.
+type AddDefaults struct {
+	Priority string
+	Tags     []string
 }
 
 type AddTodoInput struct {
 	Title    string
 	Priority string
 	Tags     []string
-	DueDate  *string // YYYY-MM-DD
+	DueDate  *string // YYYY-MM-DD or relative, see todo.ResolveDueDate
+	Project  string  // "" for the inbox
+	ParentID string  // optional; must be an existing todo
 }
 
 func (uc AddTodo) Execute(ctx context.Context, in AddTodoInput) result.Result[todo.Todo] {
@@ -29,32 +43,60 @@ func (uc AddTodo) Execute(ctx context.Context, in AddTodoInput) result.Result[to
 		return result.Fail[todo.Todo](appErr.ErrValidation)
 	}
 
+	if in.Priority == "" {
+		in.Priority = cmp.Or(uc.Defaults.Priority, string(todo.PriorityLow))
+	}
+	if in.Tags == nil {
+		in.Tags = uc.Defaults.Tags
+	}
+
 	priority, err := todo.NewPriority(in.Priority)
 	if err != nil {
 		return result.Fail[todo.Todo](appErr.ErrValidation)
 	}
 
+	project, err := todo.NewProject(in.Project)
+	if err != nil {
+		return result.Fail[todo.Todo](appErr.ErrValidation)
+	}
+
 	var due *todo.DueDate
 	if in.DueDate != nil {
-		d, err := todo.ParseDueDate(*in.DueDate)
+		d, err := todo.ResolveDueDate(*in.DueDate, uc.Clock.Now(), uc.WeekStart)
 		if err != nil {
 			return result.Fail[todo.Todo](appErr.ErrValidation)
 		}
 		due = &d
 	}
 
+	parent := todo.TodoID(in.ParentID)
+	if parent != "" {
+		if _, err := uc.Repo.GetByID(ctx, parent); err != nil {
+			return result.Fail[todo.Todo](appErr.ErrValidation)
+		}
+	}
+
 	td, events, err := todo.NewTodo(todo.NewTodoParams{
 		ID:       uc.IDGen.NewTodoID(),
 		Title:    title,
 		Priority: priority,
 		Tags:     todo.NewTags(in.Tags),
 		DueDate:  due,
+		Project:  project,
+		ParentID: parent,
 		Now:      uc.Clock.Now(),
 	})
 	if err != nil {
 		return result.Fail[todo.Todo](appErr.ErrUnExpected)
 	}
 
+	if uc.PreAdd != nil {
+		td, err = uc.PreAdd.BeforeAdd(ctx, td)
+		if err != nil {
+			return result.Fail[todo.Todo](err)
+		}
+	}
+
 	if err := uc.Repo.Create(ctx, td); err != nil {
 		return result.Fail[todo.Todo](appErr.ErrUnExpected)
 	}
@@ -21,8 +21,6 @@ func (uc SoftDeleteTodo) Execute(ctx context.Context, id todo.TodoID) result.Res
 	if err != nil {
 		return result.Fail[todo.Todo](appErr.ErrNotFound)
 	}
-	before := current
-
 	updated, events, err := current.SoftDelete(uc.Clock.Now())
 	if err != nil {
 		return result.Fail[todo.Todo](appErr.MapDomainError(err))
@@ -36,35 +34,49 @@ func (uc SoftDeleteTodo) Execute(ctx context.Context, id todo.TodoID) result.Res
 	changed := len(events) > 0
 
 	if uc.Undo != nil && changed {
+		// “undelete” by restoring the snapshot, less the timer the delete stopped
+		before := updated
+		before.DeletedAt = current.DeletedAt
 		uc.Undo.Push(func(ctx context.Context) error {
-			return uc.Repo.Update(ctx, before) // “undelete” by restoring snapshot
+			return uc.Repo.Update(ctx, before)
 		})
 	}
 	return result.Ok(updated)
 }
 
+// HardDeleteTodo removes a todo for good. With a Publisher, removing a
+// todo that was not already deleted publishes TodoDeleted with its last
+// state.
 type HardDeleteTodo struct {
-	Repo ports.TodoRepository
-	Undo *UndoManager
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+	Undo      *UndoManager
 }
 
 func (uc HardDeleteTodo) Execute(ctx context.Context, id todo.TodoID) result.Result[struct{}] {
-	// For undo, capture snapshot first (optional)
+	// snapshot first, for undo and the event
 	var before *todo.Todo
-	if uc.Undo != nil {
-		td, err := uc.Repo.GetByID(ctx, id)
-		if err == nil {
-			before = &td
-		}
+	if td, err := uc.Repo.GetByID(ctx, id); err == nil {
+		before = &td
 	}
 
 	if err := uc.Repo.HardDelete(ctx, id); err != nil {
 		return result.Fail[struct{}](appErr.ErrUnExpected)
 	}
 
+	if uc.Publisher != nil && before != nil && before.DeletedAt == nil {
+		_ = uc.Publisher.Publish(ctx, []todo.Event{todo.TodoDeleted{ID: id, Todo: *before, OccurredAt: uc.Clock.Now()}})
+	}
+
 	if uc.Undo != nil && before != nil {
+		restored := *before
+		if _, ok := restored.Timer(); ok {
+			// another timer may be running by the time this is undone
+			restored, _, _ = restored.StopTimer(uc.Clock.Now())
+		}
 		uc.Undo.Push(func(ctx context.Context) error {
-			return uc.Repo.Create(ctx, *before)
+			return uc.Repo.Create(ctx, restored)
 		})
 	}
 
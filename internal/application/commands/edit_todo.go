@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
 	"github.com/rojanmagar2001/gotodo/internal/application/ports"
@@ -14,6 +15,7 @@ type EditTodo struct {
 	Clock     ports.Clock
 	Publisher ports.EventPublisher
 	Undo      *UndoManager
+	WeekStart time.Weekday // for "eow" and "next mon" due dates
 }
 
 type EditTodoInput struct {
@@ -21,7 +23,16 @@ type EditTodoInput struct {
 	Title    *string
 	Priority *string
 	Tags     *[]string
-	DueDate  **string
+	DueDate  **string // nil leaves it, &nil clears it; see todo.ResolveDueDate
+	Project  *string  // "" moves to the inbox
+
+	// Scheduled is when work can start, read like DueDate; moving it
+	// later snoozes the todo.
+	Scheduled **string
+	// RemindAt is read like DueDate too, but needs a time: "fri 9am".
+	RemindAt **string
+	// Estimate is "90m", "2h" or "3pt"; nil leaves it, "" clears it.
+	Estimate *string
 }
 
 func (uc EditTodo) Execute(ctx context.Context, in EditTodoInput) result.Result[todo.Todo] {
@@ -69,7 +80,7 @@ func (uc EditTodo) Execute(ctx context.Context, in EditTodoInput) result.Result[
 			current.DueDate = nil
 			current.UpdatedAt = now
 		} else {
-			d, err := todo.ParseDueDate(**in.DueDate)
+			d, err := todo.ResolveDueDate(**in.DueDate, now, uc.WeekStart)
 			if err != nil {
 				return result.Fail[todo.Todo](appErr.ErrValidation)
 			}
@@ -78,6 +89,70 @@ func (uc EditTodo) Execute(ctx context.Context, in EditTodoInput) result.Result[
 		}
 	}
 
+	if in.Project != nil {
+		p, err := todo.NewProject(*in.Project)
+		if err != nil {
+			return result.Fail[todo.Todo](appErr.ErrValidation)
+		}
+		updated, ev, err := current.MoveTo(p, now)
+		if err != nil {
+			return result.Fail[todo.Todo](appErr.MapDomainError(err))
+		}
+		current = updated
+		events = append(events, ev...)
+	}
+
+	if in.Scheduled != nil {
+		var d *todo.DueDate
+		if *in.Scheduled != nil {
+			resolved, err := todo.ResolveDueDate(**in.Scheduled, now, uc.WeekStart)
+			if err != nil {
+				return result.Fail[todo.Todo](appErr.ErrValidation)
+			}
+			d = &resolved
+		}
+		updated, ev, err := current.Schedule(d, now)
+		if err != nil {
+			return result.Fail[todo.Todo](appErr.MapDomainError(err))
+		}
+		current = updated
+		events = append(events, ev...)
+	}
+
+	if in.RemindAt != nil {
+		var d *todo.DueDate
+		if *in.RemindAt != nil {
+			resolved, err := todo.ResolveDueDate(**in.RemindAt, now, uc.WeekStart)
+			if err != nil {
+				return result.Fail[todo.Todo](appErr.ErrValidation)
+			}
+			d = &resolved
+		}
+		updated, ev, err := current.SetReminder(d, now)
+		if err != nil {
+			return result.Fail[todo.Todo](appErr.MapDomainError(err))
+		}
+		current = updated
+		events = append(events, ev...)
+	}
+
+	if in.Estimate != nil {
+		var e *todo.Estimate
+		if *in.Estimate != "" {
+			parsed, err := todo.ParseEstimate(*in.Estimate)
+			if err != nil {
+				return result.Fail[todo.Todo](appErr.ErrValidation)
+			}
+			e = &parsed
+		}
+		updated, ev, err := current.SetEstimate(e, now)
+		if err != nil {
+			return result.Fail[todo.Todo](appErr.MapDomainError(err))
+		}
+		current = updated
+		events = append(events, ev...)
+	}
+
 	if err := uc.Repo.Update(ctx, current); err != nil {
 		return result.Fail[todo.Todo](appErr.ErrUnExpected)
 	}
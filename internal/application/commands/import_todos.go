@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// ImportField is a part of a todo that an import format carries.
+type ImportField string
+
+const (
+	ImportTitle     ImportField = "title"
+	ImportStatus    ImportField = "status" // done, archived and deleted
+	ImportPriority  ImportField = "priority"
+	ImportTags      ImportField = "tags"
+	ImportDueDate   ImportField = "due"
+	ImportScheduled ImportField = "scheduled"
+	ImportParent    ImportField = "parent"
+	ImportMeta      ImportField = "meta" // keys present are set, others kept
+)
+
+// ImportTodos stores todos decoded by an import codec. Todos whose ID
+// already exists get only the fields the format carries, so re-importing
+// an export changes nothing and leaves what the format cannot hold (the
+// project, estimate, time entries...) alone. Deleted todos stay deleted.
+type ImportTodos struct {
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+type ImportTodosInput struct {
+	Todos []todo.Todo
+	// Fields are what the format carries. Nil stores existing todos as
+	// given, for callers that already merged them with the store.
+	Fields []ImportField
+}
+
+type ImportReport struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+func (uc ImportTodos) Execute(ctx context.Context, in ImportTodosInput) result.Result[ImportReport] {
+	var (
+		rep    ImportReport
+		events []todo.Event
+	)
+	now := uc.Clock.Now()
+
+	for _, td := range in.Todos {
+		if !td.ID.Valid() {
+			return result.Fail[ImportReport](appErr.ErrValidation)
+		}
+
+		cur, err := uc.Repo.GetByID(ctx, td.ID)
+		switch {
+		case err == nil && in.Fields == nil:
+			if err := uc.Repo.Update(ctx, td); err != nil {
+				return result.Fail[ImportReport](appErr.ErrUnExpected)
+			}
+			rep.Updated++
+		case err == nil:
+			next, evs, changed, err := mergeImported(cur, td, in.Fields, now)
+			if err != nil {
+				return result.Fail[ImportReport](appErr.MapDomainError(err))
+			}
+			if !changed {
+				rep.Unchanged++
+				continue
+			}
+			if err := uc.Repo.Update(ctx, next); err != nil {
+				return result.Fail[ImportReport](appErr.ErrUnExpected)
+			}
+			rep.Updated++
+			events = append(events, evs...)
+		case errors.Is(err, appErr.ErrNotFound):
+			if err := uc.Repo.Create(ctx, td); err != nil {
+				return result.Fail[ImportReport](appErr.ErrUnExpected)
+			}
+			rep.Created++
+			events = append(events, todo.TodoCreated{ID: td.ID, OccurredAt: now})
+		default:
+			return result.Fail[ImportReport](appErr.ErrUnExpected)
+		}
+	}
+
+	_ = uc.Publisher.Publish(ctx, events)
+
+	return result.Ok(rep)
+}
+
+// mergeImported applies the given fields of in onto cur and reports
+// whether anything changed.
+func mergeImported(cur, in todo.Todo, fields []ImportField, now time.Time) (todo.Todo, []todo.Event, bool, error) {
+	if cur.DeletedAt != nil {
+		return cur, nil, false, nil
+	}
+
+	var (
+		events  []todo.Event
+		changed bool
+	)
+	apply := func(t todo.Todo, evs []todo.Event, err error) error {
+		if err != nil {
+			return err
+		}
+		cur = t
+		events = append(events, evs...)
+		changed = changed || len(evs) > 0
+		return nil
+	}
+	set := func(differs bool, fn func()) {
+		if differs {
+			fn()
+			cur.UpdatedAt = now
+			changed = true
+		}
+	}
+
+	for _, f := range fields {
+		var err error
+		switch f {
+		case ImportTitle:
+			err = apply(cur.ChangeTitle(in.Title, now))
+		case ImportPriority:
+			set(cur.Priority != in.Priority, func() { cur.Priority = in.Priority })
+		case ImportTags:
+			set(!slices.Equal(cur.Tags, in.Tags), func() { cur.Tags = in.Tags })
+		case ImportDueDate:
+			set(!sameDate(cur.DueDate, in.DueDate), func() { cur.DueDate = in.DueDate })
+		case ImportScheduled:
+			err = apply(cur.Schedule(in.Scheduled, now))
+		case ImportParent:
+			set(cur.ParentID != in.ParentID, func() { cur.ParentID = in.ParentID })
+		case ImportMeta:
+			for _, k := range slices.Sorted(maps.Keys(in.Meta)) {
+				v, ok := cur.Meta[k]
+				set(!ok || v != in.Meta[k], func() {
+					cur.Meta = maps.Clone(cur.Meta)
+					if cur.Meta == nil {
+						cur.Meta = map[string]string{}
+					}
+					cur.Meta[k] = in.Meta[k]
+				})
+			}
+		}
+		if err != nil {
+			return cur, nil, false, err
+		}
+	}
+
+	// status last: completing may stop a timer, deleting snapshots the todo
+	if !slices.Contains(fields, ImportStatus) {
+		return cur, events, changed, nil
+	}
+	if in.DeletedAt != nil {
+		if err := apply(cur.SoftDelete(now)); err != nil {
+			return cur, nil, false, err
+		}
+		return cur, events, changed, nil
+	}
+
+	// formats that cannot tell done from archived import archived todos
+	// as done; those stay archived
+	from, to := cur.Status, in.Status
+	if from == to || (from == todo.StatusArchived && to == todo.StatusDone) {
+		return cur, events, changed, nil
+	}
+	var err error
+	switch {
+	case to == todo.StatusActive && from == todo.StatusDone:
+		err = apply(cur.Reopen(now))
+	case to == todo.StatusActive:
+		err = apply(cur.Restore(now))
+	default: // done or archived
+		if from == todo.StatusActive {
+			if err = apply(cur.Complete(now)); err == nil && in.CompletedAt != nil {
+				cur.CompletedAt = in.CompletedAt
+			}
+		}
+		if err == nil && to == todo.StatusArchived {
+			if err = apply(cur.Archive(now)); err == nil && in.ArchivedAt != nil {
+				cur.ArchivedAt = in.ArchivedAt
+			}
+		}
+	}
+	if err != nil {
+		return cur, nil, false, err
+	}
+	return cur, events, changed, nil
+}
+
+func sameDate(a, b *todo.DueDate) bool {
+	return a == b || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type mapRepo map[todo.TodoID]todo.Todo
+
+func (r mapRepo) Create(_ context.Context, t todo.Todo) error { r[t.ID] = t; return nil }
+func (r mapRepo) Update(_ context.Context, t todo.Todo) error { r[t.ID] = t; return nil }
+func (r mapRepo) GetByID(_ context.Context, id todo.TodoID) (todo.Todo, error) {
+	t, ok := r[id]
+	if !ok {
+		return todo.Todo{}, appErr.ErrNotFound
+	}
+	return t, nil
+}
+func (r mapRepo) List(context.Context, ports.ListSpec) ([]todo.Todo, error) { return nil, nil }
+func (r mapRepo) SoftDelete(context.Context, todo.TodoID) error             { return nil }
+func (r mapRepo) HardDelete(context.Context, todo.TodoID) error             { return nil }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+type recorder struct{ events []todo.Event }
+
+func (r *recorder) Publish(_ context.Context, evs []todo.Event) error {
+	r.events = append(r.events, evs...)
+	return nil
+}
+
+func TestImportTodos_MergesCarriedFields(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	base := now.AddDate(0, 0, -3)
+
+	stored, _, _ := todo.NewTodo(todo.NewTodoParams{ID: "a", Title: "Write report", Project: "work", Now: base})
+	stored, _, _ = stored.SetEstimate(&todo.Estimate{Value: 120, Unit: todo.EstimateMinutes}, base)
+	stored, _, _ = stored.LogTime(base, time.Hour, now)
+
+	// what a lossy format gives back: no project, estimate or time entries
+	in, _, _ := todo.NewTodo(todo.NewTodoParams{ID: "a", Title: "Write the report", Priority: todo.PriorityHigh, Now: base})
+	in, _, _ = in.Complete(base.Add(time.Hour))
+
+	repo, pub := mapRepo{"a": stored}, &recorder{}
+	uc := ImportTodos{Repo: repo, Clock: fixedClock(now), Publisher: pub}
+	fields := []ImportField{ImportTitle, ImportStatus, ImportPriority, ImportTags, ImportDueDate}
+
+	res := uc.Execute(context.Background(), ImportTodosInput{Todos: []todo.Todo{in}, Fields: fields})
+	if res.Err != nil || res.Value.Updated != 1 {
+		t.Fatalf("report=%+v err=%v", res.Value, res.Err)
+	}
+	got := repo["a"]
+	if got.Project != "work" || got.Estimate == nil || len(got.TimeEntries) != 1 {
+		t.Fatalf("lost fields the format does not carry: %+v", got)
+	}
+	if got.Title != "Write the report" || got.Priority != todo.PriorityHigh || got.Status != todo.StatusDone {
+		t.Fatalf("got=%+v", got)
+	}
+	if !got.CompletedAt.Equal(base.Add(time.Hour)) {
+		t.Fatalf("completedAt=%v", got.CompletedAt)
+	}
+	var names []string
+	for _, e := range pub.events {
+		names = append(names, todo.EventName(e))
+	}
+	if len(names) != 2 || names[0] != "todo.title_changed" || names[1] != "todo.completed" {
+		t.Fatalf("events=%v", names)
+	}
+
+	res = uc.Execute(context.Background(), ImportTodosInput{Todos: []todo.Todo{in}, Fields: fields})
+	if res.Err != nil || res.Value.Unchanged != 1 || res.Value.Updated != 0 {
+		t.Fatalf("re-import report=%+v err=%v", res.Value, res.Err)
+	}
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"slices"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// AddProject registers a project so it can be listed before it has todos.
+type AddProject struct {
+	Projects ports.ProjectRepository
+}
+
+func (uc AddProject) Execute(ctx context.Context, name string) result.Result[todo.Project] {
+	p, err := todo.NewProject(name)
+	if err != nil || p.IsInbox() {
+		return result.Fail[todo.Project](appErr.ErrValidation)
+	}
+	if err := uc.Projects.AddProject(ctx, p); err != nil {
+		return result.Fail[todo.Project](appErr.ErrUnExpected)
+	}
+	return result.Ok(p)
+}
+
+// RenameProject moves every todo of From to To and re-registers the name.
+// Renaming onto an existing project merges the two.
+type RenameProject struct {
+	Repo      ports.TodoRepository
+	Projects  ports.ProjectRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+type RenameProjectInput struct {
+	From, To string
+}
+
+// Execute returns the number of todos moved.
+func (uc RenameProject) Execute(ctx context.Context, in RenameProjectInput) result.Result[int] {
+	from, err := todo.NewProject(in.From)
+	if err != nil || from.IsInbox() {
+		return result.Fail[int](appErr.ErrValidation)
+	}
+	to, err := todo.NewProject(in.To)
+	if err != nil || to.IsInbox() {
+		return result.Fail[int](appErr.ErrValidation)
+	}
+	known, err := uc.Projects.ListProjects(ctx)
+	if err != nil {
+		return result.Fail[int](appErr.ErrUnExpected)
+	}
+	if !slices.Contains(known, from) {
+		return result.Fail[int](appErr.ErrNotFound)
+	}
+
+	if err := uc.Projects.AddProject(ctx, to); err != nil {
+		return result.Fail[int](appErr.ErrUnExpected)
+	}
+	n, err := moveAll(ctx, uc.Repo, uc.Clock, uc.Publisher, from, to)
+	if err != nil {
+		return result.Fail[int](err)
+	}
+	if err := uc.Projects.RemoveProject(ctx, from); err != nil {
+		return result.Fail[int](appErr.ErrUnExpected)
+	}
+	return result.Ok(n)
+}
+
+// RemoveProject unregisters a project. A project that still has todos is
+// only removed when they are moved elsewhere (MoveTo, "" for the inbox).
+type RemoveProject struct {
+	Repo      ports.TodoRepository
+	Projects  ports.ProjectRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+type RemoveProjectInput struct {
+	Name   string
+	MoveTo *string
+}
+
+// Execute returns the number of todos moved.
+func (uc RemoveProject) Execute(ctx context.Context, in RemoveProjectInput) result.Result[int] {
+	p, err := todo.NewProject(in.Name)
+	if err != nil || p.IsInbox() {
+		return result.Fail[int](appErr.ErrValidation)
+	}
+
+	n := 0
+	if in.MoveTo != nil {
+		to, err := todo.NewProject(*in.MoveTo)
+		if err != nil || to == p {
+			return result.Fail[int](appErr.ErrValidation)
+		}
+		if n, err = moveAll(ctx, uc.Repo, uc.Clock, uc.Publisher, p, to); err != nil {
+			return result.Fail[int](err)
+		}
+	} else {
+		left, err := uc.Repo.List(ctx, ports.ListSpec{Project: &p, IncludeUnstarted: true})
+		if err != nil {
+			return result.Fail[int](appErr.ErrUnExpected)
+		}
+		if len(left) > 0 {
+			return result.Fail[int](appErr.ErrConflict)
+		}
+	}
+
+	if err := uc.Projects.RemoveProject(ctx, p); err != nil {
+		return result.Fail[int](appErr.ErrUnExpected)
+	}
+	return result.Ok(n)
+}
+
+func moveAll(ctx context.Context, repo ports.TodoRepository, clock ports.Clock, pub ports.EventPublisher, from, to todo.Project) (int, error) {
+	tds, err := repo.List(ctx, ports.ListSpec{Project: &from, IncludeUnstarted: true})
+	if err != nil {
+		return 0, appErr.ErrUnExpected
+	}
+	now := clock.Now()
+	var events []todo.Event
+	for _, td := range tds {
+		moved, ev, err := td.MoveTo(to, now)
+		if err != nil {
+			return 0, appErr.MapDomainError(err)
+		}
+		if err := repo.Update(ctx, moved); err != nil {
+			return 0, appErr.ErrUnExpected
+		}
+		events = append(events, ev...)
+	}
+	_ = pub.Publish(ctx, events)
+	return len(tds), nil
+}
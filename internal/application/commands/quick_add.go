@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QuickKind says what a word of a quick-add line was read as.
+type QuickKind string
+
+const (
+	QuickTitle    QuickKind = "title"
+	QuickTag      QuickKind = "tag"      // #tag
+	QuickPriority QuickKind = "priority" // !high, !h
+	QuickDue      QuickKind = "due"      // due:fri, due:"next fri"
+	QuickParent   QuickKind = "parent"   // ^id
+	QuickProject  QuickKind = "project"  // project:name
+)
+
+// QuickToken is one word of a quick-add line; Start and End are byte
+// offsets into the line, for highlighting.
+type QuickToken struct {
+	Kind       QuickKind
+	Start, End int
+	Value      string // the tag, priority, date, parent or project; the text for titles
+}
+
+var quickPriorities = map[string]string{
+	"high": "high", "h": "high",
+	"medium": "medium", "med": "medium", "m": "medium",
+	"low": "low", "l": "low",
+}
+
+// ParseQuickAdd reads a one-line todo like
+//
+//	Fix login bug #auth #backend !high due:fri ^t42 project:web
+//
+// Words that are not recognized stay in the title; a word starting with
+// a backslash is always title text, so `\#1` is a literal "#1". The due
+// date is left for AddTodo to resolve. The last priority, due date,
+// parent and project win.
+func ParseQuickAdd(line string) (AddTodoInput, []QuickToken) {
+	var (
+		in    AddTodoInput
+		title []string
+		toks  []QuickToken
+	)
+	for _, w := range splitQuick(line) {
+		tok := QuickToken{Kind: QuickTitle, Start: w.start, End: w.end, Value: w.text}
+		switch s := w.text; {
+		case w.quoted:
+			// only due:"..." is quoted; anything else is text
+		case strings.HasPrefix(s, `\`):
+			tok.Value = s[1:]
+		case len(s) > 1 && s[0] == '#':
+			tok.Kind, tok.Value = QuickTag, s[1:]
+			in.Tags = append(in.Tags, tok.Value)
+		case len(s) > 1 && s[0] == '!' && quickPriorities[strings.ToLower(s[1:])] != "":
+			tok.Kind, tok.Value = QuickPriority, quickPriorities[strings.ToLower(s[1:])]
+			in.Priority = tok.Value
+		case len(s) > 1 && s[0] == '^':
+			tok.Kind, tok.Value = QuickParent, s[1:]
+			in.ParentID = tok.Value
+		default:
+			key, val, ok := strings.Cut(s, ":")
+			if !ok || val == "" {
+				break
+			}
+			switch strings.ToLower(key) {
+			case "due":
+				tok.Kind, tok.Value = QuickDue, unquote(val)
+				in.DueDate = &tok.Value
+			case "project":
+				tok.Kind, tok.Value = QuickProject, val
+				in.Project = val
+			}
+		}
+		if tok.Kind == QuickTitle && tok.Value != "" {
+			title = append(title, tok.Value)
+		}
+		toks = append(toks, tok)
+	}
+	in.Title = strings.Join(title, " ")
+	return in, toks
+}
+
+type quickWord struct {
+	text       string
+	start, end int
+	quoted     bool // the word starts with a quote
+}
+
+// splitQuick splits line on spaces, keeping double-quoted parts (as in
+// due:"next fri") inside their word.
+func splitQuick(line string) []quickWord {
+	var out []quickWord
+	start, inQuote := -1, false
+	for i, r := range line {
+		switch {
+		case r == '"' && start >= 0 && i > start && line[i-1] != '\\':
+			inQuote = !inQuote
+		case r == '"' && start < 0:
+			start, inQuote = i, true
+		case unicode.IsSpace(r) && !inQuote:
+			if start >= 0 {
+				out = append(out, quickWord{text: line[start:i], start: start, end: i})
+				start = -1
+			}
+		case start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		out = append(out, quickWord{text: line[start:], start: start, end: len(line)})
+	}
+	for i := range out {
+		out[i].quoted = strings.HasPrefix(out[i].text, `"`)
+	}
+	return out
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	line := `Fix login bug #auth #backend !high due:fri ^t42 project:web`
+	in, toks := ParseQuickAdd(line)
+
+	if in.Title != "Fix login bug" || in.Priority != "high" || in.ParentID != "t42" || in.Project != "web" {
+		t.Fatalf("in=%+v", in)
+	}
+	if !slices.Equal(in.Tags, []string{"auth", "backend"}) || in.DueDate == nil || *in.DueDate != "fri" {
+		t.Fatalf("tags=%q due=%v", in.Tags, in.DueDate)
+	}
+
+	var kinds []QuickKind
+	for _, tok := range toks {
+		kinds = append(kinds, tok.Kind)
+	}
+	want := []QuickKind{QuickTitle, QuickTitle, QuickTitle, QuickTag, QuickTag, QuickPriority, QuickDue, QuickParent, QuickProject}
+	if !slices.Equal(kinds, want) {
+		t.Fatalf("kinds=%q want=%q", kinds, want)
+	}
+	if tok := toks[6]; line[tok.Start:tok.End] != "due:fri" {
+		t.Fatalf("due token spans %q", line[tok.Start:tok.End])
+	}
+}
+
+func TestParseQuickAdd_Escapes(t *testing.T) {
+	tests := []struct {
+		line, title, due string
+		tags             []string
+	}{
+		{`Reply to \#123 !urgent #mail`, "Reply to #123 !urgent", "", []string{"mail"}},
+		{`Ship it \due:now # ! ^`, "Ship it due:now # ! ^", "", nil},
+		{`Plan trip  due:"next fri"  C#`, "Plan trip C#", "next fri", nil},
+		{`"Quoted #not-a-tag" done`, `"Quoted #not-a-tag" done`, "", nil},
+		{`Pay rent !l !m`, "Pay rent", "", nil},
+	}
+	for _, tt := range tests {
+		in, _ := ParseQuickAdd(tt.line)
+		due := ""
+		if in.DueDate != nil {
+			due = *in.DueDate
+		}
+		if in.Title != tt.title || due != tt.due || !slices.Equal(in.Tags, tt.tags) {
+			t.Fatalf("ParseQuickAdd(%q) title=%q due=%q tags=%q", tt.line, in.Title, due, in.Tags)
+		}
+	}
+	if in, _ := ParseQuickAdd("Pay rent !l !m"); in.Priority != "medium" {
+		t.Fatalf("priority=%q, want the last one", in.Priority)
+	}
+}
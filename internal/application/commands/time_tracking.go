@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// StartTimer starts tracking time on a todo. Only one timer runs at a
+// time: a timer running on another todo is stopped first.
+type StartTimer struct {
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+type StartTimerOutput struct {
+	Started todo.Todo
+	Stopped *todo.Todo // the todo whose timer was stopped, if any
+}
+
+func (uc StartTimer) Execute(ctx context.Context, id todo.TodoID) result.Result[StartTimerOutput] {
+	td, err := uc.Repo.GetByID(ctx, id)
+	if err != nil {
+		return result.Fail[StartTimerOutput](appErr.ErrNotFound)
+	}
+	now := uc.Clock.Now()
+	started, events, err := td.StartTimer(now)
+	if err != nil {
+		return result.Fail[StartTimerOutput](appErr.MapDomainError(err))
+	}
+
+	var out StartTimerOutput
+	running, err := runningTimer(ctx, uc.Repo)
+	if err != nil {
+		return result.Fail[StartTimerOutput](appErr.ErrUnExpected)
+	}
+	if running != nil {
+		stopped, ev, _ := running.StopTimer(now)
+		if err := uc.Repo.Update(ctx, stopped); err != nil {
+			return result.Fail[StartTimerOutput](appErr.ErrUnExpected)
+		}
+		out.Stopped = &stopped
+		events = append(ev, events...)
+	}
+
+	if err := uc.Repo.Update(ctx, started); err != nil {
+		return result.Fail[StartTimerOutput](appErr.ErrUnExpected)
+	}
+	_ = uc.Publisher.Publish(ctx, events)
+
+	out.Started = started
+	return result.Ok(out)
+}
+
+// StopTimer stops the running timer, whichever todo it is on.
+type StopTimer struct {
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+// Execute fails with ErrNotFound when no timer is running.
+func (uc StopTimer) Execute(ctx context.Context) result.Result[todo.Todo] {
+	running, err := runningTimer(ctx, uc.Repo)
+	if err != nil {
+		return result.Fail[todo.Todo](appErr.ErrUnExpected)
+	}
+	if running == nil {
+		return result.Fail[todo.Todo](appErr.ErrNotFound)
+	}
+	stopped, events, err := running.StopTimer(uc.Clock.Now())
+	if err != nil {
+		return result.Fail[todo.Todo](appErr.MapDomainError(err))
+	}
+	if err := uc.Repo.Update(ctx, stopped); err != nil {
+		return result.Fail[todo.Todo](appErr.ErrUnExpected)
+	}
+	_ = uc.Publisher.Publish(ctx, events)
+	return result.Ok(stopped)
+}
+
+// LogTime records time spent on a todo after the fact.
+type LogTime struct {
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	Publisher ports.EventPublisher
+}
+
+type LogTimeInput struct {
+	ID       todo.TodoID
+	Duration time.Duration
+	Start    time.Time // zero: the entry ends now
+}
+
+func (uc LogTime) Execute(ctx context.Context, in LogTimeInput) result.Result[todo.Todo] {
+	td, err := uc.Repo.GetByID(ctx, in.ID)
+	if err != nil {
+		return result.Fail[todo.Todo](appErr.ErrNotFound)
+	}
+	now := uc.Clock.Now()
+	if in.Start.IsZero() {
+		in.Start = now.Add(-in.Duration)
+	}
+	updated, events, err := td.LogTime(in.Start, in.Duration, now)
+	if err != nil {
+		return result.Fail[todo.Todo](appErr.MapDomainError(err))
+	}
+	if err := uc.Repo.Update(ctx, updated); err != nil {
+		return result.Fail[todo.Todo](appErr.ErrUnExpected)
+	}
+	_ = uc.Publisher.Publish(ctx, events)
+	return result.Ok(updated)
+}
+
+// runningTimer finds the todo with the running timer, nil when there is
+// none.
+func runningTimer(ctx context.Context, repo ports.TodoRepository) (*todo.Todo, error) {
+	tds, err := repo.List(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tds {
+		if _, ok := t.Timer(); ok {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
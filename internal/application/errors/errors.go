@@ -7,4 +7,5 @@ var (
 	ErrConflict   = errors.New("conflict")
 	ErrValidation = errors.New("validation failed")
 	ErrUnExpected = errors.New("unxpected error")
+	ErrVetoed     = errors.New("vetoed by hook")
 )
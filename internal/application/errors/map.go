@@ -15,9 +15,16 @@ func MapDomainError(err error) error {
 	case errors.Is(err, domain.ErrInvalidTitle),
 		errors.Is(err, domain.ErrInvalidPriority),
 		errors.Is(err, domain.ErrInvalidDueDate),
+		errors.Is(err, domain.ErrInvalidReminder),
+		errors.Is(err, domain.ErrInvalidEstimate),
+		errors.Is(err, domain.ErrInvalidProject),
 		errors.Is(err, domain.ErrInvalidTransition),
-		errors.Is(err, domain.ErrDeletedTodo):
+		errors.Is(err, domain.ErrDeletedTodo),
+		errors.Is(err, domain.ErrInvalidTimeEntry):
 		return ErrValidation
+	case errors.Is(err, domain.ErrTimerRunning),
+		errors.Is(err, domain.ErrNoTimer):
+		return ErrConflict
 	default:
 		return ErrUnExpected
 	}
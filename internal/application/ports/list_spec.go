@@ -1,11 +1,16 @@
 package ports
 
-import "github.com/rojanmagar2001/gotodo/internal/domain/todo"
+import (
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
 
 type ListSpec struct {
 	// filters
-	Status *todo.Status
-	Tag    *string
+	Status  *todo.Status
+	Tag     *string
+	Project *todo.Project // &"" is the inbox
 
 	// search
 	Search *string // full-text-isj: title contains (case-insensitive)
@@ -20,6 +25,11 @@ type ListSpec struct {
 
 	// include soft-deleted?
 	IncludeDeleted bool
+
+	// include todos scheduled to start after Now? A zero Now is the time
+	// of the call, in UTC.
+	IncludeUnstarted bool
+	Now              time.Time
 }
 
 type (
@@ -37,3 +47,16 @@ const (
 	OrderAsc  SortOrder = "asc"
 	OrderDesc SortOrder = "desc"
 )
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByCreated, SortByDueDate, SortByPriority, SortByTitle, SortByUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+func (o SortOrder) Valid() bool {
+	return o == OrderAsc || o == OrderDesc
+}
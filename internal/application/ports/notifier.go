@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Reminder is a todo whose reminder has come up.
+type Reminder struct {
+	Todo todo.Todo
+	At   time.Time // when it was set for
+	Late bool      // fired well after At, e.g. after the machine slept
+}
+
+// Notifier tells the user about a reminder.
+type Notifier interface {
+	Notify(ctx context.Context, r Reminder) error
+}
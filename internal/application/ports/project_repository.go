@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// ProjectRepository keeps the names of projects, so a project can exist
+// before it has todos and be listed after its last todo is done.
+type ProjectRepository interface {
+	// ListProjects returns registered projects and every project a todo
+	// is in, sorted, without the inbox.
+	ListProjects(ctx context.Context) ([]todo.Project, error)
+	AddProject(ctx context.Context, p todo.Project) error
+	RemoveProject(ctx context.Context, p todo.Project) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// PreAddHook gets a chance to veto or rewrite a todo before it is stored.
+type PreAddHook interface {
+	BeforeAdd(ctx context.Context, t todo.Todo) (todo.Todo, error)
+}
@@ -1,24 +1,41 @@
 package queries
 
 import (
+	"maps"
 	"time"
 
 	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
 )
 
 type TodoDTO struct {
-	ID       string
-	Title    string
-	Status   string
-	Priority string
-	Tags     []string
-	DueDate  *string
-
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	CompletedAt *time.Time
-	ArchivedAt  *time.Time
-	DeletedAt   *time.Time
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags"`
+	DueDate  *string  `json:"dueDate"`
+	ParentID string   `json:"parentId,omitempty"`
+	Project  string   `json:"project,omitempty"`
+
+	Scheduled *string `json:"scheduled,omitempty"`
+	RemindAt  *string `json:"remindAt,omitempty"`
+	Estimate  string  `json:"estimate,omitempty"`
+
+	TimeEntries []TimeEntryDTO `json:"timeEntries,omitempty"`
+
+	Meta map[string]string `json:"meta,omitempty"`
+
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ArchivedAt  *time.Time `json:"archivedAt"`
+	DeletedAt   *time.Time `json:"deletedAt"`
+}
+
+// TimeEntryDTO is time tracked on a todo; End is nil while the timer runs.
+type TimeEntryDTO struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
 }
 
 func ToDTO(t todo.Todo) TodoDTO {
@@ -28,6 +45,26 @@ func ToDTO(t todo.Todo) TodoDTO {
 		due = &s
 	}
 
+	var scheduled, remindAt *string
+	if t.Scheduled != nil {
+		s := t.Scheduled.String()
+		scheduled = &s
+	}
+	if t.RemindAt != nil {
+		s := t.RemindAt.String()
+		remindAt = &s
+	}
+
+	var estimate string
+	if t.Estimate != nil {
+		estimate = t.Estimate.String()
+	}
+
+	var entries []TimeEntryDTO
+	for _, e := range t.TimeEntries {
+		entries = append(entries, TimeEntryDTO{Start: e.Start.UTC(), End: utc(e.End)})
+	}
+
 	// copy tags to avoid sharing underlying slice
 	tags := make([]string, len(t.Tags))
 	copy(tags, t.Tags)
@@ -39,11 +76,29 @@ func ToDTO(t todo.Todo) TodoDTO {
 		Priority: t.Priority.String(),
 		Tags:     tags,
 		DueDate:  due,
+		ParentID: t.ParentID.String(),
+		Project:  t.Project.String(),
+		Meta:     maps.Clone(t.Meta),
+
+		Scheduled: scheduled,
+		RemindAt:  remindAt,
+		Estimate:  estimate,
+
+		TimeEntries: entries,
+
+		CreatedAt:   t.CreatedAt.UTC(),
+		UpdatedAt:   t.UpdatedAt.UTC(),
+		CompletedAt: utc(t.CompletedAt),
+		ArchivedAt:  utc(t.ArchivedAt),
+		DeletedAt:   utc(t.DeletedAt),
+	}
+}
 
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
-		CompletedAt: t.CompletedAt,
-		ArchivedAt:  t.ArchivedAt,
-		DeletedAt:   t.DeletedAt,
+// utc keeps timestamps in UTC whatever zone the clock is in.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
 	}
+	u := t.UTC()
+	return &u
 }
@@ -0,0 +1,27 @@
+package queries
+
+import (
+	"context"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// ExportTodos returns domain todos (not DTOs) for export codecs, which
+// need the typed fields to map them onto other formats.
+type ExportTodos struct {
+	Repo ports.TodoRepository
+}
+
+func (q ExportTodos) Execute(ctx context.Context, spec ports.ListSpec) result.Result[[]todo.Todo] {
+	if spec.SortBy == "" {
+		spec.SortBy, spec.SortOrder = ports.SortByCreated, ports.OrderAsc
+	}
+	tds, err := q.Repo.List(ctx, spec)
+	if err != nil {
+		return result.Fail[[]todo.Todo](appErr.ErrUnExpected)
+	}
+	return result.Ok(tds)
+}
@@ -9,10 +9,14 @@ import (
 )
 
 type ListTodos struct {
-	Repo ports.TodoRepository
+	Repo  ports.TodoRepository
+	Clock ports.Clock // optional; when set, its now decides what has started
 }
 
 func (q ListTodos) Execute(ctx context.Context, spec ports.ListSpec) result.Result[[]TodoDTO] {
+	if q.Clock != nil && spec.Now.IsZero() {
+		spec.Now = q.Clock.Now()
+	}
 	tds, err := q.Repo.List(ctx, spec)
 	if err != nil {
 		return result.Fail[[]TodoDTO](appErr.ErrUnExpected)
@@ -121,3 +121,32 @@ func TestListTodos_ExcludeDeletedByDefault(t *testing.T) {
 		t.Fatalf("len=%d want=2", len(res2.Value))
 	}
 }
+
+func TestListTodos_HidesUnstarted(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	schedule := func(td todo.Todo, iso string) todo.Todo {
+		d, _ := todo.ParseDueDate(iso)
+		td.Scheduled = &d
+		return td
+	}
+	td1 := mkTodo(t, "1", "Buy milk", todo.StatusActive, todo.PriorityLow, nil, nil, now)
+	td2 := schedule(mkTodo(t, "2", "File taxes", todo.StatusActive, todo.PriorityLow, nil, nil, now.Add(time.Minute)), "2026-03-10")
+	td3 := schedule(mkTodo(t, "3", "Renew passport", todo.StatusActive, todo.PriorityLow, nil, nil, now.Add(2*time.Minute)), "2026-03-10T09:30")
+
+	q := ListTodos{Repo: newInMemoryRepo(td1, td2, td3), Clock: fakeClock{t: now}}
+
+	res := q.Execute(ctx, ports.ListSpec{SortBy: ports.SortByCreated, SortOrder: ports.OrderAsc})
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	if len(res.Value) != 2 || res.Value[1].ID != "2" {
+		t.Fatalf("len=%d want=2 (3 starts at 09:30)", len(res.Value))
+	}
+
+	res = q.Execute(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if len(res.Value) != 3 {
+		t.Fatalf("len=%d want=3 with IncludeUnstarted", len(res.Value))
+	}
+}
@@ -0,0 +1,189 @@
+package queries
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type PlanSpec struct {
+	// Capacity is the work a day holds; todos are planned in its unit.
+	Capacity todo.Estimate
+	// Off are weekdays with no capacity, e.g. the weekend.
+	Off []time.Weekday
+	// Start is the first day; zero is today.
+	Start time.Time
+}
+
+type PlanDTO struct {
+	Unit string    `json:"unit"` // "m" or "pt", as for estimates
+	Days []PlanDay `json:"days"` // up to the last with anything on it
+	// Unestimated are the todos without an estimate in the plan's unit.
+	Unestimated []TodoDTO `json:"unestimated"`
+}
+
+// PlanDay is one day of the plan. Over is set when its todos need more
+// than its capacity.
+type PlanDay struct {
+	Date     string     `json:"date"` // YYYY-MM-DD
+	Capacity int        `json:"capacity"`
+	Load     int        `json:"load"`
+	Over     bool       `json:"over"`
+	Items    []PlanItem `json:"items"`
+}
+
+type PlanItem struct {
+	Todo   TodoDTO `json:"todo"`
+	Effort int     `json:"effort"` // what is left of the estimate
+	// Late is set when the todo cannot be done by its due date within
+	// capacity; unless it is bigger than a day, it is planned on the last
+	// day it could still be.
+	Late bool `json:"late"`
+}
+
+// Plan lays out active todos over the days from the start, by due date
+// then priority. Each todo goes on the first day with room for it, from
+// when it is scheduled, but never after its due date: a todo that does
+// not fit by then overloads its last possible day and is flagged late.
+// A todo bigger than a day gets an empty day to itself, and is late when
+// the room left up to its due date is less than its effort. Time
+// estimates count down by the time already tracked.
+type Plan struct {
+	Repo  ports.TodoRepository
+	Clock ports.Clock
+}
+
+func (q Plan) Execute(ctx context.Context, spec PlanSpec) result.Result[PlanDTO] {
+	if spec.Capacity.Value <= 0 || len(spec.Off) >= 7 {
+		return result.Fail[PlanDTO](appErr.ErrValidation)
+	}
+	st := todo.StatusActive
+	tds, err := q.Repo.List(ctx, ports.ListSpec{Status: &st, IncludeUnstarted: true})
+	if err != nil {
+		return result.Fail[PlanDTO](appErr.ErrUnExpected)
+	}
+
+	now := q.Clock.Now()
+	start := cmp.Or(spec.Start, now)
+	y, m, d := start.In(now.Location()).Date()
+	start = time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+
+	out := PlanDTO{Unit: string(spec.Capacity.Unit), Unestimated: []TodoDTO{}}
+	var planned []todo.Todo
+	for _, t := range tds {
+		if t.Estimate == nil || t.Estimate.Unit != spec.Capacity.Unit {
+			out.Unestimated = append(out.Unestimated, ToDTO(t))
+			continue
+		}
+		planned = append(planned, t)
+	}
+	slices.SortFunc(planned, func(a, b todo.Todo) int {
+		switch {
+		case a.DueDate == nil && b.DueDate != nil:
+			return 1
+		case a.DueDate != nil && b.DueDate == nil:
+			return -1
+		case a.DueDate != nil && a.DueDate.IsBefore(*b.DueDate):
+			return -1
+		case a.DueDate != nil && b.DueDate.IsBefore(*a.DueDate):
+			return 1
+		}
+		if c := cmp.Compare(priorityRank(b.Priority), priorityRank(a.Priority)); c != 0 {
+			return c
+		}
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	// day grows the plan up to day i
+	day := func(i int) *PlanDay {
+		for len(out.Days) <= i {
+			date := start.AddDate(0, 0, len(out.Days))
+			c := spec.Capacity.Value
+			if slices.Contains(spec.Off, date.Weekday()) {
+				c = 0
+			}
+			out.Days = append(out.Days, PlanDay{Date: date.Format("2006-01-02"), Capacity: c, Items: []PlanItem{}})
+		}
+		return &out.Days[i]
+	}
+
+	for _, t := range planned {
+		effort := t.Estimate.Value
+		if t.Estimate.Unit == todo.EstimateMinutes {
+			effort = max(effort-int(t.Tracked(now)/time.Minute), 0)
+		}
+		first := 0
+		if t.Scheduled != nil {
+			first = max(t.Scheduled.DaysFrom(start), 0)
+		}
+		due := t.DueDate != nil
+		last := 0
+		if due {
+			last = t.DueDate.DaysFrom(start)
+		}
+
+		at, late := -1, false
+		for i := first; !due || i <= last; i++ {
+			if d := day(i); d.Capacity > 0 && (d.Load == 0 || d.Load+effort <= d.Capacity) {
+				at = i
+				break
+			}
+		}
+		if at >= 0 && due && effort > day(at).Capacity {
+			// on an empty day of its own; late if even every day up to
+			// the due date together can't hold it
+			free := 0
+			for i := first; i <= last; i++ {
+				free += max(day(i).Capacity-day(i).Load, 0)
+			}
+			late = effort > free
+		}
+		if at < 0 {
+			// no room by the due date: the last working day it could be
+			// done on, or the first one when the due date is already gone
+			late = true
+			for i := max(last, first); i >= first; i-- {
+				if day(i).Capacity > 0 {
+					at = i
+					break
+				}
+			}
+			for i := first; at < 0; i++ {
+				if day(i).Capacity > 0 {
+					at = i
+				}
+			}
+		}
+
+		d := day(at)
+		d.Load += effort
+		d.Over = d.Load > d.Capacity
+		d.Items = append(d.Items, PlanItem{Todo: ToDTO(t), Effort: effort, Late: late})
+	}
+
+	// the search may have looked further ahead than anything went
+	for len(out.Days) > 0 && len(out.Days[len(out.Days)-1].Items) == 0 {
+		out.Days = out.Days[:len(out.Days)-1]
+	}
+	return result.Ok(out)
+}
+
+func priorityRank(p todo.Priority) int {
+	switch p {
+	case todo.PriorityHigh:
+		return 2
+	case todo.PriorityMedium:
+		return 1
+	default:
+		return 0
+	}
+}
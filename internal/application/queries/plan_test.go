@@ -0,0 +1,111 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+func TestPlan(t *testing.T) {
+	// Wed Mar 11 2026, 12:00 UTC
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	base := now.AddDate(0, 0, -7)
+	today := "2026-03-11"
+
+	mk := func(id string, pr todo.Priority, due *string, est string) todo.Todo {
+		td := mkTodo(t, id, "todo "+id, todo.StatusActive, pr, nil, due, base)
+		if est != "" {
+			e, err := todo.ParseEstimate(est)
+			if err != nil {
+				t.Fatalf("ParseEstimate(%q): %v", est, err)
+			}
+			td.Estimate = &e
+		}
+		return td
+	}
+	a := mk("a", todo.PriorityHigh, &today, "3h")
+	b := mk("b", todo.PriorityLow, &today, "2h") // no room left today
+	c := mk("c", todo.PriorityLow, nil, "5h")    // bigger than a day
+	d := mk("d", todo.PriorityLow, nil, "1h")
+	d, _, _ = d.LogTime(base, 30*time.Minute, now)
+	e := mk("e", todo.PriorityLow, nil, "3pt")
+	f := mk("f", todo.PriorityLow, nil, "1h")
+	mon, _ := todo.ParseDueDate("2026-03-16")
+	f.Scheduled = &mon
+
+	q := Plan{Repo: newInMemoryRepo(a, b, c, d, e, f), Clock: fakeClock{t: now}}
+	res := q.Execute(context.Background(), PlanSpec{
+		Capacity: todo.Estimate{Value: 240, Unit: todo.EstimateMinutes},
+		Off:      []time.Weekday{time.Saturday, time.Sunday},
+	})
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	plan := res.Value
+
+	var got []string
+	for _, day := range plan.Days {
+		s := day.Date[5:] + ":"
+		for _, it := range day.Items {
+			s += " " + it.Todo.ID
+			if it.Late {
+				s += "!"
+			}
+		}
+		got = append(got, s)
+	}
+	want := []string{"03-11: a b!", "03-12: c", "03-13: d", "03-14:", "03-15:", "03-16: f"}
+	if len(got) != len(want) {
+		t.Fatalf("days=%q want=%q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("days=%q want=%q", got, want)
+		}
+	}
+
+	if !plan.Days[0].Over || !plan.Days[1].Over || plan.Days[2].Over {
+		t.Fatalf("over=%v,%v,%v want=true,true,false", plan.Days[0].Over, plan.Days[1].Over, plan.Days[2].Over)
+	}
+	if plan.Days[2].Load != 30 {
+		t.Fatalf("load=%d want=30 (less the time tracked)", plan.Days[2].Load)
+	}
+	if plan.Days[3].Capacity != 0 {
+		t.Fatalf("saturday capacity=%d want=0", plan.Days[3].Capacity)
+	}
+	if len(plan.Unestimated) != 1 || plan.Unestimated[0].ID != "e" {
+		t.Fatalf("unestimated=%v want=[e]", plan.Unestimated)
+	}
+}
+
+func TestPlan_OversizedTodoIsLateWhenDaysUpToDueCannotHoldIt(t *testing.T) {
+	// Wed Mar 11 2026
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	tomorrow, friday := "2026-03-12", "2026-03-13"
+
+	mk := func(id string, due *string, est string) todo.Todo {
+		td := mkTodo(t, id, "todo "+id, todo.StatusActive, todo.PriorityLow, nil, due, now)
+		e, _ := todo.ParseEstimate(est)
+		td.Estimate = &e
+		return td
+	}
+	big := mk("big", &tomorrow, "20h") // 12h of room by tomorrow
+	fits := mk("fits", &friday, "7h")  // 18h by Friday, less what big took
+
+	q := Plan{Repo: newInMemoryRepo(big, fits), Clock: fakeClock{t: now}}
+	res := q.Execute(context.Background(), PlanSpec{Capacity: todo.Estimate{Value: 360, Unit: todo.EstimateMinutes}})
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	late := map[string]bool{}
+	for _, day := range res.Value.Days {
+		for _, it := range day.Items {
+			late[it.Todo.ID] = it.Late
+		}
+	}
+	if !late["big"] || late["fits"] {
+		t.Fatalf("late=%v want big late, fits on time", late)
+	}
+}
@@ -0,0 +1,69 @@
+package queries
+
+import (
+	"context"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type ListProjects struct {
+	Repo     ports.TodoRepository
+	Projects ports.ProjectRepository
+	Clock    ports.Clock
+}
+
+// ProjectDTO is a project with the counts of its (not deleted) todos.
+// Name "" is the inbox.
+type ProjectDTO struct {
+	Name     string `json:"name"`
+	Active   int    `json:"active"`
+	Done     int    `json:"done"`
+	Archived int    `json:"archived"`
+	Overdue  int    `json:"overdue"`
+}
+
+// Execute lists the inbox first, then every project by name.
+func (q ListProjects) Execute(ctx context.Context) result.Result[[]ProjectDTO] {
+	names, err := q.Projects.ListProjects(ctx)
+	if err != nil {
+		return result.Fail[[]ProjectDTO](appErr.ErrUnExpected)
+	}
+	tds, err := q.Repo.List(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if err != nil {
+		return result.Fail[[]ProjectDTO](appErr.ErrUnExpected)
+	}
+
+	out := []ProjectDTO{{Name: ""}}
+	index := map[todo.Project]int{"": 0}
+	for _, n := range names {
+		index[n] = len(out)
+		out = append(out, ProjectDTO{Name: n.String()})
+	}
+
+	now := q.Clock.Now()
+	for _, t := range tds {
+		i, ok := index[t.Project]
+		if !ok {
+			// the registry lists every project in use; be lenient anyway
+			i = len(out)
+			index[t.Project] = i
+			out = append(out, ProjectDTO{Name: t.Project.String()})
+		}
+		p := &out[i]
+		switch t.Status {
+		case todo.StatusActive:
+			p.Active++
+			if t.DueDate != nil && t.DueDate.IsOverdue(now) {
+				p.Overdue++
+			}
+		case todo.StatusDone:
+			p.Done++
+		case todo.StatusArchived:
+			p.Archived++
+		}
+	}
+	return result.Ok(out)
+}
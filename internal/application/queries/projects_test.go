@@ -0,0 +1,52 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type staticProjects []todo.Project
+
+func (p staticProjects) ListProjects(ctx context.Context) ([]todo.Project, error) { return p, nil }
+func (staticProjects) AddProject(ctx context.Context, _ todo.Project) error       { return nil }
+func (staticProjects) RemoveProject(ctx context.Context, _ todo.Project) error    { return nil }
+
+func TestListProjects(t *testing.T) {
+	now := time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+	overdue := "2025-12-01"
+
+	inbox := mkTodo(t, "1", "Inbox item", todo.StatusActive, todo.PriorityLow, nil, nil, now)
+	late := mkTodo(t, "2", "Late report", todo.StatusActive, todo.PriorityLow, nil, &overdue, now)
+	late.Project = "work"
+	done := mkTodo(t, "3", "Shipped", todo.StatusDone, todo.PriorityLow, nil, nil, now)
+	done.Project = "work"
+	gone := mkTodo(t, "4", "Deleted", todo.StatusActive, todo.PriorityLow, nil, nil, now)
+	gone.Project = "work"
+	gone.DeletedAt = &now
+
+	q := ListProjects{
+		Repo:     newInMemoryRepo(inbox, late, done, gone),
+		Projects: staticProjects{"home", "work"},
+		Clock:    fakeClock{t: now},
+	}
+	res := q.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	want := []ProjectDTO{
+		{Name: "", Active: 1},
+		{Name: "home"},
+		{Name: "work", Active: 1, Done: 1, Overdue: 1},
+	}
+	if len(res.Value) != len(want) {
+		t.Fatalf("projects=%+v want=%+v", res.Value, want)
+	}
+	for i := range want {
+		if res.Value[i] != want[i] {
+			t.Fatalf("projects[%d]=%+v want=%+v", i, res.Value[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Reminders finds the reminders of active todos. Reminders are wall-clock
+// times, read in the clock's zone.
+type Reminders struct {
+	Repo  ports.TodoRepository
+	Clock ports.Clock
+}
+
+// Execute lists the reminders set for after `after` and no later than
+// until, earliest first. A zero until has no bound.
+func (q Reminders) Execute(ctx context.Context, after, until time.Time) result.Result[[]ports.Reminder] {
+	st := todo.StatusActive
+	tds, err := q.Repo.List(ctx, ports.ListSpec{Status: &st, IncludeUnstarted: true})
+	if err != nil {
+		return result.Fail[[]ports.Reminder](appErr.ErrUnExpected)
+	}
+
+	loc := q.Clock.Now().Location()
+	var out []ports.Reminder
+	for _, t := range tds {
+		if t.RemindAt == nil {
+			continue
+		}
+		at := t.RemindAt.In(loc)
+		if at.After(after) && (until.IsZero() || !at.After(until)) {
+			out = append(out, ports.Reminder{Todo: t, At: at})
+		}
+	}
+	slices.SortFunc(out, func(a, b ports.Reminder) int { return a.At.Compare(b.At) })
+	return result.Ok(out)
+}
@@ -2,7 +2,6 @@ package queries
 
 import (
 	"context"
-	"time"
 
 	"github.com/rojanmagar2001/gotodo/internal/application/ports"
 	"github.com/rojanmagar2001/gotodo/internal/application/result"
@@ -15,30 +14,34 @@ type Stats struct {
 }
 
 type StatsDTO struct {
-	Total    int
-	Active   int
-	Done     int
-	Archived int
-	Deleted  int
+	Total    int `json:"total"`
+	Active   int `json:"active"`
+	Done     int `json:"done"`
+	Archived int `json:"archived"`
+	Deleted  int `json:"deleted"`
 
-	Overdue  int
-	DueToday int
-	DueSoon  int // next 7 days (optional but useful)
+	Overdue  int `json:"overdue"`
+	DueToday int `json:"dueToday"`
+	DueSoon  int `json:"dueSoon"` // next 7 days (optional but useful)
+
+	ScheduledToday int `json:"scheduledToday"` // starting today
+	Snoozed        int `json:"snoozed"`        // not started yet, hidden from lists
 }
 
 func (q Stats) Execute(ctx context.Context) result.Result[StatsDTO] {
 	spec := ports.ListSpec{
-		IncludeDeleted: true,
-		SortBy:         ports.SortByCreated,
-		SortOrder:      ports.OrderAsc,
+		IncludeDeleted:   true,
+		IncludeUnstarted: true,
+		SortBy:           ports.SortByCreated,
+		SortOrder:        ports.OrderAsc,
 	}
 	tds, err := q.Repo.List(ctx, spec)
 	if err != nil {
 		return result.Fail[StatsDTO](err)
 	}
 
+	// "today" is the clock's day, in the clock's zone
 	now := q.Clock.Now()
-	today := dateOnlyUTC(now)
 
 	var s StatsDTO
 	s.Total = len(tds)
@@ -58,27 +61,24 @@ func (q Stats) Execute(ctx context.Context) result.Result[StatsDTO] {
 		}
 
 		if t.Status == todo.StatusActive && t.DueDate != nil {
-			due := t.DueDate.AsTimeUTC()
-			if due.Before(today) {
+			switch days := t.DueDate.DaysFrom(now); {
+			case t.DueDate.IsOverdue(now):
 				s.Overdue++
-			} else if sameDayUTC(due, today) {
+			case days == 0:
 				s.DueToday++
-			} else if due.Before(today.Add(7 * 24 * time.Hour)) {
+			case days < 7:
 				s.DueSoon++
 			}
 		}
+		if t.Status == todo.StatusActive && t.Scheduled != nil {
+			if t.Scheduled.DaysFrom(now) == 0 {
+				s.ScheduledToday++
+			}
+			if !t.IsStarted(now) {
+				s.Snoozed++
+			}
+		}
 	}
 
 	return result.Ok(s)
 }
-
-func dateOnlyUTC(t time.Time) time.Time {
-	y, m, d := t.UTC().Date()
-	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
-}
-
-func sameDayUTC(a, b time.Time) bool {
-	ay, am, ad := a.UTC().Date()
-	by, bm, bd := b.UTC().Date()
-	return ay == by && am == bm && ad == bd
-}
@@ -70,3 +70,49 @@ func TestStats_CountsAndDueBuckets(t *testing.T) {
 		t.Fatalf("DueSoon=%d want=1", s.DueSoon)
 	}
 }
+
+func TestStats_ClockZone(t *testing.T) {
+	ctx := context.Background()
+
+	// 00:30 on Dec 15 in UTC+5:45 is still Dec 14 in UTC
+	now := time.Date(2025, 12, 15, 0, 30, 0, 0, time.FixedZone("NPT", 5*3600+45*60))
+	base := time.Date(2025, 12, 10, 10, 0, 0, 0, time.UTC)
+
+	yesterday := "2025-12-14"
+	todayEarly := "2025-12-15T00:15"
+	todayLate := "2025-12-15T18:00"
+	repo := newInMemoryRepo(
+		mkTodo(t, "1", "A", todo.StatusActive, todo.PriorityLow, nil, &yesterday, base),
+		mkTodo(t, "2", "B", todo.StatusActive, todo.PriorityLow, nil, &todayEarly, base),
+		mkTodo(t, "3", "C", todo.StatusActive, todo.PriorityLow, nil, &todayLate, base),
+	)
+
+	res := Stats{Repo: repo, Clock: fakeClock{t: now}}.Execute(ctx)
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	if s := res.Value; s.Overdue != 2 || s.DueToday != 1 || s.DueSoon != 0 {
+		t.Fatalf("overdue=%d today=%d soon=%d want=2,1,0", s.Overdue, s.DueToday, s.DueSoon)
+	}
+}
+
+func TestStats_Scheduled(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	var tds []todo.Todo
+	for i, iso := range []string{"2026-03-10", "2026-03-10T17:00", "2026-03-12", "2026-03-01"} {
+		td := mkTodo(t, string(rune('1'+i)), "T", todo.StatusActive, todo.PriorityLow, nil, nil, now)
+		d, _ := todo.ParseDueDate(iso)
+		td.Scheduled = &d
+		tds = append(tds, td)
+	}
+
+	res := Stats{Repo: newInMemoryRepo(tds...), Clock: fakeClock{t: now}}.Execute(ctx)
+	if res.Err != nil {
+		t.Fatalf("err=%v", res.Err)
+	}
+	if s := res.Value; s.Active != 4 || s.ScheduledToday != 2 || s.Snoozed != 2 {
+		t.Fatalf("active=%d today=%d snoozed=%d want=4,2,2", s.Active, s.ScheduledToday, s.Snoozed)
+	}
+}
@@ -1,6 +1,7 @@
 package queries
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"sort"
@@ -107,6 +108,14 @@ func (r *inMemoryRepo) List(ctx context.Context, spec ports.ListSpec) ([]todo.To
 				continue
 			}
 		}
+		// project filter
+		if spec.Project != nil && t.Project != *spec.Project {
+			continue
+		}
+		// scheduled filter
+		if !spec.IncludeUnstarted && !t.IsStarted(cmp.Or(spec.Now, time.Now())) {
+			continue
+		}
 		// search
 		if spec.Search != nil {
 			q := strings.ToLower(strings.TrimSpace(*spec.Search))
@@ -0,0 +1,135 @@
+package queries
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+)
+
+// RunningTimer finds the todo whose timer is running.
+type RunningTimer struct {
+	Repo ports.TodoRepository
+}
+
+type TimerDTO struct {
+	Todo  TodoDTO   `json:"todo"`
+	Since time.Time `json:"since"`
+}
+
+// Execute returns nil when no timer is running.
+func (q RunningTimer) Execute(ctx context.Context) result.Result[*TimerDTO] {
+	tds, err := q.Repo.List(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if err != nil {
+		return result.Fail[*TimerDTO](appErr.ErrUnExpected)
+	}
+	for _, t := range tds {
+		if e, ok := t.Timer(); ok {
+			return result.Ok(&TimerDTO{Todo: ToDTO(t), Since: e.Start.UTC()})
+		}
+	}
+	return result.Ok[*TimerDTO](nil)
+}
+
+type ReportBy string
+
+const (
+	ReportByTag  ReportBy = "tag"
+	ReportByDay  ReportBy = "day"
+	ReportByWeek ReportBy = "week"
+)
+
+func (b ReportBy) Valid() bool {
+	return b == ReportByTag || b == ReportByDay || b == ReportByWeek
+}
+
+type TimeReportSpec struct {
+	By       ReportBy
+	From, To time.Time // entries starting in [From, To); zero is unbounded
+}
+
+// TimeReportRow is the time tracked under one key: a tag ("" for
+// untagged todos), or the day or first day of the week, as YYYY-MM-DD.
+type TimeReportRow struct {
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+	Todos   int    `json:"todos"` // how many todos the time was spent on
+}
+
+// TimeReport adds up tracked time. An entry counts in full towards the
+// day (and week) it started, in the clock's zone; a running timer counts
+// up to now. Todos with several tags count towards each.
+type TimeReport struct {
+	Repo      ports.TodoRepository
+	Clock     ports.Clock
+	WeekStart time.Weekday
+}
+
+// Execute lists days and weeks in order and tags by most time first.
+func (q TimeReport) Execute(ctx context.Context, spec TimeReportSpec) result.Result[[]TimeReportRow] {
+	if !spec.By.Valid() {
+		return result.Fail[[]TimeReportRow](appErr.ErrValidation)
+	}
+	tds, err := q.Repo.List(ctx, ports.ListSpec{IncludeUnstarted: true})
+	if err != nil {
+		return result.Fail[[]TimeReportRow](appErr.ErrUnExpected)
+	}
+
+	now := q.Clock.Now()
+	type total struct {
+		d     time.Duration
+		todos map[string]bool
+	}
+	totals := map[string]*total{}
+	add := func(key, id string, d time.Duration) {
+		t := totals[key]
+		if t == nil {
+			t = &total{todos: map[string]bool{}}
+			totals[key] = t
+		}
+		t.d += d
+		t.todos[id] = true
+	}
+
+	for _, td := range tds {
+		for _, e := range td.TimeEntries {
+			if (!spec.From.IsZero() && e.Start.Before(spec.From)) || (!spec.To.IsZero() && !e.Start.Before(spec.To)) {
+				continue
+			}
+			d := e.Duration(now)
+			start := e.Start.In(now.Location())
+			switch spec.By {
+			case ReportByTag:
+				if len(td.Tags) == 0 {
+					add("", td.ID.String(), d)
+				}
+				for _, tag := range td.Tags {
+					add(tag, td.ID.String(), d)
+				}
+			case ReportByDay:
+				add(start.Format("2006-01-02"), td.ID.String(), d)
+			case ReportByWeek:
+				back := (int(start.Weekday()) - int(q.WeekStart) + 7) % 7
+				add(start.AddDate(0, 0, -back).Format("2006-01-02"), td.ID.String(), d)
+			}
+		}
+	}
+
+	out := make([]TimeReportRow, 0, len(totals))
+	for k, t := range totals {
+		out = append(out, TimeReportRow{Key: k, Seconds: int64(t.d / time.Second), Todos: len(t.todos)})
+	}
+	slices.SortFunc(out, func(a, b TimeReportRow) int {
+		if spec.By == ReportByTag {
+			if c := cmp.Compare(b.Seconds, a.Seconds); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return result.Ok(out)
+}
@@ -0,0 +1,63 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+func TestTimeReport(t *testing.T) {
+	ctx := context.Background()
+	// Wed Mar 11 2026, 12:00 UTC
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	base := now.AddDate(0, 0, -7)
+
+	log := func(td todo.Todo, start time.Time, d time.Duration) todo.Todo {
+		td, _, err := td.LogTime(start, d, now)
+		if err != nil {
+			t.Fatalf("LogTime: %v", err)
+		}
+		return td
+	}
+	td1 := mkTodo(t, "1", "Write report", todo.StatusActive, todo.PriorityLow, []string{"work", "writing"}, nil, base)
+	td1 = log(td1, time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC), time.Hour) // Sunday
+	td1 = log(td1, time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), 30*time.Minute)
+	td1, _, _ = td1.StartTimer(now.Add(-15 * time.Minute))
+
+	td2 := mkTodo(t, "2", "Mow lawn", todo.StatusActive, todo.PriorityLow, nil, nil, base)
+	td2 = log(td2, time.Date(2026, 3, 9, 17, 0, 0, 0, time.UTC), 45*time.Minute)
+
+	q := TimeReport{Repo: newInMemoryRepo(td1, td2), Clock: fakeClock{t: now}, WeekStart: time.Monday}
+	hours := func(by ReportBy, from time.Time) map[string]float64 {
+		t.Helper()
+		res := q.Execute(ctx, TimeReportSpec{By: by, From: from})
+		if res.Err != nil {
+			t.Fatalf("err=%v", res.Err)
+		}
+		out := map[string]float64{}
+		for _, r := range res.Value {
+			out[r.Key] = float64(r.Seconds) / 3600
+		}
+		return out
+	}
+
+	if got := hours(ReportByTag, time.Time{}); got["work"] != 1.75 || got["writing"] != 1.75 || got[""] != 0.75 {
+		t.Fatalf("by tag=%v want work,writing=1.75 untagged=0.75", got)
+	}
+	if got := hours(ReportByDay, time.Time{}); got["2026-03-08"] != 1 || got["2026-03-09"] != 1.25 || got["2026-03-11"] != 0.25 {
+		t.Fatalf("by day=%v", got)
+	}
+	if got := hours(ReportByWeek, time.Time{}); got["2026-03-02"] != 1 || got["2026-03-09"] != 1.5 {
+		t.Fatalf("by week=%v want 03-02:1 03-09:1.5", got)
+	}
+	if got := hours(ReportByDay, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)); len(got) != 2 {
+		t.Fatalf("from Mar 9=%v want 2 days", got)
+	}
+
+	res := RunningTimer{Repo: newInMemoryRepo(td1, td2)}.Execute(ctx)
+	if res.Err != nil || res.Value == nil || res.Value.Todo.ID != "1" {
+		t.Fatalf("running=%v err=%v want todo 1", res.Value, res.Err)
+	}
+}
@@ -0,0 +1,162 @@
+package todo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResolveDueDate reads a due date the way people write one, relative to
+// now (in now's location) with weeks starting on weekStart:
+//
+//	2026-11-20          that day
+//	2026-11             the last day of that month
+//	today, tomorrow
+//	mon ... sun         the next such day, 1 to 7 days ahead (full names too)
+//	next fri            that day in the following week
+//	in 3 days, in 2 weeks, in 1 month, in 1 year
+//	+3d, +2w, +1m, +1y  the same, shorter; the + is optional
+//	eow, eom, eoy       the last day of this week, month or year
+//	in 2 hours, +2h     two hours from now, with that time
+//
+// Any of the days can take a time, after a space or an @: "fri 17:00",
+// "tomorrow@9am", "2026-11-20 2:30pm". A time alone is today at that time.
+//
+// Adding months keeps the day of the month where it can and uses the
+// month's last day otherwise: Jan 31 +1m is Feb 28.
+func ResolveDueDate(expr string, now time.Time, weekStart time.Weekday) (DueDate, error) {
+	if d, err := ParseDueDate(strings.TrimSpace(expr)); err == nil {
+		return d, nil
+	}
+	s := strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+
+	if i := strings.LastIndexAny(s, " @"); i >= 0 {
+		if h, m, ok := clockTime(s[i+1:]); ok {
+			d, err := ResolveDueDate(s[:i], now, weekStart)
+			if err != nil {
+				return DueDate{}, err
+			}
+			return d.Date().WithTime(h, m), nil
+		}
+	}
+	if h, m, ok := clockTime(s); ok {
+		return dateOf(now).WithTime(h, m), nil
+	}
+	return resolveDay(s, now, weekStart)
+}
+
+func resolveDay(s string, now time.Time, weekStart time.Weekday) (DueDate, error) {
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return dateOf(t.AddDate(0, 1, -1)), nil
+	}
+
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	switch s {
+	case "today":
+		return dateOf(today), nil
+	case "tomorrow":
+		return dateOf(today.AddDate(0, 0, 1)), nil
+	case "eow":
+		end := (weekStart + 6) % 7
+		return dateOf(today.AddDate(0, 0, daysUntil(today.Weekday(), end))), nil
+	case "eom":
+		return dateOf(time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC)), nil
+	case "eoy":
+		return dateOf(time.Date(y, 12, 31, 0, 0, 0, 0, time.UTC)), nil
+	}
+
+	if wd, ok := weekday(s); ok {
+		n := daysUntil(today.Weekday(), wd)
+		if n == 0 {
+			n = 7
+		}
+		return dateOf(today.AddDate(0, 0, n)), nil
+	}
+	if rest, ok := strings.CutPrefix(s, "next "); ok {
+		if wd, ok := weekday(rest); ok {
+			// the start of next week, then the day within it
+			start := today.AddDate(0, 0, 7-daysUntil(weekStart, today.Weekday()))
+			return dateOf(start.AddDate(0, 0, daysUntil(weekStart, wd))), nil
+		}
+	}
+
+	if n, unit, ok := offset(s); ok {
+		switch unit {
+		case "d":
+			return dateOf(today.AddDate(0, 0, n)), nil
+		case "w":
+			return dateOf(today.AddDate(0, 0, 7*n)), nil
+		case "m":
+			return dateOf(addMonths(today, n)), nil
+		case "y":
+			return dateOf(addMonths(today, 12*n)), nil
+		case "h":
+			t := now.Add(time.Duration(n) * time.Hour)
+			return dateOf(t).WithTime(t.Hour(), t.Minute()), nil
+		}
+	}
+	return DueDate{}, ErrInvalidDueDate
+}
+
+// offset reads "in 3 days", "+3d" and "3d" as 3, "d".
+func offset(s string) (int, string, bool) {
+	var num, unit string
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		var found bool
+		if num, unit, found = strings.Cut(rest, " "); !found {
+			return 0, "", false
+		}
+		units := map[string]string{
+			"day": "d", "days": "d", "week": "w", "weeks": "w",
+			"month": "m", "months": "m", "year": "y", "years": "y",
+			"hour": "h", "hours": "h",
+		}
+		if unit = units[unit]; unit == "" {
+			return 0, "", false
+		}
+	} else if rest := strings.TrimPrefix(s, "+"); len(rest) > 1 {
+		num, unit = rest[:len(rest)-1], rest[len(rest)-1:]
+	} else {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 || n > 10000 || strings.ContainsAny(num, "+-") {
+		return 0, "", false
+	}
+	return n, unit, true
+}
+
+// clockTime reads 17:00, 9:30, 5pm and 5:30pm.
+func clockTime(s string) (hour, min int, ok bool) {
+	for _, layout := range []string{"15:04", "3pm", "3:04pm"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour(), t.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
+
+func weekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := strings.ToLower(d.String()); s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// daysUntil counts the days from one weekday to the next to, 0 to 6.
+func daysUntil(from, to time.Weekday) int { return (int(to) - int(from) + 7) % 7 }
+
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	last := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(y, m+time.Month(n), min(d, last), 0, 0, 0, 0, time.UTC)
+}
+
+func dateOf(t time.Time) DueDate {
+	y, m, d := t.Date()
+	return DueDate{year: y, month: m, day: d}
+}
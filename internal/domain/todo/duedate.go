@@ -2,30 +2,86 @@ package todo
 
 import "time"
 
+// DueDate is a day with an optional time of day. It is wall-clock time,
+// not an instant: "17:00" stays 17:00 whatever zone it is read in, so
+// stored values never drift. Scheduled dates use it too.
 type DueDate struct {
-	year  int
-	month time.Month
-	day   int
+	year    int
+	month   time.Month
+	day     int
+	minute  int // of the day, when hasTime
+	hasTime bool
 }
 
+// ParseDueDate accepts YYYY-MM-DD, and YYYY-MM-DDTHH:MM (or a space
+// instead of the T) for a due time.
 func ParseDueDate(iso string) (DueDate, error) {
-	// Expect YYYY-MM-DD strictly
-	t, err := time.Parse("2006-01-02", iso)
-	if err != nil {
-		return DueDate{}, ErrInvalidDueDate
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.Parse(layout, iso); err == nil {
+			d := dateOf(t)
+			if layout != "2006-01-02" {
+				d = d.WithTime(t.Hour(), t.Minute())
+			}
+			return d, nil
+		}
 	}
-	y, m, d := t.Date()
-	return DueDate{year: y, month: m, day: d}, nil
+	return DueDate{}, ErrInvalidDueDate
 }
 
 func (d DueDate) String() string {
-	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	if d.hasTime {
+		return d.In(time.UTC).Format("2006-01-02T15:04")
+	}
+	return d.AsTimeUTC().Format("2006-01-02")
+}
+
+// WithTime is d due at hour:min.
+func (d DueDate) WithTime(hour, min int) DueDate {
+	d.minute, d.hasTime = hour*60+min, true
+	return d
+}
+
+// Date is d without its time.
+func (d DueDate) Date() DueDate {
+	d.minute, d.hasTime = 0, false
+	return d
 }
 
+func (d DueDate) HasTime() bool { return d.hasTime }
+
+// AsTimeUTC is the day at midnight UTC; the time of day is dropped.
 func (d DueDate) AsTimeUTC() time.Time {
 	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, time.UTC)
 }
 
+// In is the wall-clock time of d in loc, midnight when d has no time.
+func (d DueDate) In(loc *time.Location) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, d.minute, 0, 0, loc)
+}
+
+// Deadline is when d is missed in loc: its time, or the end of its day.
+func (d DueDate) Deadline(loc *time.Location) time.Time {
+	if d.hasTime {
+		return d.In(loc)
+	}
+	return time.Date(d.year, d.month, d.day+1, 0, 0, 0, 0, loc)
+}
+
+// IsOverdue reports whether d has passed at now, read in now's zone.
+func (d DueDate) IsOverdue(now time.Time) bool {
+	return !now.Before(d.Deadline(now.Location()))
+}
+
+// DaysFrom counts calendar days from now's day (in now's zone) to d:
+// 0 is today, 1 tomorrow, -1 yesterday.
+func (d DueDate) DaysFrom(now time.Time) int {
+	y, m, day := now.Date()
+	today := time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+	return int(d.AsTimeUTC().Sub(today).Hours() / 24)
+}
+
+// IsBefore orders due dates; a day without a time comes after the times
+// on that day.
 func (d DueDate) IsBefore(other DueDate) bool {
-	return d.AsTimeUTC().Before(other.AsTimeUTC())
+	return d.Deadline(time.UTC).Before(other.Deadline(time.UTC))
 }
@@ -1,6 +1,9 @@
 package todo
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestParseDueDate(t *testing.T) {
 	d, err := ParseDueDate("2025-12-13")
@@ -16,3 +19,88 @@ func TestParseDueDate(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestResolveDueDate(t *testing.T) {
+	// Wednesday, late in the evening in a zone ahead of UTC
+	now := time.Date(2026, 1, 28, 23, 30, 0, 0, time.FixedZone("X", 5*3600))
+
+	tests := []struct {
+		expr      string
+		weekStart time.Weekday
+		want      string
+	}{
+		{"2026-11-20", time.Monday, "2026-11-20"},
+		{"2026-11", time.Monday, "2026-11-30"},
+		{"2026-02", time.Monday, "2026-02-28"},
+		{"today", time.Monday, "2026-01-28"},
+		{" Tomorrow ", time.Monday, "2026-01-29"},
+		{"fri", time.Monday, "2026-01-30"},
+		{"wed", time.Monday, "2026-02-04"},
+		{"Monday", time.Monday, "2026-02-02"},
+		{"next fri", time.Monday, "2026-02-06"},
+		{"next  mon", time.Monday, "2026-02-02"},
+		{"next sun", time.Monday, "2026-02-08"},
+		{"next sun", time.Sunday, "2026-02-01"},
+		{"in 3 days", time.Monday, "2026-01-31"},
+		{"in 1 week", time.Monday, "2026-02-04"},
+		{"in 1 month", time.Monday, "2026-02-28"},
+		{"+2w", time.Monday, "2026-02-11"},
+		{"+0d", time.Monday, "2026-01-28"},
+		{"3d", time.Monday, "2026-01-31"},
+		{"+1y", time.Monday, "2027-01-28"},
+		{"eow", time.Monday, "2026-02-01"},
+		{"eow", time.Sunday, "2026-01-31"},
+		{"eom", time.Monday, "2026-01-31"},
+		{"eoy", time.Monday, "2026-12-31"},
+		{"fri 17:00", time.Monday, "2026-01-30T17:00"},
+		{"tomorrow@9am", time.Monday, "2026-01-29T09:00"},
+		{"2026-11-20 2:30pm", time.Monday, "2026-11-20T14:30"},
+		{"2026-11-20T08:05", time.Monday, "2026-11-20T08:05"},
+		{"18:15", time.Monday, "2026-01-28T18:15"},
+		{"+2h", time.Monday, "2026-01-29T01:30"},
+	}
+	for _, tt := range tests {
+		d, err := ResolveDueDate(tt.expr, now, tt.weekStart)
+		if err != nil || d.String() != tt.want {
+			t.Fatalf("ResolveDueDate(%q, %s) = %s, %v want=%s", tt.expr, tt.weekStart, d, err, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "soon", "next", "next week", "in days", "in -1 days", "+d", "+3x", "+-3d", "2026-13", "13-12-2025", "fri 25:00", "soon 5pm"} {
+		if d, err := ResolveDueDate(expr, now, time.Monday); err == nil {
+			t.Fatalf("ResolveDueDate(%q) = %s, want an error", expr, d)
+		}
+	}
+}
+
+func TestDueDate_Time(t *testing.T) {
+	for _, s := range []string{"2026-03-29", "2026-03-29T09:30"} {
+		d, err := ParseDueDate(s)
+		if err != nil || d.String() != s {
+			t.Fatalf("ParseDueDate(%q) = %s, %v", s, d, err)
+		}
+	}
+
+	// UTC+5:45: 2026-03-29 00:30 local is still 2026-03-28 in UTC
+	kathmandu := time.FixedZone("NPT", 5*3600+45*60)
+	now := time.Date(2026, 3, 29, 0, 30, 0, 0, kathmandu)
+
+	yesterday, _ := ParseDueDate("2026-03-28")
+	today, _ := ParseDueDate("2026-03-29")
+	early := today.WithTime(0, 15)
+	late := today.WithTime(18, 0)
+
+	if !yesterday.IsOverdue(now) || today.IsOverdue(now) || !early.IsOverdue(now) || late.IsOverdue(now) {
+		t.Fatalf("overdue: yesterday=%v today=%v early=%v late=%v",
+			yesterday.IsOverdue(now), today.IsOverdue(now), early.IsOverdue(now), late.IsOverdue(now))
+	}
+	if yesterday.DaysFrom(now) != -1 || today.DaysFrom(now) != 0 || late.DaysFrom(now) != 0 {
+		t.Fatalf("DaysFrom yesterday=%d today=%d late=%d", yesterday.DaysFrom(now), today.DaysFrom(now), late.DaysFrom(now))
+	}
+	if !early.IsBefore(late) || !late.IsBefore(today) || today.IsBefore(late) {
+		t.Fatalf("IsBefore: a day without a time should sort after its times")
+	}
+	if got := late.In(kathmandu).UTC().Format(time.RFC3339); got != "2026-03-29T12:15:00Z" {
+		t.Fatalf("In=%s", got)
+	}
+}
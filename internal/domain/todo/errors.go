@@ -6,6 +6,12 @@ var (
 	ErrInvalidTitle      = errors.New("invalid title")
 	ErrInvalidPriority   = errors.New("invalid priority")
 	ErrInvalidDueDate    = errors.New("invalid due date")
+	ErrInvalidReminder   = errors.New("a reminder needs a time of day")
+	ErrInvalidProject    = errors.New("invalid project")
 	ErrInvalidTransition = errors.New("invalid status transition")
 	ErrDeletedTodo       = errors.New("todo is deleted")
+	ErrTimerRunning      = errors.New("a timer is already running")
+	ErrNoTimer           = errors.New("no timer is running")
+	ErrInvalidTimeEntry  = errors.New("invalid time entry")
+	ErrInvalidEstimate   = errors.New("invalid estimate")
 )
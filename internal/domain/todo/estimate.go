@@ -0,0 +1,59 @@
+package todo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EstimateUnit string
+
+const (
+	EstimateMinutes EstimateUnit = "m"
+	EstimatePoints  EstimateUnit = "pt"
+)
+
+// Estimate is the effort a todo is expected to take, in minutes or in
+// story points.
+type Estimate struct {
+	Value int
+	Unit  EstimateUnit
+}
+
+// ParseEstimate reads a time ("90m", "2h", "1h30m") or story points
+// ("3pt", "3p"). It must be positive; times are whole minutes.
+func ParseEstimate(raw string) (Estimate, error) {
+	v := strings.TrimSpace(strings.ToLower(raw))
+	for _, suffix := range []string{"pt", "p"} {
+		if n, ok := strings.CutSuffix(v, suffix); ok {
+			points, err := strconv.Atoi(n)
+			if err != nil || points <= 0 {
+				return Estimate{}, ErrInvalidEstimate
+			}
+			return Estimate{Value: points, Unit: EstimatePoints}, nil
+		}
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 || d%time.Minute != 0 {
+		return Estimate{}, ErrInvalidEstimate
+	}
+	return Estimate{Value: int(d / time.Minute), Unit: EstimateMinutes}, nil
+}
+
+// String writes the estimate so ParseEstimate reads it back: "1h30m",
+// "45m", "3pt".
+func (e Estimate) String() string {
+	if e.Unit == EstimatePoints {
+		return strconv.Itoa(e.Value) + "pt"
+	}
+	h, m := e.Value/60, e.Value%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+}
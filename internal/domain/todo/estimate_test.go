@@ -0,0 +1,25 @@
+package todo
+
+import "testing"
+
+func TestParseEstimate(t *testing.T) {
+	for in, want := range map[string]string{
+		"90m": "1h30m", "2h": "2h", "45m": "45m", " 1H05M ": "1h05m", "3pt": "3pt", "5p": "5pt",
+	} {
+		e, err := ParseEstimate(in)
+		if err != nil {
+			t.Fatalf("ParseEstimate(%q): %v", in, err)
+		}
+		if e.String() != want {
+			t.Fatalf("ParseEstimate(%q)=%s want=%s", in, e, want)
+		}
+		if again, _ := ParseEstimate(e.String()); again != e {
+			t.Fatalf("%s does not round-trip: %+v", e, again)
+		}
+	}
+	for _, in := range []string{"", "0m", "-1h", "30s", "1.5pt", "pt", "soon"} {
+		if _, err := ParseEstimate(in); err != ErrInvalidEstimate {
+			t.Fatalf("ParseEstimate(%q) err=%v want=%v", in, err, ErrInvalidEstimate)
+		}
+	}
+}
@@ -4,14 +4,27 @@ import "time"
 
 type Event interface {
 	eventName() string
+	subject() TodoID
+	occurred() time.Time
 }
 
+// EventName returns the stable wire name of an event, e.g. "todo.completed".
+func EventName(e Event) string { return e.eventName() }
+
+// EventTodoID returns the ID of the todo an event is about.
+func EventTodoID(e Event) TodoID { return e.subject() }
+
+// EventTime returns when an event happened.
+func EventTime(e Event) time.Time { return e.occurred() }
+
 type TodoCreated struct {
 	ID         TodoID
 	OccurredAt time.Time
 }
 
-func (TodoCreated) eventName() string { return "todo.created" }
+func (TodoCreated) eventName() string     { return "todo.created" }
+func (e TodoCreated) subject() TodoID     { return e.ID }
+func (e TodoCreated) occurred() time.Time { return e.OccurredAt }
 
 type TodoTitleChanged struct {
 	ID         TodoID
@@ -19,39 +32,121 @@ type TodoTitleChanged struct {
 	OccurredAt time.Time
 }
 
-func (TodoTitleChanged) eventName() string { return "todo.title_changed" }
+func (TodoTitleChanged) eventName() string     { return "todo.title_changed" }
+func (e TodoTitleChanged) subject() TodoID     { return e.ID }
+func (e TodoTitleChanged) occurred() time.Time { return e.OccurredAt }
 
 type TodoCompleted struct {
 	ID         TodoID
 	OccurredAt time.Time
 }
 
-func (TodoCompleted) eventName() string { return "todo.completed" }
+func (TodoCompleted) eventName() string     { return "todo.completed" }
+func (e TodoCompleted) subject() TodoID     { return e.ID }
+func (e TodoCompleted) occurred() time.Time { return e.OccurredAt }
 
 type TodoReopened struct {
 	ID         TodoID
 	OccurredAt time.Time
 }
 
-func (TodoReopened) eventName() string { return "todo.reopened" }
+func (TodoReopened) eventName() string     { return "todo.reopened" }
+func (e TodoReopened) subject() TodoID     { return e.ID }
+func (e TodoReopened) occurred() time.Time { return e.OccurredAt }
 
 type TodoArchived struct {
 	ID         TodoID
 	OccurredAt time.Time
 }
 
-func (TodoArchived) eventName() string { return "todo.archived" }
+func (TodoArchived) eventName() string     { return "todo.archived" }
+func (e TodoArchived) subject() TodoID     { return e.ID }
+func (e TodoArchived) occurred() time.Time { return e.OccurredAt }
 
 type TodoRestored struct {
 	ID         TodoID
 	OccurredAt time.Time
 }
 
-func (TodoRestored) eventName() string { return "todo.restored" }
+func (TodoRestored) eventName() string     { return "todo.restored" }
+func (e TodoRestored) subject() TodoID     { return e.ID }
+func (e TodoRestored) occurred() time.Time { return e.OccurredAt }
 
 type TodoDeleted struct {
 	ID         TodoID
+	Todo       Todo // its last state, for subscribers that can no longer load it
+	OccurredAt time.Time
+}
+
+func (TodoDeleted) eventName() string     { return "todo.deleted" }
+func (e TodoDeleted) subject() TodoID     { return e.ID }
+func (e TodoDeleted) occurred() time.Time { return e.OccurredAt }
+
+type TodoMoved struct {
+	ID         TodoID
+	From, To   Project
+	OccurredAt time.Time
+}
+
+func (TodoMoved) eventName() string     { return "todo.moved" }
+func (e TodoMoved) subject() TodoID     { return e.ID }
+func (e TodoMoved) occurred() time.Time { return e.OccurredAt }
+
+type TodoScheduled struct {
+	ID         TodoID
+	Scheduled  *DueDate // nil when cleared
+	OccurredAt time.Time
+}
+
+func (TodoScheduled) eventName() string     { return "todo.scheduled" }
+func (e TodoScheduled) subject() TodoID     { return e.ID }
+func (e TodoScheduled) occurred() time.Time { return e.OccurredAt }
+
+type TodoReminderSet struct {
+	ID         TodoID
+	RemindAt   *DueDate // nil when cleared
+	OccurredAt time.Time
+}
+
+func (TodoReminderSet) eventName() string     { return "todo.reminder_set" }
+func (e TodoReminderSet) subject() TodoID     { return e.ID }
+func (e TodoReminderSet) occurred() time.Time { return e.OccurredAt }
+
+type TimerStarted struct {
+	ID         TodoID
+	OccurredAt time.Time
+}
+
+func (TimerStarted) eventName() string     { return "todo.timer_started" }
+func (e TimerStarted) subject() TodoID     { return e.ID }
+func (e TimerStarted) occurred() time.Time { return e.OccurredAt }
+
+type TimerStopped struct {
+	ID         TodoID
+	Duration   time.Duration // of the entry just stopped
+	OccurredAt time.Time
+}
+
+func (TimerStopped) eventName() string     { return "todo.timer_stopped" }
+func (e TimerStopped) subject() TodoID     { return e.ID }
+func (e TimerStopped) occurred() time.Time { return e.OccurredAt }
+
+type TimeLogged struct {
+	ID         TodoID
+	Duration   time.Duration
+	OccurredAt time.Time
+}
+
+func (TimeLogged) eventName() string     { return "todo.time_logged" }
+func (e TimeLogged) subject() TodoID     { return e.ID }
+func (e TimeLogged) occurred() time.Time { return e.OccurredAt }
+
+type TodoEstimated struct {
+	ID         TodoID
+	Estimate   *Estimate // nil when cleared
 	OccurredAt time.Time
 }
 
-func (TodoDeleted) eventName() string { return "todo.deleted" }
+func (TodoEstimated) eventName() string     { return "todo.estimated" }
+func (e TodoEstimated) subject() TodoID     { return e.ID }
+func (e TodoEstimated) occurred() time.Time { return e.OccurredAt }
@@ -0,0 +1,29 @@
+package todo
+
+import "strings"
+
+// Project names the list a todo belongs to, e.g. "work" or
+// "github.com/acme/api". The empty project is the inbox.
+type Project string
+
+const maxProjectLen = 100
+
+// NewProject lower-cases and trims raw. Names may use letters, digits and
+// - _ . / so a repository path works as a name; "" is the inbox.
+func NewProject(raw string) (Project, error) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	if len(v) > maxProjectLen || strings.HasPrefix(v, "/") || strings.HasSuffix(v, "/") {
+		return "", ErrInvalidProject
+	}
+	for _, r := range v {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./", r)) {
+			return "", ErrInvalidProject
+		}
+	}
+	return Project(v), nil
+}
+
+func (p Project) String() string { return string(p) }
+
+// IsInbox reports whether p is the empty project.
+func (p Project) IsInbox() bool { return p == "" }
@@ -0,0 +1,51 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewProject(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Project
+		ok   bool
+	}{
+		{"", "", true},
+		{"  Work ", "work", true},
+		{"github.com/acme/api", "github.com/acme/api", true},
+		{"home_v2-x", "home_v2-x", true},
+		{"has space", "", false},
+		{"/abs", "", false},
+		{"trail/", "", false},
+		{"emoji✓", "", false},
+	}
+	for _, tt := range tests {
+		got, err := NewProject(tt.raw)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Fatalf("NewProject(%q)=%q err=%v want=%q ok=%v", tt.raw, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMoveTo(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := Todo{ID: "t1", Status: StatusActive}
+
+	moved, evs, err := td.MoveTo("work", now)
+	if err != nil || moved.Project != "work" || !moved.UpdatedAt.Equal(now) || len(evs) != 1 || EventName(evs[0]) != "todo.moved" {
+		t.Fatalf("moved=%+v evs=%v err=%v", moved, evs, err)
+	}
+	if e := evs[0].(TodoMoved); e.From != "" || e.To != "work" {
+		t.Fatalf("event=%+v", e)
+	}
+
+	if _, evs, err := moved.MoveTo("work", now); err != nil || evs != nil {
+		t.Fatalf("same project evs=%v err=%v", evs, err)
+	}
+
+	moved.DeletedAt = &now
+	if _, _, err := moved.MoveTo("home", now); err != ErrDeletedTodo {
+		t.Fatalf("deleted err=%v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package todo
+
+import (
+	"slices"
+	"time"
+)
+
+// TimeEntry is a stretch of time spent on a todo. End is nil while its
+// timer runs.
+type TimeEntry struct {
+	Start time.Time
+	End   *time.Time
+}
+
+func (e TimeEntry) Running() bool { return e.End == nil }
+
+// Duration is how long the entry lasted, up to now while it runs.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.End != nil {
+		end = *e.End
+	}
+	return max(end.Sub(e.Start), 0)
+}
+
+// Timer is the entry of the running timer, if there is one.
+func (t Todo) Timer() (TimeEntry, bool) {
+	if n := len(t.TimeEntries); n > 0 && t.TimeEntries[n-1].Running() {
+		return t.TimeEntries[n-1], true
+	}
+	return TimeEntry{}, false
+}
+
+// Tracked is the time logged on the todo, counting a running timer up to
+// now.
+func (t Todo) Tracked(now time.Time) time.Duration {
+	var d time.Duration
+	for _, e := range t.TimeEntries {
+		d += e.Duration(now)
+	}
+	return d
+}
+
+// StartTimer starts tracking time on an active todo.
+func (t Todo) StartTimer(now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	if t.Status != StatusActive {
+		return t, nil, ErrInvalidTransition
+	}
+	if _, ok := t.Timer(); ok {
+		return t, nil, ErrTimerRunning
+	}
+	t.TimeEntries = append(slices.Clone(t.TimeEntries), TimeEntry{Start: now})
+	t.UpdatedAt = now
+	return t, []Event{TimerStarted{ID: t.ID, OccurredAt: now}}, nil
+}
+
+// StopTimer stops the running timer.
+func (t Todo) StopTimer(now time.Time) (Todo, []Event, error) {
+	if _, ok := t.Timer(); !ok {
+		return t, nil, ErrNoTimer
+	}
+	t.TimeEntries = slices.Clone(t.TimeEntries)
+	last := &t.TimeEntries[len(t.TimeEntries)-1]
+	last.End = ptrTime(maxTime(now, last.Start))
+	t.UpdatedAt = now
+	return t, []Event{TimerStopped{ID: t.ID, Duration: last.Duration(now), OccurredAt: now}}, nil
+}
+
+// LogTime adds time spent on the todo by hand: d from start. The entry
+// can't end in the future.
+func (t Todo) LogTime(start time.Time, d time.Duration, now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	end := start.Add(d)
+	if d <= 0 || end.After(now) {
+		return t, nil, ErrInvalidTimeEntry
+	}
+	entries := slices.Clone(t.TimeEntries)
+	i := len(entries)
+	if _, ok := t.Timer(); ok {
+		i-- // the running entry stays last
+	}
+	t.TimeEntries = slices.Insert(entries, i, TimeEntry{Start: start, End: &end})
+	t.UpdatedAt = now
+	return t, []Event{TimeLogged{ID: t.ID, Duration: d, OccurredAt: now}}, nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return b
+	}
+	return a
+}
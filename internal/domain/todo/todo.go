@@ -9,6 +9,23 @@ type Todo struct {
 	Priority Priority
 	Tags     Tags
 	DueDate  *DueDate
+	ParentID TodoID  // empty for top-level todos
+	Project  Project // empty for the inbox
+
+	// Scheduled is when work can start; lists hide the todo until then.
+	// Snoozing moves it later.
+	Scheduled *DueDate
+	// RemindAt is when to be reminded; it always has a time.
+	RemindAt *DueDate
+	// TimeEntries is the time tracked on the todo, oldest first; only
+	// the last can be running.
+	TimeEntries []TimeEntry
+	// Estimate is the expected effort; nil when not estimated.
+	Estimate *Estimate
+
+	// Meta carries free-form key/value data from importers and integrations
+	// (e.g. unknown todo.txt extensions), namespaced as "<source>.<key>".
+	Meta map[string]string
 
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -23,7 +40,11 @@ type NewTodoParams struct {
 	Priority Priority
 	Tags     Tags
 	DueDate  *DueDate
+	ParentID TodoID
+	Project  Project
 	Now      time.Time
+
+	Scheduled *DueDate
 }
 
 func NewTodo(p NewTodoParams) (Todo, []Event, error) {
@@ -41,6 +62,9 @@ func NewTodo(p NewTodoParams) (Todo, []Event, error) {
 		Priority:  p.Priority,
 		Tags:      p.Tags,
 		DueDate:   p.DueDate,
+		ParentID:  p.ParentID,
+		Project:   p.Project,
+		Scheduled: p.Scheduled,
 		CreatedAt: p.Now,
 		UpdatedAt: p.Now,
 	}
@@ -77,10 +101,15 @@ func (t Todo) Complete(now time.Time) (Todo, []Event, error) {
 	case StatusDone:
 		return t, nil, nil // idempotent
 	case StatusActive:
+		var events []Event
+		if _, ok := t.Timer(); ok {
+			// finishing stops the clock
+			t, events, _ = t.StopTimer(now)
+		}
 		t.Status = StatusDone
 		t.CompletedAt = ptrTime(now)
 		t.UpdatedAt = now
-		return t, []Event{TodoCompleted{ID: t.ID, OccurredAt: now}}, nil
+		return t, append(events, TodoCompleted{ID: t.ID, OccurredAt: now}), nil
 	case StatusArchived:
 		return t, nil, ErrInvalidTransition
 	default:
@@ -128,8 +157,14 @@ func (t Todo) SoftDelete(now time.Time) (Todo, []Event, error) {
 	if t.DeletedAt != nil {
 		return t, nil, nil
 	}
+	var events []Event
+	if _, ok := t.Timer(); ok {
+		// a deleted todo is hidden from the timer commands; don't leave
+		// it running
+		t, events, _ = t.StopTimer(now)
+	}
 	t.DeletedAt = ptrTime(now)
-	return t, []Event{TodoDeleted{ID: t.ID, OccurredAt: now}}, nil
+	return t, append(events, TodoDeleted{ID: t.ID, Todo: t, OccurredAt: now}), nil
 }
 
 func (t Todo) Reopen(now time.Time) (Todo, []Event, error) {
@@ -152,4 +187,68 @@ func (t Todo) Reopen(now time.Time) (Todo, []Event, error) {
 	}
 }
 
+// MoveTo puts the todo in project p ("" for the inbox).
+func (t Todo) MoveTo(p Project, now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	if t.Project == p {
+		return t, nil, nil // idempotent
+	}
+	from := t.Project
+	t.Project = p
+	t.UpdatedAt = now
+	return t, []Event{TodoMoved{ID: t.ID, From: from, To: p, OccurredAt: now}}, nil
+}
+
+// Schedule sets when work on the todo can start; nil clears it.
+func (t Todo) Schedule(d *DueDate, now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	if t.Scheduled == d || (t.Scheduled != nil && d != nil && *t.Scheduled == *d) {
+		return t, nil, nil // idempotent
+	}
+	t.Scheduled = d
+	t.UpdatedAt = now
+	return t, []Event{TodoScheduled{ID: t.ID, Scheduled: d, OccurredAt: now}}, nil
+}
+
+// SetReminder sets when to be reminded of the todo; nil clears it. The
+// reminder needs a time of day, not just a day.
+func (t Todo) SetReminder(d *DueDate, now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	if d != nil && !d.HasTime() {
+		return t, nil, ErrInvalidReminder
+	}
+	if t.RemindAt == d || (t.RemindAt != nil && d != nil && *t.RemindAt == *d) {
+		return t, nil, nil // idempotent
+	}
+	t.RemindAt = d
+	t.UpdatedAt = now
+	return t, []Event{TodoReminderSet{ID: t.ID, RemindAt: d, OccurredAt: now}}, nil
+}
+
+// SetEstimate sets the todo's expected effort; nil clears it.
+func (t Todo) SetEstimate(e *Estimate, now time.Time) (Todo, []Event, error) {
+	if err := t.ensureNotDeleted(); err != nil {
+		return t, nil, err
+	}
+	if t.Estimate == e || (t.Estimate != nil && e != nil && *t.Estimate == *e) {
+		return t, nil, nil // idempotent
+	}
+	t.Estimate = e
+	t.UpdatedAt = now
+	return t, []Event{TodoEstimated{ID: t.ID, Estimate: e, OccurredAt: now}}, nil
+}
+
+// IsStarted reports whether the todo's scheduled time has come at now,
+// read in now's zone. Unscheduled todos have always started; a day
+// without a time starts at midnight.
+func (t Todo) IsStarted(now time.Time) bool {
+	return t.Scheduled == nil || !now.Before(t.Scheduled.In(now.Location()))
+}
+
 func ptrTime(t time.Time) *time.Time { return &t }
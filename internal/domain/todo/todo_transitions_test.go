@@ -123,3 +123,123 @@ func TestTodo_SoftDeleteBlocksEdits(t *testing.T) {
 		t.Fatalf("err=%v want=%v", err, ErrDeletedTodo)
 	}
 }
+
+func TestTodo_Schedule(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	title, _ := NewTitle("File taxes")
+	td, _, _ := NewTodo(NewTodoParams{ID: TodoID("t1"), Title: title, Now: now})
+
+	if !td.IsStarted(now) {
+		t.Fatalf("unscheduled todo should have started")
+	}
+
+	day, _ := ParseDueDate("2026-03-12")
+	td, ev, err := td.Schedule(&day, now)
+	if err != nil || len(ev) != 1 {
+		t.Fatalf("Schedule err=%v events=%d want 1", err, len(ev))
+	}
+	if got := EventName(ev[0]); got != "todo.scheduled" {
+		t.Fatalf("event=%s want todo.scheduled", got)
+	}
+	if _, ev, _ = td.Schedule(&day, now); len(ev) != 0 {
+		t.Fatalf("rescheduling to the same day should be a no-op")
+	}
+
+	for _, c := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 11, 23, 59, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), true},
+		// a day starts at midnight in the reader's zone
+		{time.Date(2026, 3, 12, 0, 30, 0, 0, time.FixedZone("UTC+1", 3600)), true},
+	} {
+		if got := td.IsStarted(c.at); got != c.want {
+			t.Fatalf("IsStarted(%v)=%v want=%v", c.at, got, c.want)
+		}
+	}
+
+	td, ev, _ = td.Schedule(nil, now)
+	if td.Scheduled != nil || len(ev) != 1 {
+		t.Fatalf("clearing: scheduled=%v events=%d", td.Scheduled, len(ev))
+	}
+}
+
+func TestTodo_SetReminder(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	title, _ := NewTitle("Call dentist")
+	td, _, _ := NewTodo(NewTodoParams{ID: TodoID("t1"), Title: title, Now: now})
+
+	day, _ := ParseDueDate("2026-03-12")
+	if _, _, err := td.SetReminder(&day, now); err != ErrInvalidReminder {
+		t.Fatalf("err=%v want=%v", err, ErrInvalidReminder)
+	}
+
+	at := day.WithTime(9, 30)
+	td, ev, err := td.SetReminder(&at, now)
+	if err != nil || len(ev) != 1 || td.RemindAt.String() != "2026-03-12T09:30" {
+		t.Fatalf("err=%v events=%d remindAt=%v", err, len(ev), td.RemindAt)
+	}
+}
+
+func TestTodo_TimeTracking(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	title, _ := NewTitle("Write report")
+	td, _, _ := NewTodo(NewTodoParams{ID: TodoID("t1"), Title: title, Now: now})
+
+	if _, _, err := td.StopTimer(now); err != ErrNoTimer {
+		t.Fatalf("stop without timer err=%v want=%v", err, ErrNoTimer)
+	}
+	td, _, err := td.StartTimer(now)
+	if err != nil {
+		t.Fatalf("StartTimer err: %v", err)
+	}
+	if _, _, err := td.StartTimer(now); err != ErrTimerRunning {
+		t.Fatalf("second start err=%v want=%v", err, ErrTimerRunning)
+	}
+
+	// logged time goes before the running entry
+	td, _, err = td.LogTime(now.Add(-2*time.Hour), time.Hour, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("LogTime err: %v", err)
+	}
+	if _, ok := td.Timer(); !ok || len(td.TimeEntries) != 2 {
+		t.Fatalf("entries=%v want logged then running", td.TimeEntries)
+	}
+	if _, _, err := td.LogTime(now, time.Hour, now); err != ErrInvalidTimeEntry {
+		t.Fatalf("future entry err=%v want=%v", err, ErrInvalidTimeEntry)
+	}
+
+	// completing stops the clock
+	td, ev, err := td.Complete(now.Add(30 * time.Minute))
+	if err != nil || len(ev) != 2 {
+		t.Fatalf("Complete err=%v events=%d want 2", err, len(ev))
+	}
+	if got := td.Tracked(now.Add(time.Hour)); got != 90*time.Minute {
+		t.Fatalf("tracked=%v want=1h30m", got)
+	}
+}
+
+func TestTodo_SoftDeleteStopsTimer(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	title, _ := NewTitle("Write report")
+	td, _, _ := NewTodo(NewTodoParams{ID: TodoID("t1"), Title: title, Now: now})
+	td, _, _ = td.StartTimer(now)
+
+	td, ev, err := td.SoftDelete(now.Add(time.Hour))
+	if err != nil || len(ev) != 2 {
+		t.Fatalf("SoftDelete err=%v events=%d want 2", err, len(ev))
+	}
+	if _, ok := ev[0].(TimerStopped); !ok {
+		t.Fatalf("first event=%T want TimerStopped", ev[0])
+	}
+	if _, ok := td.Timer(); ok {
+		t.Fatalf("timer still running after delete")
+	}
+	if got := td.Tracked(now.Add(2 * time.Hour)); got != time.Hour {
+		t.Fatalf("tracked=%v want=1h", got)
+	}
+	if d := ev[1].(TodoDeleted); d.Todo.DeletedAt == nil {
+		t.Fatalf("deleted snapshot=%+v", d.Todo)
+	}
+}
@@ -2,6 +2,15 @@ package clock
 
 import "time"
 
-type RealClock struct{}
+// RealClock tells the time in Location, the zone that decides what day
+// it is for due dates; nil is UTC.
+type RealClock struct {
+	Location *time.Location
+}
 
-func (RealClock) Now() time.Time { return time.Now().UTC() }
+func (c RealClock) Now() time.Time {
+	if c.Location == nil {
+		return time.Now().UTC()
+	}
+	return time.Now().In(c.Location)
+}
@@ -0,0 +1,339 @@
+// Package csvimport turns spreadsheet exports into todos.
+//
+// Columns are mapped to fields by header name or 1-based position. Every
+// row is validated through the domain constructors; rows that fail (or
+// duplicate an existing title) are rejected with a reason instead of
+// aborting the import, so a dry run can show exactly what would happen.
+package csvimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type HeaderMode string
+
+const (
+	HeaderAuto HeaderMode = "auto"
+	HeaderYes  HeaderMode = "yes"
+	HeaderNo   HeaderMode = "no"
+)
+
+// Mapping names the column holding each field, by header name
+// (case-insensitive) or 1-based index. Empty means "not mapped"; when no
+// column is mapped at all, well-known header names are detected.
+type Mapping struct {
+	Title    string
+	Priority string
+	Tags     string
+	DueDate  string
+}
+
+type Options struct {
+	Mapping Mapping
+	Header  HeaderMode
+	Comma   rune // default ','
+
+	TagSeparator    string   // default ";"
+	DateLayouts     []string // Go layouts, default "2006-01-02"
+	DefaultPriority todo.Priority
+
+	// ExistingTitles are compared case-insensitively to reject duplicates.
+	ExistingTitles []string
+}
+
+// Rejection explains why a row was not imported.
+type Rejection struct {
+	Line   int
+	Record []string
+	Reason string
+}
+
+type Result struct {
+	Todos    []todo.Todo
+	Lines    []int // source line of each accepted todo
+	Rejected []Rejection
+}
+
+var aliases = map[string][]string{
+	"title":    {"title", "task", "name", "summary", "action item", "action", "item", "description"},
+	"priority": {"priority", "prio", "pri", "importance"},
+	"tags":     {"tags", "tag", "labels", "label", "category", "categories"},
+	"due":      {"due", "due date", "duedate", "deadline", "due by"},
+}
+
+var priorityAliases = map[string]todo.Priority{
+	"h": todo.PriorityHigh, "hi": todo.PriorityHigh, "p1": todo.PriorityHigh, "urgent": todo.PriorityHigh,
+	"m": todo.PriorityMedium, "med": todo.PriorityMedium, "p2": todo.PriorityMedium, "normal": todo.PriorityMedium,
+	"l": todo.PriorityLow, "lo": todo.PriorityLow, "p3": todo.PriorityLow,
+}
+
+type columns struct{ title, priority, tags, due int } // -1 = unmapped
+
+// Decode reads all rows of r. Only I/O errors are returned as errors;
+// malformed and invalid rows end up in Result.Rejected.
+func Decode(r io.Reader, opts Options, ids ports.IDGenerator, now time.Time) (Result, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	seen := map[string]bool{}
+	for _, t := range opts.ExistingTitles {
+		seen[normTitle(t)] = true
+	}
+
+	var (
+		res     Result
+		cols    columns
+		started bool
+	)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			res.Rejected = append(res.Rejected, Rejection{Line: perr.StartLine, Reason: perr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		// a quoted field may span lines; report the one the row starts on
+		line, _ := cr.FieldPos(0)
+
+		if !started {
+			started = true
+			var hasHeader bool
+			if cols, hasHeader, err = resolveColumns(rec, opts); err != nil {
+				return Result{}, err
+			}
+			if hasHeader {
+				continue
+			}
+		}
+		if blank(rec) {
+			continue
+		}
+
+		td, err := buildTodo(rec, cols, opts, ids.NewTodoID(), now)
+		if err != nil {
+			res.Rejected = append(res.Rejected, Rejection{Line: line, Record: rec, Reason: err.Error()})
+			continue
+		}
+
+		key := normTitle(td.Title.String())
+		if seen[key] {
+			res.Rejected = append(res.Rejected, Rejection{Line: line, Record: rec, Reason: fmt.Sprintf("duplicate title %q", td.Title)})
+			continue
+		}
+		seen[key] = true
+
+		res.Todos = append(res.Todos, td)
+		res.Lines = append(res.Lines, line)
+	}
+	return res, nil
+}
+
+func buildTodo(rec []string, cols columns, opts Options, id todo.TodoID, now time.Time) (todo.Todo, error) {
+	rawTitle := cell(rec, cols.title)
+	title, err := todo.NewTitle(rawTitle)
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("%v %q", err, rawTitle)
+	}
+
+	priority := opts.DefaultPriority
+	if priority == "" {
+		priority = todo.PriorityLow
+	}
+	if raw := cell(rec, cols.priority); raw != "" {
+		if p, ok := priorityAliases[strings.ToLower(raw)]; ok {
+			priority = p
+		} else if priority, err = todo.NewPriority(raw); err != nil {
+			return todo.Todo{}, fmt.Errorf("%v %q", err, raw)
+		}
+	}
+
+	var tags []string
+	if raw := cell(rec, cols.tags); raw != "" {
+		sep := opts.TagSeparator
+		if sep == "" {
+			sep = ";"
+		}
+		tags = strings.Split(raw, sep)
+	}
+
+	var due *todo.DueDate
+	if raw := cell(rec, cols.due); raw != "" {
+		d, err := parseDue(raw, opts.DateLayouts)
+		if err != nil {
+			return todo.Todo{}, fmt.Errorf("%v %q", err, raw)
+		}
+		due = &d
+	}
+
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       id,
+		Title:    title,
+		Priority: priority,
+		Tags:     todo.NewTags(tags),
+		DueDate:  due,
+		Now:      now,
+	})
+	return td, err
+}
+
+// parseDue tries each layout, then hands the result to the domain parser
+// so CSV dates obey the same rules as every other entry point.
+func parseDue(raw string, layouts []string) (todo.DueDate, error) {
+	if len(layouts) == 0 {
+		layouts = []string{"2006-01-02"}
+	}
+	for _, l := range layouts {
+		if t, err := time.Parse(l, raw); err == nil {
+			return todo.ParseDueDate(t.Format("2006-01-02"))
+		}
+	}
+	return todo.DueDate{}, todo.ErrInvalidDueDate
+}
+
+func resolveColumns(first []string, opts Options) (columns, bool, error) {
+	m := opts.Mapping
+	header := map[string]int{}
+	for i, h := range first {
+		header[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	mapped := m != (Mapping{})
+	isHeader := false
+	switch opts.Header {
+	case HeaderYes:
+		isHeader = true
+	case HeaderNo:
+	default:
+		// a header if any configured (or well-known) column name appears in it
+		names := []string{m.Title, m.Priority, m.Tags, m.DueDate}
+		if !mapped {
+			for _, a := range aliases {
+				names = append(names, a...)
+			}
+		}
+		for _, n := range names {
+			if _, ok := header[strings.ToLower(strings.TrimSpace(n))]; ok && n != "" {
+				isHeader = true
+				break
+			}
+		}
+	}
+
+	if !mapped {
+		if !isHeader {
+			// no names to go on: title, priority, tags, due by position
+			return columns{0, 1, 2, 3}, false, nil
+		}
+		return columns{
+			title:    detect(header, aliases["title"]),
+			priority: detect(header, aliases["priority"]),
+			tags:     detect(header, aliases["tags"]),
+			due:      detect(header, aliases["due"]),
+		}, true, checkTitle(detect(header, aliases["title"]))
+	}
+
+	var cols columns
+	var err error
+	resolve := func(name string) int {
+		if name == "" || err != nil {
+			return -1
+		}
+		if n, convErr := strconv.Atoi(name); convErr == nil && n > 0 {
+			return n - 1
+		}
+		if i, ok := header[strings.ToLower(strings.TrimSpace(name))]; ok && isHeader {
+			return i
+		}
+		err = fmt.Errorf("csvimport: column %q not found in header", name)
+		return -1
+	}
+	cols.title = resolve(m.Title)
+	cols.priority = resolve(m.Priority)
+	cols.tags = resolve(m.Tags)
+	cols.due = resolve(m.DueDate)
+	if err != nil {
+		return columns{}, false, err
+	}
+	return cols, isHeader, checkTitle(cols.title)
+}
+
+func checkTitle(col int) error {
+	if col < 0 {
+		return errors.New("csvimport: no title column (map one with title=COLUMN)")
+	}
+	return nil
+}
+
+func detect(header map[string]int, names []string) int {
+	for _, n := range names {
+		if i, ok := header[n]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+func cell(rec []string, i int) string {
+	if i < 0 || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func blank(rec []string) bool {
+	for _, c := range rec {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func normTitle(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), " ")) }
+
+// ParseMapping parses "title=Action Item,priority=3,due=Deadline".
+func ParseMapping(s string) (Mapping, error) {
+	var m Mapping
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return m, fmt.Errorf("csvimport: bad mapping %q (want field=column)", part)
+		}
+		v = strings.TrimSpace(v)
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "title":
+			m.Title = v
+		case "priority":
+			m.Priority = v
+		case "tags":
+			m.Tags = v
+		case "due", "duedate":
+			m.DueDate = v
+		default:
+			return m, fmt.Errorf("csvimport: unknown field %q (want title, priority, tags or due)", k)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,118 @@
+package csvimport
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("c%d", s.n))
+}
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func TestDecode_AutoDetectsHeader(t *testing.T) {
+	in := "Task,Priority,Labels,Deadline\n" +
+		"Send invoice,High,finance;Q4,2026-10-31\n" +
+		"Book venue,m,,\n"
+
+	res, err := Decode(strings.NewReader(in), Options{}, &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(res.Rejected) != 0 || len(res.Todos) != 2 {
+		t.Fatalf("todos=%d rejected=%+v", len(res.Todos), res.Rejected)
+	}
+	inv := res.Todos[0]
+	if inv.Title != "Send invoice" || inv.Priority != todo.PriorityHigh || !inv.Tags.Contains("q4") || inv.DueDate.String() != "2026-10-31" {
+		t.Fatalf("todo=%+v", inv)
+	}
+	if res.Todos[1].Priority != todo.PriorityMedium || res.Lines[1] != 3 {
+		t.Fatalf("todo=%+v line=%d", res.Todos[1], res.Lines[1])
+	}
+}
+
+func TestDecode_MappingLayoutsAndRejections(t *testing.T) {
+	in := "ID;Action Item;Owner;When;Prio;Area\n" +
+		"1;Review budget;ann;31/10/2026;low;finance|ops\n" +
+		"2;;bob;01/11/2026;low;\n" +
+		"3;Call supplier;cy;2026-13-45;low;\n" +
+		"4;Plan offsite;dee;01/11/2026;urgentish;\n" +
+		"5;Existing item;eve;;;\n" +
+		"6;review BUDGET;fay;;;\n"
+
+	m, err := ParseMapping("title=Action Item,due=When,priority=Prio,tags=6")
+	if err != nil {
+		t.Fatalf("ParseMapping err=%v", err)
+	}
+	res, err := Decode(strings.NewReader(in), Options{
+		Mapping:        m,
+		Comma:          ';',
+		TagSeparator:   "|",
+		DateLayouts:    []string{"02/01/2006", "2006-01-02"},
+		ExistingTitles: []string{"existing item"},
+	}, &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+
+	if len(res.Todos) != 1 || res.Todos[0].Title != "Review budget" || res.Todos[0].DueDate.String() != "2026-10-31" {
+		t.Fatalf("todos=%+v", res.Todos)
+	}
+	if !res.Todos[0].Tags.Contains("ops") {
+		t.Fatalf("tags=%v", res.Todos[0].Tags)
+	}
+
+	wantLines := map[int]string{3: "invalid title", 4: "invalid due date", 5: "invalid priority", 6: "duplicate title", 7: "duplicate title"}
+	if len(res.Rejected) != len(wantLines) {
+		t.Fatalf("rejected=%+v", res.Rejected)
+	}
+	for _, r := range res.Rejected {
+		if !strings.Contains(r.Reason, wantLines[r.Line]) {
+			t.Fatalf("line %d reason=%q want %q", r.Line, r.Reason, wantLines[r.Line])
+		}
+	}
+}
+
+func TestDecode_MalformedRowsAndMultilineFields(t *testing.T) {
+	in := "Task,Priority,Notes\n" +
+		"Write report,high,\"first line\nsecond line\"\n" +
+		"Fix \"bad\" quote,low,\n" +
+		"Book venue,m,\n" +
+		",low,\n"
+
+	res, err := Decode(strings.NewReader(in), Options{}, &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(res.Todos) != 2 || res.Lines[0] != 2 || res.Lines[1] != 5 {
+		t.Fatalf("todos=%d lines=%v", len(res.Todos), res.Lines)
+	}
+	if len(res.Rejected) != 2 || res.Rejected[0].Line != 4 || !strings.Contains(res.Rejected[0].Reason, "quote") || res.Rejected[1].Line != 6 {
+		t.Fatalf("rejected=%+v", res.Rejected)
+	}
+}
+
+func TestDecode_NoHeaderUsesPositions(t *testing.T) {
+	res, err := Decode(strings.NewReader("Water plants,high,home\n"), Options{}, &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(res.Todos) != 1 || res.Todos[0].Priority != todo.PriorityHigh || res.Lines[0] != 1 {
+		t.Fatalf("res=%+v", res)
+	}
+}
+
+func TestDecode_UnknownColumn(t *testing.T) {
+	_, err := Decode(strings.NewReader("a,b\n"), Options{Mapping: Mapping{Title: "Nope"}, Header: HeaderYes}, &seqIDs{}, now)
+	if err == nil {
+		t.Fatalf("expected error for unknown column")
+	}
+}
@@ -0,0 +1,335 @@
+// Package ical maps todos to and from RFC 5545 VTODO components.
+//
+//	Title       ↔ SUMMARY
+//	DueDate     ↔ DUE;VALUE=DATE
+//	Scheduled   ↔ DTSTART;VALUE=DATE
+//	Status      ↔ STATUS (NEEDS-ACTION / COMPLETED / CANCELLED for deleted)
+//	Priority    ↔ PRIORITY (1 high, 5 medium, 9 low)
+//	Tags        ↔ CATEGORIES
+//	CompletedAt ↔ COMPLETED
+//
+// UIDs are derived from the TodoID ("<id>@gotodo") so re-importing an
+// exported calendar updates todos instead of duplicating them. Foreign UIDs
+// are hashed into a stable TodoID for the same reason.
+package ical
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+const (
+	uidSuffix      = "@gotodo"
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	maxLineOctets  = 75
+
+	propArchived = "X-GOTODO-ARCHIVED"
+)
+
+// UID returns the stable iCalendar UID for a todo.
+func UID(id todo.TodoID) string { return id.String() + uidSuffix }
+
+// TodoIDFromUID inverts UID; foreign UIDs hash to a stable 16-char ID.
+func TodoIDFromUID(uid string) todo.TodoID {
+	if id, ok := strings.CutSuffix(uid, uidSuffix); ok && id != "" {
+		return todo.TodoID(id)
+	}
+	sum := sha1.Sum([]byte(uid))
+	return todo.TodoID(hex.EncodeToString(sum[:8]))
+}
+
+// Encode writes a VCALENDAR holding one VTODO per todo.
+func Encode(w io.Writer, tds []todo.Todo) error {
+	bw := bufio.NewWriter(w)
+	lw := lineWriter{w: bw}
+
+	lw.prop("BEGIN", "VCALENDAR")
+	lw.prop("VERSION", "2.0")
+	lw.prop("PRODID", "-//gotodo//gotodo//EN")
+	for _, td := range tds {
+		encodeTodo(&lw, td)
+	}
+	lw.prop("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+func encodeTodo(lw *lineWriter, td todo.Todo) {
+	lw.prop("BEGIN", "VTODO")
+	lw.prop("UID", UID(td.ID))
+	lw.prop("DTSTAMP", formatTime(td.UpdatedAt))
+	lw.prop("CREATED", formatTime(td.CreatedAt))
+	lw.prop("LAST-MODIFIED", formatTime(td.UpdatedAt))
+	lw.prop("SUMMARY", escapeText(td.Title.String()))
+
+	writeDate(lw, "DTSTART", td.Scheduled)
+	writeDate(lw, "DUE", td.DueDate)
+
+	switch {
+	case td.DeletedAt != nil:
+		lw.prop("STATUS", "CANCELLED")
+	case td.Status == todo.StatusDone || td.Status == todo.StatusArchived:
+		lw.prop("STATUS", "COMPLETED")
+	default:
+		lw.prop("STATUS", "NEEDS-ACTION")
+	}
+
+	lw.prop("PRIORITY", strconv.Itoa(toICalPriority(td.Priority)))
+
+	if len(td.Tags) > 0 {
+		cats := make([]string, len(td.Tags))
+		for i, t := range td.Tags {
+			cats[i] = escapeText(t)
+		}
+		lw.prop("CATEGORIES", strings.Join(cats, ","))
+	}
+	if td.CompletedAt != nil {
+		lw.prop("COMPLETED", formatTime(*td.CompletedAt))
+	}
+	if td.ArchivedAt != nil {
+		lw.prop(propArchived, formatTime(*td.ArchivedAt))
+	}
+	lw.prop("END", "VTODO")
+}
+
+// Decoder reads UTC due and start times as wall-clock times in
+// Location, the zone due dates are entered in.
+type Decoder struct {
+	Location *time.Location
+}
+
+// Decode uses the local time zone.
+func Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	return Decoder{}.Decode(r, ids, now)
+}
+
+func (d Decoder) loc() *time.Location {
+	if d.Location != nil {
+		return d.Location
+	}
+	return time.Local
+}
+
+// Decode reads every VTODO in r. Other components are ignored.
+func (d Decoder) Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out   []todo.Todo
+		cur   []property
+		in    bool
+		depth int // nesting inside a VTODO (VALARM etc.)
+	)
+	for _, raw := range lines {
+		p, err := parseProperty(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO") && !in:
+			in, cur = true, nil
+		case !in:
+			continue
+		case p.name == "BEGIN":
+			depth++
+		case p.name == "END" && depth > 0:
+			depth--
+		case p.name == "END" && strings.EqualFold(p.value, "VTODO"):
+			td, err := d.decodeTodo(cur, ids, now)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, td)
+			in = false
+		case depth == 0:
+			cur = append(cur, p)
+		}
+	}
+	return out, nil
+}
+
+// writeDate writes d as a DATE, or as a floating DATE-TIME (the same
+// wall-clock time in any zone, like DueDate) when it has a time.
+func writeDate(lw *lineWriter, name string, d *todo.DueDate) {
+	switch {
+	case d == nil:
+	case d.HasTime():
+		lw.prop(name, d.In(time.UTC).Format("20060102T150405"))
+	default:
+		lw.prop(name+";VALUE=DATE", d.AsTimeUTC().Format(dateLayout))
+	}
+}
+
+func (d Decoder) decodeTodo(props []property, ids ports.IDGenerator, now time.Time) (todo.Todo, error) {
+	var (
+		uid, summary, status string
+		due, start           *todo.DueDate
+		prio                 int
+		tags                 []string
+		created, modified    *time.Time
+		completed, archived  *time.Time
+	)
+
+	for _, p := range props {
+		switch p.name {
+		case "UID":
+			uid = p.value
+		case "SUMMARY":
+			summary = unescapeText(p.value)
+		case "STATUS":
+			status = strings.ToUpper(p.value)
+		case "PRIORITY":
+			prio, _ = strconv.Atoi(p.value)
+		case "CATEGORIES":
+			for _, c := range splitText(p.value) {
+				tags = append(tags, unescapeText(c))
+			}
+		case "DUE", "DTSTART":
+			v, err := parseDue(p.name, p.value, d.loc())
+			if err != nil {
+				return todo.Todo{}, err
+			}
+			if p.name == "DUE" {
+				due = &v
+			} else {
+				start = &v
+			}
+		case "CREATED":
+			created = parseTimePtr(p.value)
+		case "LAST-MODIFIED":
+			modified = parseTimePtr(p.value)
+		case "COMPLETED":
+			completed = parseTimePtr(p.value)
+		case propArchived:
+			archived = parseTimePtr(p.value)
+		}
+	}
+
+	title, err := todo.NewTitle(summary)
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("ical: VTODO %s: %w", uid, err)
+	}
+
+	id := ids.NewTodoID()
+	if uid != "" {
+		id = TodoIDFromUID(uid)
+	}
+
+	createdAt := now
+	if created != nil {
+		createdAt = *created
+	}
+
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       id,
+		Title:    title,
+		Priority: fromICalPriority(prio),
+		Tags:     todo.NewTags(tags),
+		DueDate:  due,
+		Now:      createdAt,
+
+		Scheduled: start,
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	switch status {
+	case "COMPLETED":
+		at := createdAt
+		if completed != nil {
+			at = *completed
+		}
+		td, _, _ = td.Complete(at)
+		if archived != nil {
+			td, _, _ = td.Archive(*archived)
+		}
+	case "CANCELLED":
+		at := now
+		if modified != nil {
+			at = *modified
+		}
+		td, _, _ = td.SoftDelete(at)
+	}
+
+	if modified != nil && modified.After(td.UpdatedAt) {
+		td.UpdatedAt = *modified
+	}
+	return td, nil
+}
+
+// toICalPriority follows the RFC 5545 convention: 1-4 high, 5 medium, 6-9 low.
+func toICalPriority(p todo.Priority) int {
+	switch p {
+	case todo.PriorityHigh:
+		return 1
+	case todo.PriorityMedium:
+		return 5
+	default:
+		return 9
+	}
+}
+
+func fromICalPriority(n int) todo.Priority {
+	switch {
+	case n >= 1 && n <= 4:
+		return todo.PriorityHigh
+	case n == 5:
+		return todo.PriorityMedium
+	default:
+		return todo.PriorityLow // 0 is "undefined"
+	}
+}
+
+func formatTime(t time.Time) string { return t.UTC().Format(dateTimeLayout) }
+
+// parseTime accepts DATE, UTC DATE-TIME and floating DATE-TIME values.
+func parseTime(v string) (time.Time, error) {
+	for _, layout := range []string{dateTimeLayout, "20060102T150405", dateLayout} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("ical: bad date %q", v)
+}
+
+// parseDue reads a DATE as a day and a DATE-TIME as a time on that day;
+// UTC times are read in loc.
+func parseDue(name, v string, loc *time.Location) (todo.DueDate, error) {
+	t, err := parseTime(v)
+	if err != nil {
+		return todo.DueDate{}, fmt.Errorf("ical: %s %q: %w", name, v, todo.ErrInvalidDueDate)
+	}
+	if strings.HasSuffix(v, "Z") {
+		t = t.In(loc)
+	}
+	layout := "2006-01-02"
+	if len(v) > len(dateLayout) {
+		layout = "2006-01-02T15:04"
+	}
+	return todo.ParseDueDate(t.Format(layout))
+}
+
+func parseTimePtr(v string) *time.Time {
+	t, err := parseTime(v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
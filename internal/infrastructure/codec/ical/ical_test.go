@@ -0,0 +1,171 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type fixedIDs struct{}
+
+func (fixedIDs) NewTodoID() todo.TodoID { return "generated" }
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func mkTodo(t *testing.T, id, title string, pr todo.Priority, tags []string, due string) todo.Todo {
+	t.Helper()
+	tt, err := todo.NewTitle(title)
+	if err != nil {
+		t.Fatalf("bad title: %v", err)
+	}
+	var dd *todo.DueDate
+	if due != "" {
+		d, err := todo.ParseDueDate(due)
+		if err != nil {
+			t.Fatalf("bad due: %v", err)
+		}
+		dd = &d
+	}
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID: todo.TodoID(id), Title: tt, Priority: pr, Tags: todo.NewTags(tags), DueDate: dd,
+		Now: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("NewTodo err=%v", err)
+	}
+	return td
+}
+
+func TestEncode_MapsFields(t *testing.T) {
+	active := mkTodo(t, "a1", "Buy milk, eggs; bread", todo.PriorityHigh, []string{"home", "errands"}, "2026-10-20")
+	done := mkTodo(t, "d1", "Write report", todo.PriorityMedium, nil, "")
+	done, _, _ = done.Complete(time.Date(2026, 10, 2, 9, 30, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, []todo.Todo{active, done}); err != nil {
+		t.Fatalf("Encode err=%v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"UID:a1@gotodo\r\n",
+		`SUMMARY:Buy milk\, eggs\; bread` + "\r\n",
+		"DUE;VALUE=DATE:20261020\r\n",
+		"STATUS:NEEDS-ACTION\r\n",
+		"PRIORITY:1\r\n",
+		"CATEGORIES:errands,home\r\n",
+		"UID:d1@gotodo\r\n",
+		"STATUS:COMPLETED\r\n",
+		"PRIORITY:5\r\n",
+		"COMPLETED:20261002T093000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRoundTrip_KeepsIDs(t *testing.T) {
+	long := strings.Repeat("very long title ", 10)
+	in := []todo.Todo{
+		mkTodo(t, "a1", long, todo.PriorityLow, []string{"x"}, "2026-10-20"),
+		mkTodo(t, "a2", "Ünïcödé tïtlé with ëmojï 🎉 and more text to force folding here", todo.PriorityHigh, nil, "2026-10-21T17:30"),
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, in); err != nil {
+		t.Fatalf("Encode err=%v", err)
+	}
+	for _, l := range strings.Split(buf.String(), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Fatalf("line not folded (%d octets): %q", len(l), l)
+		}
+	}
+
+	got, err := Decode(&buf, fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len=%d want=2", len(got))
+	}
+	for i := range in {
+		if got[i].ID != in[i].ID || got[i].Title != in[i].Title || got[i].Priority != in[i].Priority {
+			t.Fatalf("got=%+v want=%+v", got[i], in[i])
+		}
+	}
+	if got[0].DueDate == nil || got[0].DueDate.String() != "2026-10-20" {
+		t.Fatalf("due=%v", got[0].DueDate)
+	}
+	if got[1].DueDate == nil || got[1].DueDate.String() != "2026-10-21T17:30" {
+		t.Fatalf("due time=%v", got[1].DueDate)
+	}
+}
+
+func TestDecode_ForeignCalendar(t *testing.T) {
+	cal := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:not a todo",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:19970901T130000Z-123404@example.com",
+		"SUMMARY:Submit Quebec",
+		"  Income Tax Return",
+		"DUE:20261015T235959Z",
+		"STATUS:CANCELLED",
+		"PRIORITY:2",
+		"CATEGORIES:FAMILY,FINANCE",
+		"BEGIN:VALARM",
+		"SUMMARY:alarm text",
+		"END:VALARM",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(cal), fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("len=%d want=1", len(got))
+	}
+	td := got[0]
+	if td.Title != "Submit Quebec Income Tax Return" {
+		t.Fatalf("title=%q", td.Title)
+	}
+	if td.ID != TodoIDFromUID("19970901T130000Z-123404@example.com") || len(td.ID) != 16 {
+		t.Fatalf("id=%q not stable", td.ID)
+	}
+	if td.DeletedAt == nil {
+		t.Fatalf("CANCELLED should soft-delete")
+	}
+	if td.Priority != todo.PriorityHigh || !td.Tags.Contains("finance") {
+		t.Fatalf("td=%+v", td)
+	}
+	// a DATE-TIME keeps its time, read in the local zone
+	want := time.Date(2026, 10, 15, 23, 59, 59, 0, time.UTC).In(time.Local).Format("2006-01-02T15:04")
+	if td.DueDate == nil || td.DueDate.String() != want {
+		t.Fatalf("due=%v want=%s", td.DueDate, want)
+	}
+}
+
+func TestDecoder_Location(t *testing.T) {
+	cal := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a@gotodo\r\nSUMMARY:Call\r\nDUE:20261016T023000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	got, err := Decoder{Location: ny}.Decode(strings.NewReader(cal), fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	// 02:30 UTC is still the evening before in New York
+	if got[0].DueDate == nil || got[0].DueDate.String() != "2026-10-15T22:30" {
+		t.Fatalf("due=%v", got[0].DueDate)
+	}
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type property struct {
+	name   string // upper-cased, without parameters
+	params string
+	value  string
+}
+
+// parseProperty splits "NAME;PARAM=x:VALUE". Colons inside quoted
+// parameter values are skipped.
+func parseProperty(line string) (property, error) {
+	quoted := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if quoted {
+				continue
+			}
+			head, value := line[:i], line[i+1:]
+			name, params, _ := strings.Cut(head, ";")
+			return property{name: strings.ToUpper(name), params: params, value: value}, nil
+		}
+	}
+	return property{}, fmt.Errorf("ical: malformed line %q", line)
+}
+
+// unfold joins RFC 5545 continuation lines (CRLF followed by a space or tab).
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if l == "" {
+			continue
+		}
+		if (l[0] == ' ' || l[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// prop writes "name:value" folded at 75 octets without splitting UTF-8.
+func (lw *lineWriter) prop(name, value string) {
+	if lw.err != nil {
+		return
+	}
+	line := name + ":" + value
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		if _, lw.err = lw.w.WriteString(line[:cut] + "\r\n "); lw.err != nil {
+			return
+		}
+		line = line[cut:]
+		limit = maxLineOctets - 1 // the leading space counts
+	}
+	_, lw.err = lw.w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitText splits a multi-valued TEXT property on unescaped commas.
+func splitText(s string) []string {
+	var (
+		out   []string
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
@@ -0,0 +1,202 @@
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type GroupBy string
+
+const (
+	GroupByStatus GroupBy = "status"
+	GroupByTag    GroupBy = "tag"
+)
+
+const untagged = "Untagged"
+
+// Encoder writes todos as task lists under one "##" heading per group.
+type Encoder struct {
+	GroupBy GroupBy
+}
+
+// Encode writes todos grouped by status.
+func Encode(w io.Writer, tds []todo.Todo) error {
+	return Encoder{GroupBy: GroupByStatus}.Encode(w, tds)
+}
+
+func (e Encoder) Encode(w io.Writer, tds []todo.Todo) error {
+	groups, order := e.group(tds)
+
+	bw := bufio.NewWriter(w)
+	for i, name := range order {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintf(bw, "## %s\n\n", name)
+		writeTree(bw, groups[name])
+	}
+	return bw.Flush()
+}
+
+func (e Encoder) group(tds []todo.Todo) (map[string][]todo.Todo, []string) {
+	groups := map[string][]todo.Todo{}
+	var order []string
+
+	add := func(name string, td todo.Todo) {
+		if _, ok := groups[name]; !ok {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], td)
+	}
+
+	for _, td := range tds {
+		if td.DeletedAt != nil {
+			continue
+		}
+		switch e.GroupBy {
+		case GroupByTag:
+			// first tag only, so every todo (and its ID) appears once
+			if len(td.Tags) == 0 {
+				add(untagged, td)
+			} else {
+				add(td.Tags[0], td)
+			}
+		default:
+			add(statusHeading(td.Status), td)
+		}
+	}
+
+	if e.GroupBy == GroupByTag {
+		sort.Slice(order, func(i, j int) bool {
+			if order[i] == untagged || order[j] == untagged {
+				return order[j] == untagged && order[i] != untagged
+			}
+			return order[i] < order[j]
+		})
+	} else {
+		rank := map[string]int{"Active": 0, "Done": 1, "Archived": 2}
+		sort.Slice(order, func(i, j int) bool { return rank[order[i]] < rank[order[j]] })
+	}
+	return groups, order
+}
+
+func statusHeading(s todo.Status) string {
+	switch s {
+	case todo.StatusDone:
+		return "Done"
+	case todo.StatusArchived:
+		return "Archived"
+	default:
+		return "Active"
+	}
+}
+
+// writeTree nests each todo under its parent when the parent is in tds.
+func writeTree(w io.Writer, tds []todo.Todo) {
+	present := make(map[todo.TodoID]bool, len(tds))
+	for _, td := range tds {
+		present[td.ID] = true
+	}
+	children := map[todo.TodoID][]todo.Todo{}
+	var roots []todo.Todo
+	for _, td := range tds {
+		if td.ParentID.Valid() && present[td.ParentID] && td.ParentID != td.ID {
+			children[td.ParentID] = append(children[td.ParentID], td)
+			continue
+		}
+		roots = append(roots, td)
+	}
+
+	seen := map[todo.TodoID]bool{}
+	var walk func(td todo.Todo, depth int)
+	walk = func(td todo.Todo, depth int) {
+		if seen[td.ID] {
+			return // parent cycle in stored data
+		}
+		seen[td.ID] = true
+		fmt.Fprintf(w, "%s- %s\n", strings.Repeat("  ", depth), FormatItem(td))
+		for _, c := range children[td.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		walk(r, 0)
+	}
+}
+
+// FormatItem renders td as "[ ] title #tag @due <!-- gotodo:id -->",
+// i.e. everything after the bullet.
+func FormatItem(td todo.Todo) string {
+	var b strings.Builder
+	if td.Status == todo.StatusActive {
+		b.WriteString("[ ] ")
+	} else {
+		b.WriteString("[x] ")
+	}
+	b.WriteString(escape(td.Title.String()))
+	for _, t := range td.Tags {
+		b.WriteString(" #" + strings.ReplaceAll(t, " ", "-"))
+	}
+	if td.DueDate != nil {
+		b.WriteString(" @" + td.DueDate.String())
+	}
+	b.WriteString(" <!-- gotodo:" + td.ID.String() + " -->")
+	return b.String()
+}
+
+// Decode imports every checklist item in r. Items without an ID comment
+// get a fresh ID; nested items become subtasks of the enclosing item.
+func Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	doc, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]todo.Todo, 0, len(doc.Items))
+	for i, it := range doc.Items {
+		id := it.ID
+		if !id.Valid() {
+			id = ids.NewTodoID()
+		}
+		var parent todo.TodoID
+		if it.Parent >= 0 {
+			parent = out[it.Parent].ID
+		}
+		td, err := newFromItem(it, id, parent, now)
+		if err != nil {
+			return nil, fmt.Errorf("markdown: line %d: %w", doc.Items[i].line+1, err)
+		}
+		out = append(out, td)
+	}
+	return out, nil
+}
+
+func newFromItem(it Item, id, parent todo.TodoID, now time.Time) (todo.Todo, error) {
+	title, err := todo.NewTitle(it.Title)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       id,
+		Title:    title,
+		Priority: todo.PriorityLow,
+		Tags:     todo.NewTags(it.Tags),
+		DueDate:  it.Due,
+		ParentID: parent,
+		Now:      now,
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if it.Done {
+		td, _, err = td.Complete(now)
+	}
+	return td, err
+}
@@ -0,0 +1,192 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("n%d", s.n))
+}
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func mkTodo(t *testing.T, id, title string, tags []string, updated time.Time) todo.Todo {
+	t.Helper()
+	tt, err := todo.NewTitle(title)
+	if err != nil {
+		t.Fatalf("bad title: %v", err)
+	}
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID: todo.TodoID(id), Title: tt, Priority: todo.PriorityLow, Tags: todo.NewTags(tags), Now: updated,
+	})
+	if err != nil {
+		t.Fatalf("NewTodo err=%v", err)
+	}
+	return td
+}
+
+func TestDecode_NestedAndInlineSyntax(t *testing.T) {
+	md := strings.Join([]string{
+		"# Project",
+		"",
+		"Some prose with a - [ ] inside text that is not an item.",
+		"- [ ] Ship release #work @2026-10-20",
+		"  - [x] Write changelog <!-- gotodo:abc -->",
+		"  - [ ] Fix issue #123 and \\#literal",
+		"* [X] Buy milk #Home",
+		"```",
+		"- [ ] not parsed inside code fence",
+		"```",
+	}, "\n")
+
+	tds, err := Decode(strings.NewReader(md), &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if len(tds) != 4 {
+		t.Fatalf("len=%d want=4", len(tds))
+	}
+
+	ship, changelog, fix, milk := tds[0], tds[1], tds[2], tds[3]
+	if ship.Title != "Ship release" || !ship.Tags.Contains("work") || ship.DueDate == nil || ship.DueDate.String() != "2026-10-20" {
+		t.Fatalf("ship=%+v", ship)
+	}
+	if changelog.ID != "abc" || changelog.ParentID != ship.ID || changelog.Status != todo.StatusDone {
+		t.Fatalf("changelog=%+v", changelog)
+	}
+	// any "#word" is a tag, as the encoder writes them; only "\#" keeps one in the title
+	if fix.Title != "Fix issue and #literal" || !fix.Tags.Contains("123") || fix.ParentID != ship.ID {
+		t.Fatalf("fix=%+v", fix)
+	}
+	if milk.Status != todo.StatusDone || !milk.Tags.Contains("home") || milk.ParentID.Valid() {
+		t.Fatalf("milk=%+v", milk)
+	}
+}
+
+func TestEncode_GroupByTagRoundTrips(t *testing.T) {
+	parent := mkTodo(t, "p", "Ship #1 release", []string{"work"}, now)
+	child := mkTodo(t, "c", "Write notes", []string{"work"}, now)
+	child.ParentID = "p"
+	home := mkTodo(t, "h", "Buy milk", nil, now)
+
+	var buf bytes.Buffer
+	if err := (Encoder{GroupBy: GroupByTag}).Encode(&buf, []todo.Todo{parent, child, home}); err != nil {
+		t.Fatalf("Encode err=%v", err)
+	}
+	want := strings.Join([]string{
+		"## work",
+		"",
+		`- [ ] Ship \#1 release #work <!-- gotodo:p -->`,
+		"  - [ ] Write notes #work <!-- gotodo:c -->",
+		"",
+		"## Untagged",
+		"",
+		"- [ ] Buy milk <!-- gotodo:h -->",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	back, err := Decode(&buf, &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if back[0].Title != parent.Title || back[1].ParentID != "p" || back[2].ID != "h" {
+		t.Fatalf("back=%+v", back)
+	}
+}
+
+func TestFormatItem_TagsRoundTrip(t *testing.T) {
+	td := mkTodo(t, "t1", "Plan #2026 budget", []string{"c++", "café", "2026", "q4/ops"}, now)
+
+	doc, err := Parse(strings.NewReader("- " + FormatItem(td) + "\n"))
+	if err != nil || len(doc.Items) != 1 {
+		t.Fatalf("Parse err=%v doc=%+v", err, doc)
+	}
+	it := doc.Items[0]
+	if it.Title != td.Title.String() || !slices.Equal(todo.NewTags(it.Tags), td.Tags) {
+		t.Fatalf("title=%q tags=%v want %q %v", it.Title, it.Tags, td.Title, td.Tags)
+	}
+}
+
+func TestSync_TwoWay(t *testing.T) {
+	const file = "/repo/README.md"
+	before := now.Add(-time.Hour)
+
+	edited := mkTodo(t, "e", "Old title", nil, before.Add(-time.Hour))
+	edited.Meta = map[string]string{MetaFile: file}
+	storeWins := mkTodo(t, "s", "Store title", nil, now) // updated after the file
+	storeWins.Meta = map[string]string{MetaFile: file}
+	removed := mkTodo(t, "r", "Removed from file", nil, before.Add(-time.Hour))
+	removed.Meta = map[string]string{MetaFile: file}
+	fresh := mkTodo(t, "f", "Added in store", nil, before)
+	gone := mkTodo(t, "g", "Deleted in store", nil, before.Add(-time.Hour))
+	gone, _, _ = gone.SoftDelete(before)
+
+	md := strings.Join([]string{
+		"# Notes",
+		"- [x] New title <!-- gotodo:e -->",
+		"- [ ] Stale title <!-- gotodo:s -->",
+		"- [ ] Brand new item",
+		"- [ ] Deleted in store <!-- gotodo:g -->",
+		"",
+		"Footer text.",
+	}, "\n")
+	doc, err := Parse(strings.NewReader(md))
+	if err != nil {
+		t.Fatalf("Parse err=%v", err)
+	}
+
+	plan, err := Sync(doc, []todo.Todo{edited, storeWins, removed, fresh, gone}, SyncOptions{
+		File: file, ModTime: before, Now: now, IDs: &seqIDs{},
+	})
+	if err != nil {
+		t.Fatalf("Sync err=%v", err)
+	}
+
+	if len(plan.Create) != 1 || plan.Create[0].Title != "Brand new item" {
+		t.Fatalf("create=%+v", plan.Create)
+	}
+	if len(plan.Delete) != 1 || plan.Delete[0] != "r" {
+		t.Fatalf("delete=%v", plan.Delete)
+	}
+
+	updated := map[todo.TodoID]todo.Todo{}
+	for _, td := range plan.Update {
+		updated[td.ID] = td
+	}
+	if e := updated["e"]; e.Title != "New title" || e.Status != todo.StatusDone {
+		t.Fatalf("file edit not applied: %+v", e)
+	}
+	if _, ok := updated["s"]; ok {
+		t.Fatalf("store-newer todo should not be updated from the file")
+	}
+	if f := updated["f"]; f.Meta[MetaFile] != file {
+		t.Fatalf("appended todo not marked: %+v", f)
+	}
+
+	wantOut := strings.Join([]string{
+		"# Notes",
+		"- [x] New title <!-- gotodo:e -->",
+		"- [ ] Store title <!-- gotodo:s -->",
+		"- [ ] Brand new item <!-- gotodo:n1 -->",
+		"- [ ] Added in store <!-- gotodo:f -->",
+		"",
+		"Footer text.",
+		"",
+	}, "\n")
+	if plan.Output != wantOut {
+		t.Fatalf("output:\n%s\nwant:\n%s", plan.Output, wantOut)
+	}
+}
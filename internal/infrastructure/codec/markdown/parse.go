@@ -0,0 +1,147 @@
+// Package markdown maps todos to and from GitHub-flavored task lists.
+//
+//	- [ ] Buy milk #home @2026-10-20 <!-- gotodo:4f2a9c1e -->
+//	  - [x] Find wallet <!-- gotodo:77b01d3a -->
+//
+// "#tag" (anything up to the next space) becomes a tag, "@YYYY-MM-DD" the
+// due date and nested items become subtasks. The trailing HTML comment
+// carries the TodoID so a file can be synced with the store repeatedly
+// without duplicating items. A literal "#tag" or "@date" in a title is
+// written as "\#tag" / "\@date".
+package markdown
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+var (
+	itemRe = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+\[([ xX])\]\s?(.*)$`)
+	idRe   = regexp.MustCompile(`\s*<!--\s*gotodo:([A-Za-z0-9_-]+)\s*-->\s*$`)
+	tagRe  = regexp.MustCompile(`^#(\S+)$`) // whatever the encoder writes after "#"
+	dueRe  = regexp.MustCompile(`^@(\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2})?)$`)
+)
+
+// Item is one checklist entry of a parsed document.
+type Item struct {
+	ID     todo.TodoID // empty when the line has no ID comment yet
+	Title  string
+	Done   bool
+	Tags   []string
+	Due    *todo.DueDate
+	Parent int // index into Doc.Items, -1 for top-level items
+
+	line   int    // index into Doc.Lines
+	indent string // leading whitespace
+	bullet string // "-", "*", "1." ...
+}
+
+// Doc is a Markdown file split into lines, with checklist items indexed.
+// Non-checklist lines are kept verbatim so sync can rewrite items in place.
+type Doc struct {
+	Lines []string
+	Items []Item
+}
+
+// Parse reads a Markdown document. Invalid inline dates are left in the
+// title rather than failing the whole file.
+func Parse(r io.Reader) (*Doc, error) {
+	doc := &Doc{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var stack []int // open items by increasing indent
+	inFence := false
+	for sc.Scan() {
+		line := sc.Text()
+		doc.Lines = append(doc.Lines, line)
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		m := itemRe.FindStringSubmatch(line)
+		if inFence || m == nil {
+			continue
+		}
+
+		it := parseItem(m[4])
+		it.Done = m[3] != " "
+		it.line = len(doc.Lines) - 1
+		it.indent = m[1]
+		it.bullet = m[2]
+
+		width := indentWidth(it.indent)
+		for len(stack) > 0 && indentWidth(doc.Items[stack[len(stack)-1]].indent) >= width {
+			stack = stack[:len(stack)-1]
+		}
+		it.Parent = -1
+		if len(stack) > 0 {
+			it.Parent = stack[len(stack)-1]
+		}
+
+		doc.Items = append(doc.Items, it)
+		stack = append(stack, len(doc.Items)-1)
+	}
+	return doc, sc.Err()
+}
+
+func parseItem(text string) Item {
+	var it Item
+	if m := idRe.FindStringSubmatch(text); m != nil {
+		it.ID = todo.TodoID(m[1])
+		text = text[:len(text)-len(m[0])]
+	}
+
+	var words []string
+	for _, tok := range strings.Fields(text) {
+		if m := tagRe.FindStringSubmatch(tok); m != nil {
+			it.Tags = append(it.Tags, m[1])
+			continue
+		}
+		if m := dueRe.FindStringSubmatch(tok); m != nil {
+			if d, err := todo.ParseDueDate(m[1]); err == nil {
+				it.Due = &d
+				continue
+			}
+		}
+		words = append(words, unescape(tok))
+	}
+	it.Title = strings.Join(words, " ")
+	return it
+}
+
+func indentWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		if r == '\t' {
+			n += 4
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// escape protects title words that would otherwise parse as tags or dates.
+func escape(title string) string {
+	words := strings.Split(title, " ")
+	for i, w := range words {
+		if tagRe.MatchString(w) || dueRe.MatchString(w) {
+			words[i] = `\` + w
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func unescape(tok string) string {
+	if strings.HasPrefix(tok, `\#`) || strings.HasPrefix(tok, `\@`) {
+		return tok[1:]
+	}
+	return tok
+}
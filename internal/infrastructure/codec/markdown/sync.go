@@ -0,0 +1,222 @@
+package markdown
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// MetaFile records which Markdown file a todo was last synced into, so an
+// item later removed from that file can be told apart from a todo that
+// was simply added to the store since.
+const MetaFile = "markdown.file"
+
+type SyncOptions struct {
+	File    string    // absolute path of the document
+	ModTime time.Time // last write of the document; newer edits win
+	Now     time.Time
+	IDs     ports.IDGenerator
+	Tag     string // optional: only todos with this tag take part
+}
+
+// SyncPlan is what Sync wants done to the store, plus the rewritten file.
+type SyncPlan struct {
+	Create []todo.Todo
+	Update []todo.Todo
+	Delete []todo.TodoID
+	Output string
+}
+
+// Sync reconciles doc with the store by embedded ID comments.
+//
+// Items are matched to todos by ID. When both differ, the document wins if
+// it was written after the todo's last update, otherwise the store wins.
+// Items without an ID (or with an unknown one) are created. Todos deleted
+// in the store drop out of the document; todos previously synced into this
+// file but removed from it are deleted; other in-scope todos are appended.
+// Lines that aren't checklist items are left untouched.
+func Sync(doc *Doc, store []todo.Todo, opts SyncOptions) (SyncPlan, error) {
+	var plan SyncPlan
+
+	byID := make(map[todo.TodoID]todo.Todo, len(store))
+	for _, td := range store {
+		byID[td.ID] = td
+	}
+
+	resolved := make([]*todo.Todo, len(doc.Items))
+	seen := map[todo.TodoID]bool{}
+
+	for i, it := range doc.Items {
+		var parent todo.TodoID
+		if it.Parent >= 0 && resolved[it.Parent] != nil {
+			parent = resolved[it.Parent].ID
+		}
+
+		st, ok := byID[it.ID]
+		if it.ID.Valid() && ok && !seen[it.ID] {
+			seen[it.ID] = true
+			if st.DeletedAt != nil {
+				continue // deleted in the store: drop the line
+			}
+
+			changed := false
+			if differs(it, st, parent, opts.Tag) && opts.ModTime.After(st.UpdatedAt) {
+				next, err := applyItem(st, it, parent, opts)
+				if err != nil {
+					return SyncPlan{}, err
+				}
+				st, changed = next, true
+			}
+			if st.Meta[MetaFile] != opts.File {
+				st.Meta = withMeta(st.Meta, MetaFile, opts.File)
+				changed = true
+			}
+			if changed {
+				plan.Update = append(plan.Update, st)
+			}
+			resolved[i] = &st
+			continue
+		}
+
+		id := it.ID
+		if !id.Valid() || seen[id] {
+			id = opts.IDs.NewTodoID()
+		}
+		seen[id] = true
+		it.Tags = withTag(it.Tags, opts.Tag)
+		td, err := newFromItem(it, id, parent, opts.Now)
+		if err != nil {
+			return SyncPlan{}, err
+		}
+		td.Meta = withMeta(nil, MetaFile, opts.File)
+		plan.Create = append(plan.Create, td)
+		resolved[i] = &td
+	}
+
+	// store todos missing from the document
+	var appended []todo.Todo
+	for _, td := range store {
+		if seen[td.ID] || td.DeletedAt != nil || (opts.Tag != "" && !td.Tags.Contains(opts.Tag)) {
+			continue
+		}
+		if td.Meta[MetaFile] == opts.File {
+			plan.Delete = append(plan.Delete, td.ID)
+			continue
+		}
+		td.Meta = withMeta(td.Meta, MetaFile, opts.File)
+		plan.Update = append(plan.Update, td)
+		appended = append(appended, td)
+	}
+
+	plan.Output = render(doc, resolved, appended)
+	return plan, nil
+}
+
+func render(doc *Doc, resolved []*todo.Todo, appended []todo.Todo) string {
+	byLine := make(map[int]int, len(doc.Items))
+	last := -1
+	for i, it := range doc.Items {
+		byLine[it.line] = i
+		last = it.line
+	}
+
+	var b strings.Builder
+	writeAppended := func() {
+		if len(appended) > 0 {
+			writeTree(&b, appended)
+		}
+	}
+
+	if last < 0 {
+		for _, l := range doc.Lines {
+			b.WriteString(l + "\n")
+		}
+		if len(doc.Lines) > 0 && len(appended) > 0 && strings.TrimSpace(doc.Lines[len(doc.Lines)-1]) != "" {
+			b.WriteString("\n")
+		}
+		writeAppended()
+		return b.String()
+	}
+
+	for n, l := range doc.Lines {
+		i, isItem := byLine[n]
+		switch {
+		case !isItem:
+			b.WriteString(l + "\n")
+		case resolved[i] != nil:
+			it := doc.Items[i]
+			b.WriteString(it.indent + it.bullet + " " + FormatItem(*resolved[i]) + "\n")
+		}
+		if n == last {
+			writeAppended()
+		}
+	}
+	return b.String()
+}
+
+func differs(it Item, td todo.Todo, parent todo.TodoID, scopeTag string) bool {
+	if it.Title != td.Title.String() {
+		return true
+	}
+	if it.Done != (td.Status != todo.StatusActive) {
+		return true
+	}
+	if !slices.Equal([]string(todo.NewTags(withTag(it.Tags, scopeTag))), []string(td.Tags)) {
+		return true
+	}
+	if (it.Due == nil) != (td.DueDate == nil) || (it.Due != nil && *it.Due != *td.DueDate) {
+		return true
+	}
+	return parent != td.ParentID
+}
+
+// applyItem moves td to the state described by the document item.
+func applyItem(td todo.Todo, it Item, parent todo.TodoID, opts SyncOptions) (todo.Todo, error) {
+	now := opts.Now
+
+	title, err := todo.NewTitle(it.Title)
+	if err != nil {
+		return td, err
+	}
+	if td, _, err = td.ChangeTitle(title, now); err != nil {
+		return td, err
+	}
+
+	switch {
+	case it.Done && td.Status == todo.StatusActive:
+		td, _, err = td.Complete(now)
+	case !it.Done && td.Status == todo.StatusDone:
+		td, _, err = td.Reopen(now)
+	case !it.Done && td.Status == todo.StatusArchived:
+		td, _, err = td.Restore(now)
+	}
+	if err != nil {
+		return td, err
+	}
+
+	td.Tags = todo.NewTags(withTag(it.Tags, opts.Tag))
+	td.DueDate = it.Due
+	td.ParentID = parent
+	td.UpdatedAt = now
+	return td, nil
+}
+
+func withTag(tags []string, tag string) []string {
+	if tag == "" || todo.NewTags(tags).Contains(tag) {
+		return tags
+	}
+	return append(slices.Clone(tags), tag)
+}
+
+func withMeta(m map[string]string, k, v string) map[string]string {
+	out := maps.Clone(m)
+	if out == nil {
+		out = map[string]string{}
+	}
+	out[k] = v
+	return out
+}
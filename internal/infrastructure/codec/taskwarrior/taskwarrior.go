@@ -0,0 +1,372 @@
+// Package taskwarrior maps Taskwarrior's `task export` / `task import`
+// JSON onto todos.
+//
+//	status   pending, waiting, recurring → active; completed → done;
+//	         deleted → soft-deleted
+//	priority H → high, M → medium, L or none → low
+//	entry, modified, end → CreatedAt, UpdatedAt, CompletedAt / DeletedAt
+//	due, wait → DueDate, Scheduled
+//
+// Taskwarrior UUIDs become TodoIDs (lowercased). Native 16-hex-digit IDs are
+// embedded in a recognizable UUID on export and recovered on import, so
+// both directions keep identities stable. Other attributes (project,
+// annotations, UDAs...) ride along in Todo.Meta as "taskwarrior.<name>".
+package taskwarrior
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+const (
+	timeLayout = "20060102T150405Z"
+	metaPrefix = "taskwarrior."
+
+	// "gotodo" in hex; marks UUIDs that wrap a native 16-hex-digit TodoID.
+	nativePrefix = "676f746f-646f-4000-"
+)
+
+var (
+	nativeIDRe = regexp.MustCompile(`^[0-9a-f]{16}$`)
+	uuidRe     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// known attributes; everything else is preserved in Meta.
+// "id" (working-set index) and "urgency" are derived by Taskwarrior and dropped.
+var known = map[string]bool{
+	"uuid": true, "description": true, "status": true, "priority": true, "tags": true,
+	"due": true, "wait": true, "entry": true, "modified": true, "end": true, "id": true, "urgency": true,
+}
+
+type task map[string]json.RawMessage
+
+// Codec converts dates in Location, which should be the zone Taskwarrior
+// runs in: it stores a due *date* as local midnight in UTC.
+type Codec struct {
+	Location *time.Location
+}
+
+// Decode uses the local time zone.
+func Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	return Codec{}.Decode(r, ids, now)
+}
+
+// Encode uses the local time zone.
+func Encode(w io.Writer, tds []todo.Todo) error { return Codec{}.Encode(w, tds) }
+
+func (c Codec) loc() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	}
+	return time.Local
+}
+
+// Decode accepts a JSON array (task export) or one object per line
+// (the older export format).
+func (c Codec) Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []task
+	trimmed := strings.TrimSpace(string(b))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(b, &tasks); err != nil {
+			return nil, fmt.Errorf("taskwarrior: %w", err)
+		}
+	} else {
+		for i, line := range strings.Split(trimmed, "\n") {
+			line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if line == "" {
+				continue
+			}
+			var t task
+			if err := json.Unmarshal([]byte(line), &t); err != nil {
+				return nil, fmt.Errorf("taskwarrior: line %d: %w", i+1, err)
+			}
+			tasks = append(tasks, t)
+		}
+	}
+
+	out := make([]todo.Todo, 0, len(tasks))
+	for i, t := range tasks {
+		td, err := c.decodeTask(t, ids, now)
+		if err != nil {
+			return nil, fmt.Errorf("taskwarrior: task %d: %w", i+1, err)
+		}
+		out = append(out, td)
+	}
+	return out, nil
+}
+
+func (c Codec) decodeTask(t task, ids ports.IDGenerator, now time.Time) (todo.Todo, error) {
+	var (
+		uid, desc, status, prio string
+		tags                    []string
+	)
+	if err := t.get("uuid", &uid); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := t.get("description", &desc); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := t.get("status", &status); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := t.get("priority", &prio); err != nil {
+		return todo.Todo{}, err
+	}
+	if err := t.get("tags", &tags); err != nil {
+		return todo.Todo{}, err
+	}
+	entry, err := t.time("entry")
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	modified, err := t.time("modified")
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	end, err := t.time("end")
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	dueAt, err := t.time("due")
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	waitAt, err := t.time("wait")
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	title, err := todo.NewTitle(desc)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	id := ids.NewTodoID()
+	if uid != "" {
+		id = TodoIDFromUUID(uid)
+	}
+
+	due, err := c.date(dueAt)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	wait, err := c.date(waitAt)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	createdAt := now
+	if entry != nil {
+		createdAt = *entry
+	}
+
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       id,
+		Title:    title,
+		Priority: fromTWPriority(prio),
+		Tags:     todo.NewTags(tags),
+		DueDate:  due,
+		Now:      createdAt,
+
+		Scheduled: wait,
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	finished := createdAt
+	if end != nil {
+		finished = *end
+	} else if modified != nil {
+		finished = *modified
+	}
+	switch status {
+	case "completed":
+		td, _, err = td.Complete(finished)
+	case "deleted":
+		td, _, err = td.SoftDelete(finished)
+	case "", "pending", "waiting", "recurring":
+	default:
+		return todo.Todo{}, fmt.Errorf("unknown status %q", status)
+	}
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if modified != nil {
+		td.UpdatedAt = *modified
+	}
+
+	for k, v := range t {
+		if known[k] {
+			continue
+		}
+		if td.Meta == nil {
+			td.Meta = map[string]string{}
+		}
+		td.Meta[metaPrefix+k] = string(v)
+	}
+	return td, nil
+}
+
+// Encode writes a JSON array accepted by `task import`.
+func (c Codec) Encode(w io.Writer, tds []todo.Todo) error {
+	tasks := make([]map[string]any, 0, len(tds))
+	for _, td := range tds {
+		tasks = append(tasks, c.encodeTask(td))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tasks)
+}
+
+func (c Codec) encodeTask(td todo.Todo) map[string]any {
+	t := map[string]any{}
+
+	// preserved attributes first so the typed fields below win on conflict
+	for _, k := range slices.Sorted(maps.Keys(td.Meta)) {
+		name, ok := strings.CutPrefix(k, metaPrefix)
+		if !ok || known[name] {
+			continue
+		}
+		t[name] = json.RawMessage(td.Meta[k])
+	}
+
+	t["uuid"] = UUIDFromTodoID(td.ID)
+	t["description"] = td.Title.String()
+	t["entry"] = formatTime(td.CreatedAt)
+	t["modified"] = formatTime(td.UpdatedAt)
+	t["priority"] = toTWPriority(td.Priority)
+	if len(td.Tags) > 0 {
+		t["tags"] = []string(td.Tags)
+	}
+	if td.DueDate != nil {
+		t["due"] = formatTime(td.DueDate.In(c.loc()))
+	}
+	if td.Scheduled != nil {
+		t["wait"] = formatTime(td.Scheduled.In(c.loc()))
+	}
+
+	switch {
+	case td.DeletedAt != nil:
+		t["status"] = "deleted"
+		t["end"] = formatTime(*td.DeletedAt)
+	case td.Status == todo.StatusDone || td.Status == todo.StatusArchived:
+		t["status"] = "completed"
+		if td.CompletedAt != nil {
+			t["end"] = formatTime(*td.CompletedAt)
+		}
+	default:
+		t["status"] = "pending"
+	}
+	return t
+}
+
+// date reads a Taskwarrior time as a day when it is midnight, and as a
+// time on that day otherwise.
+func (c Codec) date(at *time.Time) (*todo.DueDate, error) {
+	if at == nil {
+		return nil, nil
+	}
+	local, layout := at.In(c.loc()), "2006-01-02"
+	if local.Hour() != 0 || local.Minute() != 0 {
+		layout = "2006-01-02T15:04"
+	}
+	d, err := todo.ParseDueDate(local.Format(layout))
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// TodoIDFromUUID unwraps native IDs and keeps Taskwarrior UUIDs, lowercased.
+func TodoIDFromUUID(u string) todo.TodoID {
+	u = strings.ToLower(u)
+	if rest, ok := strings.CutPrefix(u, nativePrefix); ok {
+		if id := strings.ReplaceAll(rest, "-", ""); nativeIDRe.MatchString(id) {
+			return todo.TodoID(id)
+		}
+	}
+	return todo.TodoID(u)
+}
+
+// UUIDFromTodoID is the inverse of TodoIDFromUUID. IDs that are neither a
+// UUID nor a native ID get a stable name-based UUID (not reversible).
+func UUIDFromTodoID(id todo.TodoID) string {
+	s := id.String()
+	if nativeIDRe.MatchString(s) {
+		return nativePrefix + s[:4] + "-" + s[4:]
+	}
+	if uuidRe.MatchString(s) {
+		return strings.ToLower(s)
+	}
+
+	// RFC 4122 version 5 layout over a SHA-1 of the ID
+	sum := sha1.Sum([]byte("gotodo:" + s))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(sum[:16])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func (t task) get(key string, dst any) error {
+	raw, ok := t[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+func (t task) time(key string) (*time.Time, error) {
+	var s string
+	if err := t.get(key, &s); err != nil || s == "" {
+		return nil, err
+	}
+	v, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return &v, nil
+}
+
+func formatTime(t time.Time) string { return t.UTC().Format(timeLayout) }
+
+func fromTWPriority(p string) todo.Priority {
+	switch strings.ToUpper(p) {
+	case "H":
+		return todo.PriorityHigh
+	case "M":
+		return todo.PriorityMedium
+	default:
+		return todo.PriorityLow
+	}
+}
+
+func toTWPriority(p todo.Priority) string {
+	switch p {
+	case todo.PriorityHigh:
+		return "H"
+	case todo.PriorityMedium:
+		return "M"
+	default:
+		return "L"
+	}
+}
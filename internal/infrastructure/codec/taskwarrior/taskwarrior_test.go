@@ -0,0 +1,156 @@
+package taskwarrior
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type fixedIDs struct{}
+
+func (fixedIDs) NewTodoID() todo.TodoID { return "generated" }
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func TestDecode_ArrayAndStatusMapping(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	in := `[
+  {"id":1,"uuid":"5A1B2C3D-0000-4000-8000-000000000001","description":"Pay rent","status":"pending",
+   "priority":"H","tags":["home","bills"],"due":"20261031T230000Z","entry":"20261001T080000Z",
+   "modified":"20261002T080000Z","project":"house","urgency":9.1},
+  {"uuid":"5a1b2c3d-0000-4000-8000-000000000002","description":"Done thing","status":"completed",
+   "entry":"20261001T080000Z","end":"20261003T100000Z"},
+  {"uuid":"5a1b2c3d-0000-4000-8000-000000000003","description":"Gone","status":"deleted",
+   "priority":"M","entry":"20261001T080000Z","end":"20261004T100000Z"}
+]`
+	tds, err := Codec{Location: berlin}.Decode(strings.NewReader(in), fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if len(tds) != 3 {
+		t.Fatalf("len=%d want=3", len(tds))
+	}
+
+	rent, done, gone := tds[0], tds[1], tds[2]
+	if rent.ID != "5a1b2c3d-0000-4000-8000-000000000001" {
+		t.Fatalf("id=%q", rent.ID)
+	}
+	if rent.Priority != todo.PriorityHigh || !rent.Tags.Contains("bills") || rent.Status != todo.StatusActive {
+		t.Fatalf("rent=%+v", rent)
+	}
+	// 23:00Z on the 31st is midnight on Nov 1 in Berlin
+	if rent.DueDate == nil || rent.DueDate.String() != "2026-11-01" {
+		t.Fatalf("due=%v want=2026-11-01", rent.DueDate)
+	}
+	if rent.Meta["taskwarrior.project"] != `"house"` {
+		t.Fatalf("meta=%v", rent.Meta)
+	}
+	if _, ok := rent.Meta["taskwarrior.urgency"]; ok {
+		t.Fatalf("derived attribute kept: %v", rent.Meta)
+	}
+	if done.Status != todo.StatusDone || done.CompletedAt == nil || !done.CompletedAt.Equal(time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("done=%+v", done)
+	}
+	if gone.DeletedAt == nil || gone.Priority != todo.PriorityMedium {
+		t.Fatalf("gone=%+v", gone)
+	}
+}
+
+func TestDecode_LineFormatAndErrors(t *testing.T) {
+	in := `{"description":"First","status":"pending"},
+{"description":"Second","status":"waiting"}
+`
+	tds, err := Decode(strings.NewReader(in), fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if len(tds) != 2 || tds[0].ID != "generated" || tds[1].Status != todo.StatusActive {
+		t.Fatalf("tds=%+v", tds)
+	}
+
+	for _, bad := range []string{
+		`[{"description":"x","status":"exploded"}]`,
+		`[{"description":"","status":"pending"}]`,
+		`[{"description":"x","entry":"yesterday"}]`,
+	} {
+		if _, err := Decode(strings.NewReader(bad), fixedIDs{}, now); err == nil {
+			t.Fatalf("Decode(%s) err=nil want error", bad)
+		}
+	}
+}
+
+func TestRoundTrip_KeepsIDsAndMeta(t *testing.T) {
+	title, _ := todo.NewTitle("Write report")
+	due, _ := todo.ParseDueDate("2026-10-25")
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID: "4f2a9c1e0b7d3a55", Title: title, Priority: todo.PriorityMedium,
+		Tags: todo.NewTags([]string{"work"}), DueDate: &due, Now: now,
+	})
+	if err != nil {
+		t.Fatalf("NewTodo err=%v", err)
+	}
+	td.Meta = map[string]string{
+		"taskwarrior.project":     `"acme"`,
+		"taskwarrior.annotations": `[{"entry":"20261019T090000Z","description":"see mail"}]`,
+		"todotxt.rec":             "1w", // other codecs' metadata is not exported
+	}
+
+	c := Codec{Location: time.UTC}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, []todo.Todo{td}); err != nil {
+		t.Fatalf("Encode err=%v", err)
+	}
+
+	var raw []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got := raw[0]["uuid"]; got != "676f746f-646f-4000-4f2a-9c1e0b7d3a55" {
+		t.Fatalf("uuid=%v", got)
+	}
+	if raw[0]["project"] != "acme" || raw[0]["due"] != "20261025T000000Z" || raw[0]["priority"] != "M" {
+		t.Fatalf("task=%v", raw[0])
+	}
+	if _, ok := raw[0]["rec"]; ok {
+		t.Fatalf("foreign meta exported: %v", raw[0])
+	}
+
+	back, err := c.Decode(&buf, fixedIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	got := back[0]
+	if got.ID != td.ID || got.Title != td.Title || got.DueDate.String() != "2026-10-25" {
+		t.Fatalf("back=%+v", got)
+	}
+	if got.Meta["taskwarrior.annotations"] == "" || got.Meta["taskwarrior.project"] != `"acme"` {
+		t.Fatalf("meta=%v", got.Meta)
+	}
+}
+
+func TestUUIDFromTodoID(t *testing.T) {
+	tests := []struct {
+		id   todo.TodoID
+		want string
+	}{
+		{"0123456789abcdef", "676f746f-646f-4000-0123-456789abcdef"},
+		{"5A1B2C3D-0000-4000-8000-000000000001", "5a1b2c3d-0000-4000-8000-000000000001"},
+	}
+	for _, tt := range tests {
+		if got := UUIDFromTodoID(tt.id); got != tt.want {
+			t.Fatalf("UUIDFromTodoID(%q)=%q want=%q", tt.id, got, tt.want)
+		}
+	}
+
+	u := UUIDFromTodoID("n1")
+	if u != UUIDFromTodoID("n1") || !uuidRe.MatchString(u) || u[14] != '5' {
+		t.Fatalf("name-based uuid=%q", u)
+	}
+}
@@ -0,0 +1,298 @@
+// Package todotxt maps todo.txt lines (http://todotxt.org) to and from todos.
+//
+//	x 2026-10-01 2026-09-20 Call mom +family @phone due:2026-10-05 pri:A
+//	(B) 2026-09-21 Write report +work due:2026-10-03 est:2h
+//	Renew passport t:2026-11-01
+//
+// The t: (threshold) extension is the scheduled date and id: the TodoID,
+// so re-importing an exported file updates todos instead of duplicating them.
+// Priorities map A→high, B→medium and anything else (or none) → low; a
+// C to Z letter is kept in Todo.Meta under "todotxt.pri" and written back
+// while the todo stays low.
+// +project tags are stored without the plus; @context tags keep their "@"
+// so they can be written back as contexts. Unknown key:value extensions
+// are kept in Todo.Meta under "todotxt.<key>" and written back on export.
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	metaPrefix = "todotxt."
+)
+
+var (
+	priorityRe = regexp.MustCompile(`^\(([A-Z])\)$`)
+	// keys start with a letter, so times ("10:30") and verses ("3:2") stay
+	// in the title
+	extensionRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^\s/][^\s]*)$`)
+)
+
+// Decode parses every non-blank line of r into a todo. Lines without an id:
+// extension get an ID from ids; lines without a creation date are stamped
+// with now.
+func Decode(r io.Reader, ids ports.IDGenerator, now time.Time) ([]todo.Todo, error) {
+	var out []todo.Todo
+
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		td, err := ParseLine(line, ids.NewTodoID(), now)
+		if err != nil {
+			return nil, fmt.Errorf("todotxt: line %d: %w", n, err)
+		}
+		out = append(out, td)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParseLine parses a single todo.txt line. An id: extension wins over id.
+func ParseLine(line string, id todo.TodoID, now time.Time) (todo.Todo, error) {
+	tokens := strings.Fields(line)
+
+	var (
+		done      bool
+		completed *time.Time
+		created   *time.Time
+		priority  = todo.PriorityLow
+		letter    string // C–Z, which low cannot tell apart
+	)
+
+	if len(tokens) > 0 && tokens[0] == "x" {
+		done = true
+		tokens = tokens[1:]
+		if d, ok := parseDate(tokens); ok {
+			completed = &d
+			tokens = tokens[1:]
+		}
+	} else if len(tokens) > 0 {
+		if m := priorityRe.FindStringSubmatch(tokens[0]); m != nil {
+			priority, letter = fromLetter(m[1])
+			tokens = tokens[1:]
+		}
+	}
+	if d, ok := parseDate(tokens); ok {
+		created = &d
+		tokens = tokens[1:]
+	}
+
+	var (
+		words []string
+		tags  []string
+		due   *todo.DueDate
+		start *todo.DueDate
+		meta  map[string]string
+	)
+	for _, tok := range tokens {
+		switch {
+		case len(tok) > 1 && tok[0] == '+':
+			tags = append(tags, tok[1:])
+		case len(tok) > 1 && tok[0] == '@':
+			tags = append(tags, tok)
+		case extensionRe.MatchString(tok):
+			m := extensionRe.FindStringSubmatch(tok)
+			key, val := m[1], m[2]
+			switch key {
+			case "due", "t":
+				d, err := todo.ParseDueDate(val)
+				if err != nil {
+					return todo.Todo{}, err
+				}
+				if key == "due" {
+					due = &d
+				} else {
+					start = &d
+				}
+			case "pri":
+				priority, letter = fromLetter(val)
+			case "id":
+				id = todo.TodoID(val)
+			default:
+				if meta == nil {
+					meta = map[string]string{}
+				}
+				meta[metaPrefix+key] = val
+			}
+		default:
+			words = append(words, tok)
+		}
+	}
+
+	title, err := todo.NewTitle(strings.Join(words, " "))
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	createdAt := now
+	if created != nil {
+		createdAt = *created
+	}
+
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       id,
+		Title:    title,
+		Priority: priority,
+		Tags:     todo.NewTags(tags),
+		DueDate:  due,
+		Now:      createdAt,
+
+		Scheduled: start,
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	if letter != "" {
+		if meta == nil {
+			meta = map[string]string{}
+		}
+		meta[metaPrefix+"pri"] = letter
+	}
+	td.Meta = meta
+
+	if done {
+		at := createdAt
+		if completed != nil {
+			at = *completed
+		}
+		td, _, err = td.Complete(at)
+		if err != nil {
+			return todo.Todo{}, err
+		}
+	}
+
+	return td, nil
+}
+
+// Encode writes one todo.txt line per todo. Deleted todos are skipped and
+// archived todos are written as completed.
+func Encode(w io.Writer, tds []todo.Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, td := range tds {
+		if td.DeletedAt != nil {
+			continue
+		}
+		if _, err := bw.WriteString(FormatLine(td) + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// FormatLine renders td as a todo.txt line.
+func FormatLine(td todo.Todo) string {
+	var parts []string
+
+	done := td.Status == todo.StatusDone || td.Status == todo.StatusArchived
+	if done {
+		at := td.UpdatedAt
+		if td.CompletedAt != nil {
+			at = *td.CompletedAt
+		}
+		parts = append(parts, "x", at.UTC().Format(dateLayout))
+	} else if l := toLetter(td); l != "" {
+		parts = append(parts, "("+l+")")
+	}
+	if !td.CreatedAt.IsZero() {
+		parts = append(parts, td.CreatedAt.UTC().Format(dateLayout))
+	}
+
+	parts = append(parts, td.Title.String())
+
+	// projects first, then contexts, as todo.txt clients usually write them
+	for _, tag := range td.Tags {
+		if !strings.HasPrefix(tag, "@") {
+			parts = append(parts, "+"+strings.ReplaceAll(tag, " ", "_"))
+		}
+	}
+	for _, tag := range td.Tags {
+		if strings.HasPrefix(tag, "@") {
+			parts = append(parts, tag)
+		}
+	}
+	if td.DueDate != nil {
+		parts = append(parts, "due:"+td.DueDate.String())
+	}
+	if td.Scheduled != nil {
+		parts = append(parts, "t:"+td.Scheduled.String())
+	}
+	if done {
+		if l := toLetter(td); l != "" {
+			parts = append(parts, "pri:"+l)
+		}
+	}
+	if td.ID != "" {
+		parts = append(parts, "id:"+td.ID.String())
+	}
+
+	keys := make([]string, 0, len(td.Meta))
+	for k := range td.Meta {
+		if strings.HasPrefix(k, metaPrefix) && k != metaPrefix+"id" && k != metaPrefix+"pri" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, strings.TrimPrefix(k, metaPrefix)+":"+td.Meta[k])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func parseDate(tokens []string) (time.Time, bool) {
+	if len(tokens) == 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dateLayout, tokens[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// fromLetter maps a priority letter to a priority, returning the letter
+// too when it is one of C to Z.
+func fromLetter(l string) (todo.Priority, string) {
+	l = strings.ToUpper(l)
+	switch {
+	case l == "A":
+		return todo.PriorityHigh, ""
+	case l == "B":
+		return todo.PriorityMedium, ""
+	case len(l) == 1 && l >= "C" && l <= "Z":
+		return todo.PriorityLow, l
+	default:
+		return todo.PriorityLow, ""
+	}
+}
+
+// toLetter is td's priority letter: A or B, or the C–Z letter it was
+// imported with as long as it is still low.
+func toLetter(td todo.Todo) string {
+	switch td.Priority {
+	case todo.PriorityHigh:
+		return "A"
+	case todo.PriorityMedium:
+		return "B"
+	default:
+		return td.Meta[metaPrefix+"pri"]
+	}
+}
@@ -0,0 +1,135 @@
+package todotxt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("t%d", s.n))
+}
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func TestParseLine(t *testing.T) {
+	td, err := ParseLine("(A) 2026-09-20 Call mom +Family @phone due:2026-10-05 color:blue http://example.com", "t1", now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if td.Title != "Call mom http://example.com" {
+		t.Fatalf("title=%q", td.Title)
+	}
+	if td.Priority != todo.PriorityHigh {
+		t.Fatalf("priority=%s", td.Priority)
+	}
+	if !td.Tags.Contains("family") || !td.Tags.Contains("@phone") {
+		t.Fatalf("tags=%v", td.Tags)
+	}
+	if td.DueDate == nil || td.DueDate.String() != "2026-10-05" {
+		t.Fatalf("due=%v", td.DueDate)
+	}
+	if td.Meta["todotxt.color"] != "blue" {
+		t.Fatalf("meta=%v", td.Meta)
+	}
+	if got := td.CreatedAt.Format(dateLayout); got != "2026-09-20" {
+		t.Fatalf("created=%s", got)
+	}
+}
+
+func TestParseLine_TimesStayInTitle(t *testing.T) {
+	line := "Call dentist at 10:30 about chapter 3:2 due:2026-10-05"
+	td, err := ParseLine(line, "t1", now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if td.Title != "Call dentist at 10:30 about chapter 3:2" || td.Meta != nil {
+		t.Fatalf("title=%q meta=%v", td.Title, td.Meta)
+	}
+	if got := FormatLine(td); !strings.Contains(got, " at 10:30 about chapter 3:2 due:2026-10-05") {
+		t.Fatalf("FormatLine=%q", got)
+	}
+}
+
+func TestParseLine_Completed(t *testing.T) {
+	td, err := ParseLine("x 2026-10-01 2026-09-20 Pay rent pri:B", "t1", now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if td.Status != todo.StatusDone || td.CompletedAt == nil {
+		t.Fatalf("status=%s completedAt=%v", td.Status, td.CompletedAt)
+	}
+	if got := td.CompletedAt.Format(dateLayout); got != "2026-10-01" {
+		t.Fatalf("completed=%s", got)
+	}
+	if td.Priority != todo.PriorityMedium {
+		t.Fatalf("priority=%s", td.Priority)
+	}
+}
+
+func TestParseLine_Threshold(t *testing.T) {
+	td, err := ParseLine("Renew passport t:2026-11-01", "t1", now)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if td.Scheduled == nil || td.Scheduled.String() != "2026-11-01" {
+		t.Fatalf("scheduled=%v", td.Scheduled)
+	}
+	if _, ok := td.Meta["todotxt.t"]; ok {
+		t.Fatalf("t: kept in meta: %v", td.Meta)
+	}
+	if line := FormatLine(td); !strings.Contains(line, " t:2026-11-01") {
+		t.Fatalf("line=%q", line)
+	}
+}
+
+func TestDecode_ReportsLineNumber(t *testing.T) {
+	_, err := Decode(strings.NewReader("ok task\n\n+onlytag\n"), &seqIDs{}, now)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("err=%v want line 3", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := strings.Join([]string{
+		"(A) 2026-09-20 Call mom +family @phone due:2026-10-05 id:4f2a9c1e color:blue",
+		"x 2026-10-01 2026-09-21 Write report +work pri:B id:77b01d3a",
+		"2026-09-22 Water plants id:t1",
+		"(D) 2026-09-23 Sort photos id:t2",
+		"x 2026-10-02 2026-09-24 Clean garage pri:C id:t3",
+	}, "\n") + "\n"
+
+	tds, err := Decode(strings.NewReader(in), &seqIDs{}, now)
+	if err != nil {
+		t.Fatalf("Decode err=%v", err)
+	}
+	if tds[0].ID != "4f2a9c1e" || tds[1].ID != "77b01d3a" {
+		t.Fatalf("ids=%s,%s", tds[0].ID, tds[1].ID)
+	}
+	if _, ok := tds[0].Meta["todotxt.id"]; ok {
+		t.Fatalf("id: kept in meta: %v", tds[0].Meta)
+	}
+	if tds[3].Priority != todo.PriorityLow || tds[3].Meta["todotxt.pri"] != "D" {
+		t.Fatalf("priority=%s meta=%v", tds[3].Priority, tds[3].Meta)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, tds); err != nil {
+		t.Fatalf("Encode err=%v", err)
+	}
+	if buf.String() != in {
+		t.Fatalf("round trip mismatch\n got: %q\nwant: %q", buf.String(), in)
+	}
+
+	// a todo raised after import drops the letter it came with
+	tds[3].Priority = todo.PriorityHigh
+	if got := FormatLine(tds[3]); !strings.HasPrefix(got, "(A) ") {
+		t.Fatalf("FormatLine=%q", got)
+	}
+}
@@ -0,0 +1,207 @@
+package codescan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("n%d", s.n))
+}
+
+var now = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+func TestScanFile_GoComments(t *testing.T) {
+	src := strings.Join([]string{
+		"package x",
+		"",
+		"// TODO: handle errors",
+		`var u = "http://example.com" // FIXME(ana) wrong host`,
+		"/* HACK until v2 */ var y = 1",
+		"/*",
+		" * TODO: inside a block",
+		" */",
+		"// Not a TODO: marker must come first",
+		"// TODOS are not markers",
+		"// TODO: handle errors",
+		`var s = "// TODO inside a string"`,
+	}, "\n")
+
+	got, err := ScanFile(strings.NewReader(src), "x/x.go", DefaultSyntaxes()[".go"])
+	if err != nil {
+		t.Fatalf("ScanFile err=%v", err)
+	}
+
+	want := []struct {
+		line       int
+		kind, text string
+	}{
+		{3, "TODO", "handle errors"},
+		{4, "FIXME", "wrong host"},
+		{5, "HACK", "until v2"},
+		{7, "TODO", "inside a block"},
+		{11, "TODO", "handle errors"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings want=%d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Line != w.line || got[i].Kind != w.kind || got[i].Text != w.text {
+			t.Fatalf("finding %d=%+v want=%+v", i, got[i], w)
+		}
+	}
+	if got[1].Author != "ana" {
+		t.Fatalf("author=%q want=ana", got[1].Author)
+	}
+	if got[0].Fingerprint == got[4].Fingerprint {
+		t.Fatalf("identical comments share a fingerprint")
+	}
+}
+
+func TestScanner_TargetsAndSyntaxes(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, body string) {
+		t.Helper()
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "// TODO top\n")
+	write("pkg/a.go", "// TODO nested\n")
+	write("pkg/run.py", "# FIXME python\n")
+	write("vendor/v.go", "// TODO vendored\n")
+	write(".hidden/h.go", "// TODO hidden\n")
+
+	syn := DefaultSyntaxes()
+	if err := ParseSyntax(".py,.sh=#", syn); err != nil {
+		t.Fatalf("ParseSyntax err=%v", err)
+	}
+
+	s := Scanner{Root: root, Syntaxes: syn}
+	all, err := s.Scan([]Target{{Dir: ".", Recursive: true}})
+	if err != nil {
+		t.Fatalf("Scan err=%v", err)
+	}
+	var files []string
+	for _, f := range all {
+		files = append(files, f.File)
+	}
+	if strings.Join(files, " ") != "main.go pkg/a.go pkg/run.py" {
+		t.Fatalf("files=%v", files)
+	}
+
+	top, err := s.Scan([]Target{{Dir: "."}})
+	if err != nil {
+		t.Fatalf("Scan err=%v", err)
+	}
+	if len(top) != 1 || top[0].File != "main.go" {
+		t.Fatalf("non-recursive=%+v", top)
+	}
+}
+
+func TestSync_Lifecycle(t *testing.T) {
+	const root = "/repo"
+	targets := []Target{{Dir: ".", Recursive: true}}
+	ids := &seqIDs{}
+	opts := SyncOptions{Root: root, Targets: targets, Now: now, IDs: ids}
+
+	scan := func(src string) []Finding {
+		t.Helper()
+		fs, err := ScanFile(strings.NewReader(src), "a.go", DefaultSyntaxes()[".go"])
+		if err != nil {
+			t.Fatalf("ScanFile err=%v", err)
+		}
+		return fs
+	}
+	apply := func(store []todo.Todo, plan SyncPlan) []todo.Todo {
+		t.Helper()
+		byID := map[todo.TodoID]int{}
+		for i, td := range store {
+			byID[td.ID] = i
+		}
+		for _, td := range plan.Update {
+			store[byID[td.ID]] = td
+		}
+		for _, id := range plan.Complete {
+			store[byID[id]], _, _ = store[byID[id]].Complete(now)
+		}
+		for _, id := range plan.Reopen {
+			store[byID[id]], _, _ = store[byID[id]].Reopen(now)
+		}
+		return append(store, plan.Create...)
+	}
+
+	// first scan creates
+	plan, err := Sync(scan("// TODO one\n// FIXME two\n"), nil, opts)
+	if err != nil {
+		t.Fatalf("Sync err=%v", err)
+	}
+	if len(plan.Create) != 2 {
+		t.Fatalf("create=%+v", plan.Create)
+	}
+	fixme := plan.Create[1]
+	if fixme.Title != "two" || fixme.Priority != todo.PriorityMedium || !fixme.Tags.Contains("code") ||
+		!fixme.Tags.Contains("a.go") || fixme.Meta[MetaLine] != "2" {
+		t.Fatalf("fixme=%+v", fixme)
+	}
+	store := apply(nil, plan)
+
+	// rescan after moving lines: no duplicates, location updated
+	plan, _ = Sync(scan("\n\n// FIXME two\n// TODO one\n"), store, opts)
+	if len(plan.Create) != 0 || len(plan.Complete) != 0 || len(plan.Update) != 2 {
+		t.Fatalf("move plan=%+v", plan)
+	}
+	store = apply(store, plan)
+
+	// removing a comment completes its todo
+	plan, _ = Sync(scan("// TODO one\n"), store, opts)
+	if len(plan.Complete) != 1 || plan.Complete[0] != fixme.ID {
+		t.Fatalf("remove plan=%+v", plan)
+	}
+	store = apply(store, plan)
+
+	// a comment outside the scanned targets is not touched
+	plan, _ = Sync(nil, store, SyncOptions{Root: root, Targets: []Target{{Dir: "other"}}, Now: now, IDs: ids})
+	if len(plan.Complete) != 0 {
+		t.Fatalf("out of scope completed: %+v", plan)
+	}
+
+	// bringing it back reopens the same todo
+	plan, _ = Sync(scan("// TODO one\n// FIXME two\n"), store, opts)
+	if len(plan.Create) != 0 || len(plan.Reopen) != 1 || plan.Reopen[0] != fixme.ID {
+		t.Fatalf("restore plan=%+v", plan)
+	}
+}
+
+func TestTargetContains(t *testing.T) {
+	tests := []struct {
+		t    Target
+		file string
+		want bool
+	}{
+		{Target{Dir: ".", Recursive: true}, "a/b/c.go", true},
+		{Target{Dir: "."}, "c.go", true},
+		{Target{Dir: "."}, "a/c.go", false},
+		{Target{Dir: "a", Recursive: true}, "a/b/c.go", true},
+		{Target{Dir: "a"}, "a/b/c.go", false},
+		{Target{Dir: "a", Recursive: true}, "ab/c.go", false},
+	}
+	for _, tt := range tests {
+		if got := tt.t.Contains(tt.file); got != tt.want {
+			t.Fatalf("%+v.Contains(%q)=%v want=%v", tt.t, tt.file, got, tt.want)
+		}
+	}
+}
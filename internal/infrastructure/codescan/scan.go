@@ -0,0 +1,335 @@
+// Package codescan finds TODO, FIXME and HACK comments in a source tree so
+// they can be tracked as todos.
+//
+// Each comment gets a fingerprint from its file, marker and text (not its
+// line number), so moving code around keeps the same todo while editing
+// the comment text retires the old one and creates a new one.
+package codescan
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxFileSize skips generated blobs and the like.
+const maxFileSize = 1 << 20
+
+var markerRe = regexp.MustCompile(`^(TODO|FIXME|HACK)(?:\(([^)]*)\))?(?::|\s|$)\s*(.*)$`)
+
+// Syntax describes how comments are written in one language.
+type Syntax struct {
+	Line  []string    // e.g. "//", "#"
+	Block [][2]string // e.g. {"/*", "*/"}
+}
+
+// DefaultSyntaxes covers Go. Add others with ParseSyntax.
+func DefaultSyntaxes() map[string]Syntax {
+	return map[string]Syntax{
+		".go": {Line: []string{"//"}, Block: [][2]string{{"/*", "*/"}}},
+	}
+}
+
+// ParseSyntax adds a comment syntax to into. The spec lists extensions and
+// either a line prefix or a block delimiter pair separated by "...":
+//
+//	.py,.sh=#
+//	.css=/*...*/
+//
+// Repeating an extension adds to its syntax.
+func ParseSyntax(spec string, into map[string]Syntax) error {
+	exts, delim, ok := strings.Cut(spec, "=")
+	delim = strings.TrimSpace(delim)
+	if !ok || strings.TrimSpace(exts) == "" || delim == "" {
+		return fmt.Errorf("codescan: bad comment syntax %q (want .ext[,.ext]=PREFIX or START...END)", spec)
+	}
+	for _, ext := range strings.Split(exts, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		syn := into[ext]
+		if start, end, ok := strings.Cut(delim, "..."); ok && start != "" && end != "" {
+			syn.Block = append(syn.Block, [2]string{start, end})
+		} else {
+			syn.Line = append(syn.Line, delim)
+		}
+		into[ext] = syn
+	}
+	return nil
+}
+
+// Finding is one marker comment.
+type Finding struct {
+	File        string // slash-separated, relative to the scan root
+	Line        int
+	Kind        string // TODO, FIXME or HACK
+	Author      string // from TODO(name)
+	Text        string
+	Fingerprint string
+}
+
+// Target is a directory to scan, relative to the root. "dir/..." scans
+// recursively, "dir" only the files directly in it (as with go tooling).
+type Target struct {
+	Dir       string // slash-separated, "." for the root
+	Recursive bool
+}
+
+// Contains reports whether file (relative to the root) is covered.
+func (t Target) Contains(file string) bool {
+	dir := pathDir(file)
+	if t.Dir == "." {
+		return t.Recursive || dir == "."
+	}
+	if dir == t.Dir {
+		return true
+	}
+	return t.Recursive && strings.HasPrefix(dir, t.Dir+"/")
+}
+
+// ParseTargets resolves command-line patterns against root. Patterns are
+// relative to the working directory; no patterns means "./...".
+func ParseTargets(root string, patterns []string) ([]Target, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	var out []Target
+	for _, p := range patterns {
+		var t Target
+		if rest, ok := strings.CutSuffix(p, "..."); ok {
+			p, t.Recursive = rest, true
+			if p == "" {
+				p = "."
+			}
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("codescan: %s is outside %s", p, root)
+		}
+		t.Dir = filepath.ToSlash(rel)
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// FindRoot returns the nearest directory at or above dir containing .git,
+// or dir itself when there is none.
+func FindRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d, nil
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return abs, nil
+		}
+		d = parent
+	}
+}
+
+type Scanner struct {
+	Root     string            // absolute
+	Syntaxes map[string]Syntax // by lower-case extension; nil means DefaultSyntaxes
+}
+
+// Scan walks the targets and returns findings in file order. Hidden
+// directories, vendor, node_modules and testdata are skipped.
+func (s Scanner) Scan(targets []Target) ([]Finding, error) {
+	syntaxes := s.Syntaxes
+	if syntaxes == nil {
+		syntaxes = DefaultSyntaxes()
+	}
+
+	var out []Finding
+	seen := map[string]bool{}
+	for _, t := range targets {
+		start := filepath.Join(s.Root, filepath.FromSlash(t.Dir))
+		err := filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path == start {
+					return nil
+				}
+				if !t.Recursive || skipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			syn, ok := syntaxes[strings.ToLower(filepath.Ext(path))]
+			if !ok || !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(s.Root, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if seen[rel] {
+				return nil // overlapping targets
+			}
+			seen[rel] = true
+
+			if info, err := d.Info(); err != nil || info.Size() > maxFileSize {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			found, err := ScanFile(f, rel, syn)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			out = append(out, found...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ScanFile extracts the marker comments of one file. Comment openers
+// inside single-line string literals are ignored, and the marker must
+// start the comment text.
+func ScanFile(r io.Reader, file string, syn Syntax) ([]Finding, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxFileSize)
+
+	var (
+		out      []Finding
+		inBlock  = -1 // index into syn.Block while inside a block comment
+		dupCount = map[string]int{}
+	)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if strings.IndexByte(line, 0) >= 0 {
+			return nil, nil // binary
+		}
+
+		for _, text := range comments(line, syn, &inBlock) {
+			m := markerRe.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			f := Finding{File: file, Line: n, Kind: m[1], Author: strings.TrimSpace(m[2]), Text: strings.TrimSpace(m[3])}
+
+			key := f.Kind + "\x00" + strings.Join(strings.Fields(f.Text), " ")
+			f.Fingerprint = fingerprint(file, key, dupCount[key])
+			dupCount[key]++
+			out = append(out, f)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, nil // minified or generated
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// comments returns the comment texts on one line, updating the block state.
+func comments(line string, syn Syntax, inBlock *int) []string {
+	var out []string
+	for line != "" {
+		if *inBlock >= 0 {
+			end := syn.Block[*inBlock][1]
+			body, rest, closed := strings.Cut(line, end)
+			out = append(out, strings.TrimLeft(strings.TrimSpace(body), "* "))
+			if !closed {
+				return out
+			}
+			*inBlock, line = -1, rest
+			continue
+		}
+
+		// earliest comment opener on the line
+		at, lineLen, block := -1, 0, -1
+		for _, p := range syn.Line {
+			if i := indexCode(line, p); i >= 0 && (at < 0 || i < at) {
+				at, lineLen, block = i, len(p), -1
+			}
+		}
+		for bi, b := range syn.Block {
+			if i := indexCode(line, b[0]); i >= 0 && (at < 0 || i < at) {
+				at, lineLen, block = i, len(b[0]), bi
+			}
+		}
+		if at < 0 {
+			return out
+		}
+		if block < 0 {
+			return append(out, strings.TrimSpace(line[at+lineLen:]))
+		}
+		*inBlock, line = block, line[at+lineLen:]
+		if strings.TrimSpace(line) == "" {
+			return out
+		}
+	}
+	return out
+}
+
+// indexCode is strings.Index ignoring matches inside "..." and `...`
+// literals on the line. Strings spanning lines are not tracked.
+func indexCode(line, sub string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`':
+			quote = c
+		case strings.HasPrefix(line[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
+
+func fingerprint(file, key string, dup int) string {
+	sum := sha1.Sum(fmt.Appendf(nil, "%s\x00%s\x00%d", file, key, dup))
+	return hex.EncodeToString(sum[:8])
+}
+
+func skipDir(name string) bool {
+	switch name {
+	case "vendor", "node_modules", "testdata":
+		return true
+	}
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func pathDir(file string) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		return file[:i]
+	}
+	return "."
+}
@@ -0,0 +1,169 @@
+package codescan
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Tag is added to every todo created from a comment.
+const Tag = "code"
+
+const (
+	MetaFingerprint = "scan.fingerprint"
+	MetaRoot        = "scan.root" // absolute root the file path is relative to
+	MetaFile        = "scan.file"
+	MetaLine        = "scan.line"
+	MetaAuthor      = "scan.author"
+	MetaGone        = "scan.gone" // set when the comment vanished and the todo was auto-completed
+)
+
+// titleLimit matches the domain's maximum title length.
+const titleLimit = 200
+
+type SyncOptions struct {
+	Root    string   // absolute
+	Targets []Target // only todos for files under these can be auto-completed
+	Now     time.Time
+	IDs     ports.IDGenerator
+}
+
+// SyncPlan is what Sync wants done to the store. Update must be applied
+// before Complete and Reopen, which only change the status.
+type SyncPlan struct {
+	Create   []todo.Todo
+	Update   []todo.Todo
+	Complete []todo.TodoID
+	Reopen   []todo.TodoID
+}
+
+// Sync matches findings to todos by fingerprint. New comments become
+// todos, moved ones get their location updated, and todos whose comment
+// is gone are completed. A todo completed that way is reopened if its
+// comment comes back; one the user completed or deleted is left alone.
+func Sync(findings []Finding, store []todo.Todo, opts SyncOptions) (SyncPlan, error) {
+	var plan SyncPlan
+
+	byFP := map[string]todo.Todo{}
+	for _, td := range store {
+		if td.Meta[MetaRoot] == opts.Root && td.Meta[MetaFingerprint] != "" {
+			byFP[td.Meta[MetaFingerprint]] = td
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, f := range findings {
+		if seen[f.Fingerprint] {
+			continue
+		}
+		seen[f.Fingerprint] = true
+
+		td, ok := byFP[f.Fingerprint]
+		if !ok {
+			created, err := newFromFinding(f, opts)
+			if err != nil {
+				return SyncPlan{}, err
+			}
+			plan.Create = append(plan.Create, created)
+			continue
+		}
+		if td.DeletedAt != nil {
+			continue
+		}
+
+		meta := withLocation(td.Meta, f)
+		reopen := td.Status == todo.StatusDone && meta[MetaGone] != ""
+		delete(meta, MetaGone)
+		if !maps.Equal(meta, td.Meta) {
+			td.Meta = meta
+			plan.Update = append(plan.Update, td)
+		}
+		if reopen {
+			plan.Reopen = append(plan.Reopen, td.ID)
+		}
+	}
+
+	for _, td := range store {
+		if td.Meta[MetaRoot] != opts.Root || td.Meta[MetaFingerprint] == "" || seen[td.Meta[MetaFingerprint]] {
+			continue
+		}
+		if td.DeletedAt != nil || td.Status != todo.StatusActive || !covered(opts.Targets, td.Meta[MetaFile]) {
+			continue
+		}
+		td.Meta = maps.Clone(td.Meta)
+		td.Meta[MetaGone] = opts.Now.UTC().Format(time.RFC3339)
+		plan.Update = append(plan.Update, td)
+		plan.Complete = append(plan.Complete, td.ID)
+	}
+	return plan, nil
+}
+
+func newFromFinding(f Finding, opts SyncOptions) (todo.Todo, error) {
+	title, err := todo.NewTitle(titleFor(f))
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	priority := todo.PriorityLow
+	if f.Kind == "FIXME" {
+		priority = todo.PriorityMedium
+	}
+
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       opts.IDs.NewTodoID(),
+		Title:    title,
+		Priority: priority,
+		Tags:     todo.NewTags([]string{Tag, f.Kind, f.File}),
+		Now:      opts.Now,
+	})
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	td.Meta = withLocation(map[string]string{
+		MetaRoot:        opts.Root,
+		MetaFingerprint: f.Fingerprint,
+	}, f)
+	if f.Author != "" {
+		td.Meta[MetaAuthor] = f.Author
+	}
+	return td, nil
+}
+
+func titleFor(f Finding) string {
+	t := strings.Join(strings.Fields(f.Text), " ")
+	if t == "" {
+		t = f.Kind + " in " + f.File
+	}
+	if len(t) <= titleLimit {
+		return t
+	}
+	cut := titleLimit - len("…")
+	for cut > 0 && !utf8.RuneStart(t[cut]) {
+		cut--
+	}
+	return t[:cut] + "…"
+}
+
+func withLocation(meta map[string]string, f Finding) map[string]string {
+	out := maps.Clone(meta)
+	if out == nil {
+		out = map[string]string{}
+	}
+	out[MetaFile] = f.File
+	out[MetaLine] = strconv.Itoa(f.Line)
+	return out
+}
+
+func covered(targets []Target, file string) bool {
+	for _, t := range targets {
+		if t.Contains(file) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,362 @@
+// Package config loads gotodo's settings from a TOML file under
+// $XDG_CONFIG_HOME/gotodo, with named profiles and environment overrides.
+//
+//	[store]
+//	path = "~/Dropbox/todos.json"
+//
+//	[defaults]
+//	priority = "medium"
+//	tags = ["inbox"]
+//
+//	[profiles.work.store]
+//	path = "~/work/todos.json"
+//
+// Inside a workspace (a directory with a .gotodo directory, or below one)
+// the workspace's store replaces store.path.
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/workspace"
+)
+
+// Config is the effective configuration: defaults, then the file's base
+// settings, then the selected profile, then environment overrides.
+type Config struct {
+	File      string // config file the settings came from (may not exist)
+	Profile   string
+	Workspace string // root of the workspace in use, "" for the global store
+
+	Store    Store
+	Defaults Defaults
+	Dates    Dates
+	TUI      TUI
+	Plan     Plan
+	Hooks    Hooks
+}
+
+type Store struct {
+	Backend string // only "json" for now
+	Path    string // resolved path of the todos file
+	Global  string // the todos file outside any workspace
+}
+
+// Defaults apply to todos added without a priority or tags.
+type Defaults struct {
+	Priority string
+	Tags     []string
+}
+
+type Dates struct {
+	WeekStart time.Weekday
+	Format    string         // Go layout for dates shown to people
+	Timezone  string         // IANA name, or "local" for the system zone
+	Location  *time.Location // Timezone, loaded
+}
+
+// Hooks are the user's scripts run on events.
+type Hooks struct {
+	Timeout time.Duration // how long a hook may run before it is killed
+}
+
+// Plan is what `todo plan` assumes unless told otherwise.
+type Plan struct {
+	Capacity todo.Estimate // a day's work
+	DaysOff  []time.Weekday
+}
+
+type TUI struct {
+	Theme string              // auto, dark, light or plain
+	Keys  map[string][]string // action -> keys, only for actions that are set
+}
+
+// Options choose the file and profile; empty fields fall back to
+// $GOTODO_CONFIG and $GOTODO_PROFILE. Dir is where to look for a
+// workspace; "" uses the global store.
+type Options struct {
+	File    string
+	Profile string
+	Dir     string
+}
+
+// Default is the configuration used when nothing is set.
+func Default() Config {
+	return Config{
+		Store:    Store{Backend: "json"},
+		Defaults: Defaults{Priority: "low"},
+		Dates:    Dates{WeekStart: time.Monday, Format: "2006-01-02", Timezone: "local", Location: time.Local},
+		TUI:      TUI{Theme: "auto", Keys: map[string][]string{}},
+		Plan: Plan{
+			Capacity: todo.Estimate{Value: 6 * 60, Unit: todo.EstimateMinutes},
+			DaysOff:  []time.Weekday{time.Saturday, time.Sunday},
+		},
+		Hooks: Hooks{Timeout: 5 * time.Second},
+	}
+}
+
+// Load reads the configuration. A missing file is fine; a bad value for a
+// known key is an error. Unknown keys are ignored here (Validate reports
+// them) so a newer config file still works with an older binary.
+func Load(opts Options) (Config, error) {
+	c := Default()
+
+	file, err := FilePath(opts.File)
+	if err != nil {
+		return c, err
+	}
+	c.File = file
+	c.Profile = cmp.Or(opts.Profile, os.Getenv("GOTODO_PROFILE"))
+
+	doc, err := ReadDocument(file)
+	if err != nil {
+		return c, err
+	}
+
+	var base, profile []Entry
+	found := false
+	for _, e := range doc.Entries() {
+		name, key, ok := profileKey(e.Path())
+		switch {
+		case !ok:
+			base = append(base, e)
+		case name == c.Profile:
+			found = true
+			profile = append(profile, Entry{Key: key, Value: e.Value, Line: e.Line})
+		}
+	}
+	if c.Profile != "" && !found {
+		return c, fmt.Errorf("config: %s: unknown profile %q", file, c.Profile)
+	}
+
+	for _, e := range append(base, profile...) {
+		s, ok := lookup(e.Path())
+		if !ok {
+			continue
+		}
+		if err := s.apply(&c, e.Value); err != nil {
+			return c, fmt.Errorf("config: %s:%d: %s: %w", file, e.Line, e.Path(), err)
+		}
+	}
+
+	if c.Store.Path, err = c.resolvePath(c.Store.Path); err != nil {
+		return c, err
+	}
+	c.Store.Global = c.Store.Path
+
+	if p := os.Getenv("GOTODO_DB"); p != "" {
+		// relative to the working directory, like --file
+		if c.Store.Path, err = filepath.Abs(p); err != nil {
+			return c, err
+		}
+	} else if opts.Dir != "" {
+		if root, ok := workspace.Find(opts.Dir); ok {
+			c.Workspace = root
+			c.Store.Path = workspace.StorePath(root)
+		}
+	}
+	return c, nil
+}
+
+// DBPath is the todos file to open: file (a --file flag) when set,
+// otherwise the configured one.
+func (c Config) DBPath(file string) string {
+	if file != "" {
+		return file
+	}
+	return c.Store.Path
+}
+
+// resolvePath expands "~/" and makes p relative to the config file's
+// directory; an empty p is the default data path.
+func (c Config) resolvePath(p string) (string, error) {
+	switch {
+	case p == "":
+		return DefaultDataPath()
+	case p == "~" || strings.HasPrefix(p, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, p[1:]), nil
+	case !filepath.IsAbs(p):
+		return filepath.Join(filepath.Dir(c.File), p), nil
+	}
+	return p, nil
+}
+
+// ReadDocument parses the file at path; a missing file is an empty document.
+func ReadDocument(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Document{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ParseDocument(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Validate checks every entry of doc, reporting syntax-valid but unknown
+// keys as well as bad values.
+func Validate(doc *Document) []error {
+	var errs []error
+	for _, e := range doc.Entries() {
+		key := e.Path()
+		if _, k, ok := profileKey(key); ok {
+			key = k
+		}
+		s, ok := lookup(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("line %d: %s: unknown key", e.Line, e.Path()))
+			continue
+		}
+		scratch := Default()
+		if err := s.apply(&scratch, e.Value); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s: %w", e.Line, e.Path(), err))
+		}
+	}
+	return errs
+}
+
+// Set writes key = value to the file at path, in profile's tables when
+// profile is set. value is parsed as the key expects: list keys split on
+// commas. The file and its directory are created if needed.
+func Set(path, profile, key, value string) error {
+	s, ok := lookup(key)
+	if !ok {
+		return fmt.Errorf("config: unknown key %q", key)
+	}
+	var v any = value
+	if s.list {
+		v = splitList(value)
+	}
+	scratch := Default()
+	if err := s.apply(&scratch, v); err != nil {
+		return fmt.Errorf("config: %s: %w", key, err)
+	}
+
+	doc, err := ReadDocument(path)
+	if err != nil {
+		return err
+	}
+	table, name := "", key
+	if dot := strings.LastIndex(key, "."); dot >= 0 {
+		table, name = key[:dot], key[dot+1:]
+	}
+	if profile != "" {
+		table = joinPath("profiles."+profile, table)
+	}
+	doc.Set(table, name, v)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(doc.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get returns the effective value of key: a string or a []string.
+func (c Config) Get(key string) (any, error) {
+	s, ok := lookup(key)
+	if !ok {
+		return nil, fmt.Errorf("config: unknown key %q", key)
+	}
+	return s.get(c), nil
+}
+
+// Format renders a value in TOML syntax.
+func Format(v any) string { return formatValue(v) }
+
+// Keys lists every known key in a stable order.
+func Keys() []string {
+	out := make([]string, len(settings))
+	for i, s := range settings {
+		out[i] = s.name
+	}
+	return out
+}
+
+// DefaultFile is $XDG_CONFIG_HOME/gotodo/config.toml, with
+// ~/.config as the fallback for $XDG_CONFIG_HOME.
+func DefaultFile() (string, error) {
+	dir, err := xdgDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gotodo", "config.toml"), nil
+}
+
+// DefaultDataPath is where todos live when no path is configured:
+// ~/.gotodo/todos.json if it already exists (stores made before config
+// files), otherwise $XDG_DATA_HOME/gotodo/todos.json.
+func DefaultDataPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	legacy := filepath.Join(home, ".gotodo", "todos.json")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+	dir, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gotodo", "todos.json"), nil
+}
+
+// FilePath is the config file to use: file (a --config flag) when set,
+// then $GOTODO_CONFIG, then DefaultFile.
+func FilePath(file string) (string, error) {
+	if file = cmp.Or(file, os.Getenv("GOTODO_CONFIG")); file != "" {
+		return filepath.Abs(file)
+	}
+	return DefaultFile()
+}
+
+func xdgDir(env, fallback string) (string, error) {
+	// the spec says relative values are invalid and must be ignored
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback), nil
+}
+
+// profileKey splits "profiles.work.store.path" into "work" and "store.path".
+func profileKey(path string) (name, key string, ok bool) {
+	rest, ok := strings.CutPrefix(path, "profiles.")
+	if !ok {
+		return "", "", false
+	}
+	name, key, ok = strings.Cut(rest, ".")
+	return name, key, ok
+}
+
+func splitList(s string) []string {
+	out := []string{}
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
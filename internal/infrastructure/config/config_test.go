@@ -0,0 +1,340 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// isolate points HOME and the XDG dirs at a temp dir and clears overrides.
+func isolate(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, k := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "GOTODO_CONFIG", "GOTODO_PROFILE", "GOTODO_DB"} {
+		t.Setenv(k, "")
+	}
+	return home
+}
+
+func TestParseDocument(t *testing.T) {
+	doc, err := ParseDocument(`# gotodo
+top = 'literal \n'
+
+[tui]
+theme = "dark" # trailing comment
+keys.quit = ["q", "ctrl+c"]
+
+[defaults]
+tags = [ "a#b", 'c' ]
+n = 1_000
+on = true
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range doc.Entries() {
+		got = append(got, e.Path()+"="+formatValue(e.Value))
+	}
+	want := []string{
+		`top="literal \\n"`,
+		`tui.theme="dark"`,
+		`tui.keys.quit=["q", "ctrl+c"]`,
+		`defaults.tags=["a#b", "c"]`,
+		`defaults.n=1000`,
+		`defaults.on=true`,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("entries=%q want=%q", got, want)
+	}
+}
+
+func TestParseDocument_Errors(t *testing.T) {
+	tests := []string{
+		"[tui\n",
+		"[[tui]]\n",
+		"theme\n",
+		"theme = \"dark\n",
+		"tags = [1, 2]\n",
+		"a = 1\na = 2\n",
+		"[t]\nk = 1\n[t]\nk = 2\n",
+		"x = 1.5\n",
+	}
+	for _, src := range tests {
+		if _, err := ParseDocument(src); err == nil || !strings.Contains(err.Error(), "line ") {
+			t.Fatalf("ParseDocument(%q) err=%v, want a line error", src, err)
+		}
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	doc, err := ParseDocument(`# settings
+store.path = "a.json"
+
+[tui] # look
+theme = "dark"
+keys.up = "k"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Set("store", "backend", "json")
+	doc.Set("tui", "theme", "light")
+	doc.Set("tui.keys", "down", []string{"j"})
+	doc.Set("", "x", int64(1))
+	doc.Set("defaults", "tags", []string{"a", `b"c`})
+
+	want := `# settings
+store.path = "a.json"
+store.backend = "json"
+x = 1
+
+[tui] # look
+theme = "light"
+keys.up = "k"
+keys.down = ["j"]
+
+[defaults]
+tags = ["a", "b\"c"]
+`
+	if got := doc.String(); got != want {
+		t.Fatalf("String=\n%s\nwant=\n%s", got, want)
+	}
+	if _, err := ParseDocument(doc.String()); err != nil {
+		t.Fatalf("re-parse err=%v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home := isolate(t)
+	path := writeConfig(t, `
+[store]
+path = "base.json"
+
+[defaults]
+priority = "medium"
+tags = ["inbox"]
+
+[dates]
+week_start = "Sun"
+format = "02/01/2006"
+timezone = "Asia/Kathmandu"
+
+[tui]
+theme = "plain"
+keys.quit = "Q"
+some_future_key = true
+
+[hooks]
+timeout = "30s"
+
+[plan]
+capacity = "5h"
+days_off = ["fri", "Saturday"]
+
+[profiles.work]
+store.path = "~/work/todos.json"
+defaults.tags = ["work"]
+`)
+
+	c, err := Load(Options{File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Store.Path != filepath.Join(filepath.Dir(path), "base.json") {
+		t.Fatalf("path=%s", c.Store.Path)
+	}
+	if c.Defaults.Priority != "medium" || !slices.Equal(c.Defaults.Tags, []string{"inbox"}) {
+		t.Fatalf("defaults=%+v", c.Defaults)
+	}
+	if c.Dates.WeekStart != time.Sunday || c.Dates.Format != "02/01/2006" || c.Dates.Location.String() != "Asia/Kathmandu" {
+		t.Fatalf("dates=%+v", c.Dates)
+	}
+	if c.TUI.Theme != "plain" || !slices.Equal(c.TUI.Keys["quit"], []string{"Q"}) {
+		t.Fatalf("tui=%+v", c.TUI)
+	}
+	if c.Hooks.Timeout != 30*time.Second {
+		t.Fatalf("hooks=%+v", c.Hooks)
+	}
+	if c.Plan.Capacity.String() != "5h" || !slices.Equal(c.Plan.DaysOff, []time.Weekday{time.Friday, time.Saturday}) {
+		t.Fatalf("plan=%+v", c.Plan)
+	}
+
+	c, err = Load(Options{File: path, Profile: "work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Store.Path != filepath.Join(home, "work", "todos.json") || c.Defaults.Priority != "medium" || c.Defaults.Tags[0] != "work" {
+		t.Fatalf("work profile=%+v", c)
+	}
+
+	// env: profile, then GOTODO_DB beats the file; --file beats both
+	t.Setenv("GOTODO_PROFILE", "work")
+	t.Setenv("GOTODO_DB", "/tmp/env.json")
+	c, err = Load(Options{File: path})
+	if err != nil || c.Profile != "work" || c.Store.Path != "/tmp/env.json" {
+		t.Fatalf("env c=%+v err=%v", c, err)
+	}
+	if got := c.DBPath("/tmp/flag.json"); got != "/tmp/flag.json" {
+		t.Fatalf("DBPath=%s", got)
+	}
+
+	if _, err := Load(Options{File: path, Profile: "home"}); err == nil || !strings.Contains(err.Error(), `unknown profile "home"`) {
+		t.Fatalf("unknown profile err=%v", err)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	home := isolate(t)
+
+	c, err := Load(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.File != filepath.Join(home, ".config", "gotodo", "config.toml") {
+		t.Fatalf("file=%s", c.File)
+	}
+	if c.Store.Path != filepath.Join(home, ".local", "share", "gotodo", "todos.json") {
+		t.Fatalf("path=%s", c.Store.Path)
+	}
+	if c.Defaults.Priority != "low" || c.Dates.WeekStart != time.Monday || c.TUI.Theme != "auto" {
+		t.Fatalf("c=%+v", c)
+	}
+
+	// an existing store in the old location keeps being used
+	legacy := filepath.Join(home, ".gotodo", "todos.json")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "cfg"))
+	c, err = Load(Options{})
+	if err != nil || c.Store.Path != legacy || c.File != filepath.Join(home, "cfg", "gotodo", "config.toml") {
+		t.Fatalf("c=%+v err=%v", c, err)
+	}
+}
+
+func TestLoad_Workspace(t *testing.T) {
+	home := isolate(t)
+	root := filepath.Join(home, "src", "api")
+	sub := filepath.Join(root, "cmd")
+	if err := os.MkdirAll(filepath.Join(root, ".gotodo"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	global := filepath.Join(home, ".local", "share", "gotodo", "todos.json")
+
+	c, err := Load(Options{Dir: sub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Workspace != root || c.Store.Path != filepath.Join(root, ".gotodo", "todos.json") || c.Store.Global != global {
+		t.Fatalf("c=%+v", c)
+	}
+
+	// no Dir: the global store
+	if c, _ := Load(Options{}); c.Workspace != "" || c.Store.Path != global {
+		t.Fatalf("without Dir c=%+v", c)
+	}
+	// GOTODO_DB beats the workspace
+	t.Setenv("GOTODO_DB", "/tmp/env.json")
+	if c, _ := Load(Options{Dir: sub}); c.Workspace != "" || c.Store.Path != "/tmp/env.json" {
+		t.Fatalf("with GOTODO_DB c=%+v", c)
+	}
+}
+
+func TestLoad_BadValue(t *testing.T) {
+	isolate(t)
+	path := writeConfig(t, "[defaults]\npriority = \"urgent\"\n")
+	_, err := Load(Options{File: path})
+	if err == nil || !strings.Contains(err.Error(), ":2: defaults.priority") {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := ParseDocument(`[store]
+backend = "sqlite"
+path = "x.json"
+
+[dates]
+format = "Jan 2"
+week_start = "someday"
+timezone = "Mars/Olympus"
+
+[tui]
+theme = "neon"
+colour = "red"
+
+[profiles.work.defaults]
+tags = "work"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range Validate(doc) {
+		got = append(got, strings.Join(strings.SplitN(e.Error(), ":", 3)[:2], ":"))
+	}
+	want := []string{
+		"line 2: store.backend",
+		"line 6: dates.format",
+		"line 7: dates.week_start",
+		"line 8: dates.timezone",
+		"line 11: tui.theme",
+		"line 12: tui.colour",
+		"line 15: profiles.work.defaults.tags",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("problems=%q want=%q", got, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	isolate(t)
+	path := filepath.Join(t.TempDir(), "gotodo", "config.toml")
+
+	if err := Set(path, "", "defaults.tags", "inbox, home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(path, "", "tui.keys.complete", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(path, "work", "store.path", "/srv/work.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(path, "", "defaults.priority", "urgent"); err == nil {
+		t.Fatalf("bad value written")
+	}
+	if err := Set(path, "", "nope", "1"); err == nil {
+		t.Fatalf("unknown key written")
+	}
+
+	c, err := Load(Options{File: path, Profile: "work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(c.Defaults.Tags, []string{"inbox", "home"}) || !slices.Equal(c.TUI.Keys["complete"], []string{"x"}) || c.Store.Path != "/srv/work.json" {
+		t.Fatalf("c=%+v", c)
+	}
+	if v, _ := c.Get("dates.week_start"); v != "monday" {
+		t.Fatalf("Get=%v", v)
+	}
+}
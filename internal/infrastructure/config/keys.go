@@ -0,0 +1,268 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// setting is one known key: how to check and apply a value, and how to
+// read it back.
+type setting struct {
+	name  string
+	list  bool // an array of strings
+	apply func(c *Config, v any) error
+	get   func(c Config) any
+}
+
+var settings = []setting{
+	{
+		name: "store.backend",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			if s != "json" {
+				return fmt.Errorf("unsupported backend %q (only json)", s)
+			}
+			c.Store.Backend = s
+			return nil
+		},
+		get: func(c Config) any { return c.Store.Backend },
+	},
+	{
+		name: "store.path",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			c.Store.Path = s
+			return err
+		},
+		get: func(c Config) any { return c.Store.Path },
+	},
+	{
+		name: "defaults.priority",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			if _, err := todo.NewPriority(s); err != nil {
+				return fmt.Errorf("want low, medium or high, got %q", s)
+			}
+			c.Defaults.Priority = s
+			return nil
+		},
+		get: func(c Config) any { return c.Defaults.Priority },
+	},
+	{
+		name: "defaults.tags",
+		list: true,
+		apply: func(c *Config, v any) error {
+			tags, err := asList(v, false)
+			c.Defaults.Tags = tags
+			return err
+		},
+		get: func(c Config) any { return c.Defaults.Tags },
+	},
+	{
+		name: "dates.week_start",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			d, ok := parseWeekday(s)
+			if !ok {
+				return fmt.Errorf("want a weekday name, got %q", s)
+			}
+			c.Dates.WeekStart = d
+			return nil
+		},
+		get: func(c Config) any { return strings.ToLower(c.Dates.WeekStart.String()) },
+	},
+	{
+		name: "dates.format",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			// the layout must show year, month and day to be read back
+			ref := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+			if t, err := time.Parse(s, ref.Format(s)); err != nil || !t.Equal(ref) {
+				return fmt.Errorf("%q is not a Go date layout with year, month and day (like 2006-01-02)", s)
+			}
+			c.Dates.Format = s
+			return nil
+		},
+		get: func(c Config) any { return c.Dates.Format },
+	},
+	{
+		name: "dates.timezone",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			loc := time.Local
+			if !strings.EqualFold(s, "local") {
+				if loc, err = time.LoadLocation(s); err != nil || s == "" {
+					return fmt.Errorf("want an IANA zone like Asia/Kathmandu, or local, got %q", s)
+				}
+			}
+			c.Dates.Timezone, c.Dates.Location = s, loc
+			return nil
+		},
+		get: func(c Config) any { return c.Dates.Timezone },
+	},
+	{
+		name: "tui.theme",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(Themes, s) {
+				return fmt.Errorf("want one of %s, got %q", strings.Join(Themes, ", "), s)
+			}
+			c.TUI.Theme = s
+			return nil
+		},
+		get: func(c Config) any { return c.TUI.Theme },
+	},
+	{
+		name: "hooks.timeout",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("want a duration like 5s or 1m, got %q", s)
+			}
+			c.Hooks.Timeout = d
+			return nil
+		},
+		get: func(c Config) any { return c.Hooks.Timeout.String() },
+	},
+	{
+		name: "plan.capacity",
+		apply: func(c *Config, v any) error {
+			s, err := asString(v)
+			if err != nil {
+				return err
+			}
+			e, err := todo.ParseEstimate(s)
+			if err != nil {
+				return fmt.Errorf("want a time like 6h or story points like 8pt, got %q", s)
+			}
+			c.Plan.Capacity = e
+			return nil
+		},
+		get: func(c Config) any { return c.Plan.Capacity.String() },
+	},
+	{
+		name: "plan.days_off",
+		list: true,
+		apply: func(c *Config, v any) error {
+			names, err := asList(v, false)
+			if err != nil {
+				return err
+			}
+			days := []time.Weekday{}
+			for _, s := range names {
+				d, ok := parseWeekday(s)
+				if !ok {
+					return fmt.Errorf("want weekday names, got %q", s)
+				}
+				days = append(days, d)
+			}
+			if len(days) >= 7 {
+				return errors.New("at least one day must be a working day")
+			}
+			c.Plan.DaysOff = days
+			return nil
+		},
+		get: func(c Config) any {
+			names := []string{}
+			for _, d := range c.Plan.DaysOff {
+				names = append(names, strings.ToLower(d.String()))
+			}
+			return names
+		},
+	},
+}
+
+// parseWeekday reads a weekday's name, or its first three letters.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := strings.ToLower(d.String()); strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Themes are the accepted values of tui.theme.
+var Themes = []string{"auto", "dark", "light", "plain"}
+
+// KeyActions are the TUI actions that can be rebound with
+// tui.keys.<action>, as a key or a list of keys.
+var KeyActions = []string{"quit", "up", "down", "complete", "reload", "project", "add", "timer"}
+
+func init() {
+	for _, action := range KeyActions {
+		settings = append(settings, setting{
+			name: "tui.keys." + action,
+			list: true,
+			apply: func(c *Config, v any) error {
+				keys, err := asList(v, true)
+				if err != nil {
+					return err
+				}
+				if len(keys) == 0 {
+					return errors.New("needs at least one key")
+				}
+				c.TUI.Keys[action] = keys
+				return nil
+			},
+			get: func(c Config) any { return c.TUI.Keys[action] },
+		})
+	}
+}
+
+func lookup(name string) (setting, bool) {
+	for _, s := range settings {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+func asString(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("want a string, got %s", formatValue(v))
+	}
+	return s, nil
+}
+
+// asList accepts an array of strings, or a single string when single is set.
+func asList(v any, single bool) ([]string, error) {
+	switch x := v.(type) {
+	case []string:
+		return x, nil
+	case string:
+		if single {
+			return []string{x}, nil
+		}
+	}
+	return nil, fmt.Errorf("want an array of strings, got %s", formatValue(v))
+}
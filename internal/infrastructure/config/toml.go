@@ -0,0 +1,329 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Document is a TOML file reduced to the subset gotodo needs: [tables],
+// key = value pairs, and values that are strings, integers, booleans or
+// arrays of strings. It keeps the original lines so Set can edit a file
+// without losing comments or layout.
+type Document struct {
+	lines []docLine
+}
+
+type docLine struct {
+	raw    string
+	header string // enclosing [header] ("" before the first one)
+	table  string // table of the key: header plus any dotted prefix
+	key    string // set for key = value lines
+	rawKey string // key as written, relative to header
+	value  string // raw value text
+	num    int    // 1-based line number in the parsed input
+}
+
+// Entry is one key = value pair of a Document.
+type Entry struct {
+	Table, Key string
+	Value      any // string, int64, bool or []string
+	Line       int
+}
+
+// Path is the dotted name of the entry, e.g. "tui.keys.quit".
+func (e Entry) Path() string {
+	if e.Table == "" {
+		return e.Key
+	}
+	return e.Table + "." + e.Key
+}
+
+// ParseDocument parses src, reporting the first syntax error with its line.
+func ParseDocument(src string) (*Document, error) {
+	d := &Document{}
+	table := ""
+	seen := map[string]bool{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		ln := docLine{raw: raw, header: table, table: table, num: i + 1}
+		s := strings.TrimSpace(stripComment(raw))
+
+		switch {
+		case s == "":
+		case strings.HasPrefix(s, "["):
+			if !strings.HasSuffix(s, "]") || strings.HasPrefix(s, "[[") {
+				return nil, fmt.Errorf("line %d: bad table header %q", i+1, s)
+			}
+			name := strings.TrimSpace(s[1 : len(s)-1])
+			if !validPath(name) {
+				return nil, fmt.Errorf("line %d: bad table name %q", i+1, name)
+			}
+			table, ln.header, ln.table = name, name, name
+		default:
+			k, v, ok := strings.Cut(s, "=")
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			if !ok || !validPath(k) || v == "" {
+				return nil, fmt.Errorf("line %d: expected key = value", i+1)
+			}
+			if _, err := parseValue(v); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", i+1, k, err)
+			}
+			ln.rawKey = k
+			// dotted keys belong to a sub-table
+			if dot := strings.LastIndex(k, "."); dot >= 0 {
+				ln.table = joinPath(table, k[:dot])
+				k = k[dot+1:]
+			}
+			full := joinPath(ln.table, k)
+			if seen[full] {
+				return nil, fmt.Errorf("line %d: %s is set twice", i+1, full)
+			}
+			seen[full] = true
+			ln.key, ln.value = k, v
+		}
+		d.lines = append(d.lines, ln)
+	}
+	return d, nil
+}
+
+// Entries returns every key = value pair in file order.
+func (d *Document) Entries() []Entry {
+	var out []Entry
+	for _, ln := range d.lines {
+		if ln.key == "" {
+			continue
+		}
+		v, _ := parseValue(ln.value)
+		out = append(out, Entry{Table: ln.table, Key: ln.key, Value: v, Line: ln.num})
+	}
+	return out
+}
+
+// Set writes table.key = value, replacing an existing line or adding one
+// at the end of the table (creating the table if needed).
+func (d *Document) Set(table, key string, value any) {
+	text := formatValue(value)
+	full := joinPath(table, key)
+	last := -1
+	for i, ln := range d.lines {
+		if ln.table == table && ln.key == key {
+			indent := ln.raw[:len(ln.raw)-len(strings.TrimLeft(ln.raw, " \t"))]
+			d.lines[i].raw = indent + ln.rawKey + " = " + text
+			d.lines[i].value = text
+			return
+		}
+		if ln.table == table && (ln.key != "" || ln.header == table && isHeader(ln.raw)) {
+			last = i
+		}
+	}
+
+	if last >= 0 {
+		header := d.lines[last].header
+		rawKey := full
+		if header != "" {
+			rawKey = strings.TrimPrefix(full, header+".")
+		}
+		ln := docLine{raw: rawKey + " = " + text, header: header, table: table, key: key, rawKey: rawKey, value: text}
+		d.lines = append(d.lines[:last+1], append([]docLine{ln}, d.lines[last+1:]...)...)
+		return
+	}
+
+	ln := docLine{raw: key + " = " + text, header: table, table: table, key: key, rawKey: key, value: text}
+	if table == "" {
+		// top-level keys must come before the first header
+		at := len(d.lines)
+		for i, l := range d.lines {
+			if isHeader(l.raw) {
+				at = i
+				break
+			}
+		}
+		for at > 0 && strings.TrimSpace(d.lines[at-1].raw) == "" {
+			at--
+		}
+		d.lines = append(d.lines[:at], append([]docLine{ln}, d.lines[at:]...)...)
+		return
+	}
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1].raw) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, docLine{header: table, table: table})
+	}
+	d.lines = append(d.lines, docLine{raw: "[" + table + "]", header: table, table: table}, ln)
+}
+
+func isHeader(raw string) bool {
+	return strings.HasPrefix(strings.TrimSpace(raw), "[")
+}
+
+// String renders the document, ending with a newline.
+func (d *Document) String() string {
+	var b strings.Builder
+	for _, ln := range d.lines {
+		b.WriteString(ln.raw)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func validPath(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func joinPath(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "." + b
+}
+
+// stripComment drops a trailing # comment that is not inside a string.
+func stripComment(s string) string {
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || !escaped(s, i)) {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// escaped reports whether s[i] is preceded by an odd number of backslashes.
+func escaped(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func parseValue(s string) (any, error) {
+	switch {
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case strings.HasPrefix(s, `"`), strings.HasPrefix(s, "'"):
+		v, rest, err := parseString(s)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("unexpected %q after string", rest)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "["):
+		return parseArray(s)
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value %s", s)
+	}
+	return n, nil
+}
+
+func parseString(s string) (string, string, error) {
+	q := s[0]
+	if q == '\'' {
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), s[i+1:], nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(s[i])
+			default:
+				return "", "", fmt.Errorf("unsupported escape \\%c", s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+func parseArray(s string) ([]string, error) {
+	rest := strings.TrimSpace(s[1:])
+	out := []string{}
+	for {
+		if strings.HasPrefix(rest, "]") {
+			if strings.TrimSpace(rest[1:]) != "" {
+				return nil, fmt.Errorf("unexpected %q after array", rest[1:])
+			}
+			return out, nil
+		}
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			return nil, fmt.Errorf("arrays may only hold strings")
+		}
+		v, after, err := parseString(rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		rest = strings.TrimSpace(after)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case bool:
+		return strconv.FormatBool(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case int:
+		return strconv.Itoa(x)
+	case []string:
+		parts := make([]string, len(x))
+		for i, s := range x {
+			parts[i] = quote(s)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return quote(fmt.Sprint(x))
+	}
+}
+
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
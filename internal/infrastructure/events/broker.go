@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// DefaultBufferSize is how many past events a Broker keeps for resuming.
+const DefaultBufferSize = 1024
+
+// subscriberBuffer is how far a subscriber may fall behind before it is
+// dropped; it can reconnect and resume from the buffer.
+const subscriberBuffer = 64
+
+// Message is a published event with a sequence number clients can resume
+// from. IDs start at 1 and increase by one per event.
+type Message struct {
+	ID         uint64
+	Event      string
+	TodoID     todo.TodoID
+	OccurredAt time.Time
+}
+
+// Broker fans published events out to live subscribers and keeps the
+// most recent ones in a ring buffer.
+type Broker struct {
+	// Epoch differs between brokers (and so between server restarts);
+	// clients pair it with message IDs to tell a restart from a gap.
+	Epoch string
+
+	mu   sync.Mutex
+	buf  []Message // ring, len == capacity once full
+	head int       // next write position when full
+	last uint64    // ID of the newest message
+	subs map[chan Message]struct{}
+}
+
+func NewBroker(size int) *Broker {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Broker{
+		Epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		buf:   make([]Message, 0, size),
+		subs:  map[chan Message]struct{}{},
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, evs []todo.Event) error {
+	msgs := make([]Message, 0, len(evs))
+	for _, e := range evs {
+		msgs = append(msgs, Message{Event: todo.EventName(e), TodoID: todo.EventTodoID(e), OccurredAt: todo.EventTime(e)})
+	}
+	b.publish(msgs)
+	return nil
+}
+
+// publish numbers msgs and sends them out.
+func (b *Broker) publish(msgs []Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, m := range msgs {
+		b.last++
+		m.ID = b.last
+
+		if len(b.buf) < cap(b.buf) {
+			b.buf = append(b.buf, m)
+		} else {
+			b.buf[b.head] = m
+			b.head = (b.head + 1) % len(b.buf)
+		}
+
+		for ch := range b.subs {
+			select {
+			case ch <- m:
+			default:
+				// too slow: drop it rather than block publishers
+				delete(b.subs, ch)
+				close(ch)
+			}
+		}
+	}
+}
+
+// Subscribe returns the buffered messages after lastID and a channel of
+// new ones. lastID 0 means "from now on". complete is false when messages
+// after lastID have already been evicted, so the client has missed some
+// and should reload its state. The channel is closed if the subscriber
+// falls too far behind; call cancel when done.
+func (b *Broker) Subscribe(lastID uint64) (backlog []Message, live <-chan Message, cancel func(), complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		msgs := b.ordered()
+		if (len(msgs) > 0 && msgs[0].ID > lastID+1) || lastID > b.last {
+			complete = false // evicted, or an ID from before a restart
+		}
+		for _, m := range msgs {
+			if m.ID > lastID {
+				backlog = append(backlog, m)
+			}
+		}
+	}
+
+	ch := make(chan Message, subscriberBuffer)
+	b.subs[ch] = struct{}{}
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+	return backlog, ch, cancel, complete
+}
+
+// LastID is the ID of the newest message, 0 before the first.
+func (b *Broker) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last
+}
+
+func (b *Broker) ordered() []Message {
+	if len(b.buf) < cap(b.buf) {
+		return b.buf
+	}
+	return append(append([]Message(nil), b.buf[b.head:]...), b.buf[:b.head]...)
+}
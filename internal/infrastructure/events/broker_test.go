@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+func publishN(t *testing.T, b *Broker, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		ev := todo.TodoCreated{ID: todo.TodoID(fmt.Sprintf("t%d", i)), OccurredAt: time.Unix(int64(i), 0)}
+		if err := b.Publish(context.Background(), []todo.Event{ev}); err != nil {
+			t.Fatalf("Publish err=%v", err)
+		}
+	}
+}
+
+func ids(msgs []Message) []uint64 {
+	var out []uint64
+	for _, m := range msgs {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+func TestBroker_ResumeFromBuffer(t *testing.T) {
+	b := NewBroker(3)
+	publishN(t, b, 5) // buffer now holds 3, 4, 5
+
+	backlog, _, cancel, complete := b.Subscribe(2)
+	defer cancel()
+	if !complete || fmt.Sprint(ids(backlog)) != "[3 4 5]" {
+		t.Fatalf("Subscribe(2) backlog=%v complete=%v", ids(backlog), complete)
+	}
+	if backlog[0].Event != "todo.created" || backlog[0].TodoID != "t2" {
+		t.Fatalf("message=%+v", backlog[0])
+	}
+
+	if backlog, _, cancel, complete := b.Subscribe(1); complete || len(backlog) != 3 {
+		t.Fatalf("Subscribe(1) backlog=%v complete=%v want gap", ids(backlog), complete)
+	} else {
+		cancel()
+	}
+	if _, _, cancel, complete := b.Subscribe(99); complete {
+		t.Fatalf("Subscribe(99) complete=true for an ID from the future")
+	} else {
+		cancel()
+	}
+	if backlog, _, cancel, complete := b.Subscribe(0); !complete || len(backlog) != 0 {
+		t.Fatalf("Subscribe(0) backlog=%v complete=%v", ids(backlog), complete)
+	} else {
+		cancel()
+	}
+}
+
+func TestBroker_LiveAndSlowSubscribers(t *testing.T) {
+	b := NewBroker(0)
+
+	_, live, cancel, _ := b.Subscribe(0)
+	defer cancel()
+	publishN(t, b, 1)
+	if m := <-live; m.ID != 1 {
+		t.Fatalf("live message=%+v", m)
+	}
+
+	_, slow, cancelSlow, _ := b.Subscribe(0)
+	defer cancelSlow()
+	publishN(t, b, subscriberBuffer+1)
+
+	n := 0
+	for range slow {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Fatalf("slow subscriber got %d messages before being dropped, want=%d", n, subscriberBuffer)
+	}
+	if b.LastID() != subscriberBuffer+2 {
+		t.Fatalf("LastID=%d", b.LastID())
+	}
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// ChangedEvent is published for a todo another process changed in a way
+// that no domain event name describes; subscribers reload it.
+const ChangedEvent = "todo.changed"
+
+// Follower feeds a Broker the changes other processes (the CLI, the TUI)
+// make to the store, which never pass through this process's publishers.
+// Use it as the publisher in place of the Broker, so this process's own
+// changes are forwarded once and not reported again when the file moves.
+type Follower struct {
+	Broker *Broker
+	Repo   ports.TodoRepository
+
+	mu   sync.Mutex
+	seen map[todo.TodoID]todo.Todo
+}
+
+// Publish records the state the events left their todos in, then
+// forwards them to the Broker.
+func (f *Follower) Publish(ctx context.Context, evs []todo.Event) error {
+	f.mu.Lock()
+	if f.seen != nil {
+		for _, e := range evs {
+			id := todo.EventTodoID(e)
+			if t, err := f.Repo.GetByID(ctx, id); err == nil {
+				f.seen[id] = t
+			} else {
+				delete(f.seen, id)
+			}
+		}
+	}
+	f.mu.Unlock()
+	return f.Broker.Publish(ctx, evs)
+}
+
+// Run reads the store each time changed fires and publishes, for every
+// todo that differs from the last read, "todo.created", "todo.deleted"
+// or ChangedEvent. It returns when ctx is done.
+func (f *Follower) Run(ctx context.Context, changed <-chan struct{}) error {
+	if _, err := f.read(ctx); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+		msgs, err := f.read(ctx)
+		if err != nil {
+			continue // mid-write or briefly locked; the next change retries
+		}
+		f.Broker.publish(msgs)
+	}
+}
+
+// read replaces the snapshot with the store and returns what changed.
+func (f *Follower) read(ctx context.Context) ([]Message, error) {
+	tds, err := f.Repo.List(ctx, ports.ListSpec{IncludeDeleted: true, IncludeUnstarted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	first := f.seen == nil
+	now := time.Now().UTC()
+	next := make(map[todo.TodoID]todo.Todo, len(tds))
+	var msgs []Message
+	for _, t := range tds {
+		next[t.ID] = t
+		old, ok := f.seen[t.ID]
+		switch {
+		case first:
+		case !ok && t.DeletedAt == nil:
+			msgs = append(msgs, Message{Event: "todo.created", TodoID: t.ID, OccurredAt: t.CreatedAt})
+		case !ok:
+		case old.DeletedAt == nil && t.DeletedAt != nil:
+			msgs = append(msgs, Message{Event: "todo.deleted", TodoID: t.ID, OccurredAt: *t.DeletedAt})
+		case !reflect.DeepEqual(old, t):
+			msgs = append(msgs, Message{Event: ChangedEvent, TodoID: t.ID, OccurredAt: now})
+		}
+	}
+	var gone []todo.TodoID
+	for id, t := range f.seen {
+		if _, ok := next[id]; !ok && t.DeletedAt == nil {
+			gone = append(gone, id)
+		}
+	}
+	slices.Sort(gone)
+	for _, id := range gone {
+		msgs = append(msgs, Message{Event: "todo.deleted", TodoID: id, OccurredAt: now})
+	}
+
+	f.seen = next
+	return msgs, nil
+}
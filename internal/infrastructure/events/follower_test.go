@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+func TestFollower_PublishesOtherProcessesChanges(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todos.json")
+	ours, theirs := jsonstore.NewRepository(path), jsonstore.NewRepository(path)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	mk := func(id string) todo.Todo {
+		td, _, _ := todo.NewTodo(todo.NewTodoParams{ID: todo.TodoID(id), Title: todo.Title("todo " + id), Priority: todo.PriorityLow, Now: now})
+		if err := theirs.Create(ctx, td); err != nil {
+			t.Fatal(err)
+		}
+		return td
+	}
+	a, b := mk("a"), mk("b")
+
+	broker := NewBroker(16)
+	f := &Follower{Broker: broker, Repo: ours}
+	changed := make(chan struct{})
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() { _ = f.Run(runCtx, changed) }()
+	_, live, cancel, _ := broker.Subscribe(0)
+	defer cancel()
+
+	next := func() Message {
+		t.Helper()
+		select {
+		case m := <-live:
+			return m
+		case <-time.After(2 * time.Second):
+			t.Fatal("no message")
+			return Message{}
+		}
+	}
+
+	// another process: adds c, renames a and deletes b
+	changed <- struct{}{} // the first read is the baseline
+	mk("c")
+	a, _, _ = a.ChangeTitle("renamed", now)
+	_ = theirs.Update(ctx, a)
+	b, _, _ = b.SoftDelete(now)
+	_ = theirs.Update(ctx, b)
+	changed <- struct{}{}
+
+	got := map[string]string{}
+	for range 3 {
+		m := next()
+		got[m.TodoID.String()] = m.Event
+	}
+	if got["a"] != ChangedEvent || got["b"] != "todo.deleted" || got["c"] != "todo.created" {
+		t.Fatalf("events=%v", got)
+	}
+
+	// this process: published once, not again when the file moves
+	a, evs, _ := a.Complete(now)
+	_ = ours.Update(ctx, a)
+	_ = f.Publish(ctx, evs)
+	changed <- struct{}{}
+	if m := next(); m.Event != "todo.completed" {
+		t.Fatalf("message=%+v", m)
+	}
+	select {
+	case m := <-live:
+		t.Fatalf("reported twice: %+v", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"context"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// MultiPublisher fans events out to every publisher in order.
+// All publishers run even if one fails; the first error is returned.
+type MultiPublisher []ports.EventPublisher
+
+func (m MultiPublisher) Publish(ctx context.Context, evs []todo.Event) error {
+	var first error
+	for _, p := range m {
+		if err := p.Publish(ctx, evs); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
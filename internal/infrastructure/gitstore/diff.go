@@ -0,0 +1,149 @@
+package gitstore
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+// Change is how one todo differs between two versions of the store.
+type Change struct {
+	ID     string
+	Title  string // newest title
+	Kind   string // "added", "removed" or "changed"
+	Fields []FieldChange
+}
+
+type FieldChange struct {
+	Field    string
+	Old, New string
+}
+
+// Diff compares two versions of the store, in the order of the newer one
+// (removed todos last).
+func Diff(old, new []todo.Todo) []Change {
+	before := map[todo.TodoID]todo.Todo{}
+	for _, t := range old {
+		before[t.ID] = t
+	}
+
+	var out []Change
+	for _, t := range new {
+		o, ok := before[t.ID]
+		delete(before, t.ID)
+		if !ok {
+			out = append(out, Change{ID: t.ID.String(), Title: t.Title.String(), Kind: "added"})
+			continue
+		}
+		if fs := diffFields(o, t); len(fs) > 0 {
+			out = append(out, Change{ID: t.ID.String(), Title: t.Title.String(), Kind: "changed", Fields: fs})
+		}
+	}
+
+	var removed []Change
+	for _, t := range before {
+		removed = append(removed, Change{ID: t.ID.String(), Title: t.Title.String(), Kind: "removed"})
+	}
+	slices.SortFunc(removed, func(a, b Change) int { return cmp.Compare(a.ID, b.ID) })
+	return append(out, removed...)
+}
+
+func diffFields(a, b todo.Todo) []FieldChange {
+	var out []FieldChange
+	add := func(field, x, y string) {
+		if x != y {
+			out = append(out, FieldChange{Field: field, Old: x, New: y})
+		}
+	}
+	add("title", a.Title.String(), b.Title.String())
+	add("status", string(a.Status), string(b.Status))
+	add("priority", a.Priority.String(), b.Priority.String())
+	add("tags", strings.Join(a.Tags, ","), strings.Join(b.Tags, ","))
+	add("due", dueString(a.DueDate), dueString(b.DueDate))
+	add("parent", a.ParentID.String(), b.ParentID.String())
+	add("project", a.Project.String(), b.Project.String())
+	add("scheduled", dueString(a.Scheduled), dueString(b.Scheduled))
+	add("remind", dueString(a.RemindAt), dueString(b.RemindAt))
+	add("estimate", estimateString(a.Estimate), estimateString(b.Estimate))
+	add("tracked", trackedString(a), trackedString(b))
+	add("deleted", deletedString(a), deletedString(b))
+	return out
+}
+
+func dueString(d *todo.DueDate) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+func estimateString(e *todo.Estimate) string {
+	if e == nil {
+		return ""
+	}
+	return e.String()
+}
+
+// trackedString is the finished time tracked on t, noting a running timer.
+func trackedString(t todo.Todo) string {
+	if len(t.TimeEntries) == 0 {
+		return ""
+	}
+	var d time.Duration
+	for _, e := range t.TimeEntries {
+		if e.End != nil {
+			d += e.End.Sub(e.Start)
+		}
+	}
+	s := d.String()
+	if _, ok := t.Timer(); ok {
+		s += " + running"
+	}
+	return s
+}
+
+func deletedString(t todo.Todo) string {
+	if t.DeletedAt == nil {
+		return ""
+	}
+	return "yes"
+}
+
+// Load returns the todos at rev, or in the work tree when rev is "".
+// A rev where the store didn't exist yet gives no todos.
+func (r Repo) Load(ctx context.Context, rev string) ([]todo.Todo, error) {
+	var b []byte
+	var err error
+	if rev == "" {
+		b, err = os.ReadFile(filepath.Join(r.Dir, r.File))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+	} else {
+		b, err = r.Show(ctx, rev)
+	}
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return jsonstore.Decode(b)
+}
+
+// DiffRevs compares the store at from and to ("" is the work tree).
+func (r Repo) DiffRevs(ctx context.Context, from, to string) ([]Change, error) {
+	old, err := r.Load(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := r.Load(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(old, cur), nil
+}
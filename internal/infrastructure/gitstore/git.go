@@ -0,0 +1,182 @@
+// Package gitstore keeps the data directory as a git repository: every
+// mutation is committed with a message derived from its domain events, and
+// the history can be listed, diffed and synced through any git remote.
+//
+// It drives the git binary, which must be on PATH.
+package gitstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotRepo is returned when the data directory is not a git repository.
+var ErrNotRepo = errors.New("gitstore: data directory is not a git repository (run `todo git init`)")
+
+// ignored are the store's scratch files, which never belong in history.
+const ignored = `*.lockdir/
+*.tmp
+*.replica
+webhook-queue.json
+`
+
+// Repo is a git work tree holding the store file.
+type Repo struct {
+	Dir  string
+	File string // store file name relative to Dir, e.g. "todos.json"
+}
+
+// Open returns the repository for the store at path, or ErrNotRepo.
+func Open(path string) (Repo, error) {
+	r := Repo{Dir: filepath.Dir(path), File: filepath.Base(path)}
+	if fi, err := os.Stat(filepath.Join(r.Dir, ".git")); err != nil || !fi.IsDir() {
+		return r, ErrNotRepo
+	}
+	return r, nil
+}
+
+// Init turns the directory of the store at path into a repository and
+// commits the current state. It is a no-op for an existing repository.
+func Init(ctx context.Context, path string) (Repo, error) {
+	if r, err := Open(path); err == nil {
+		return r, nil
+	}
+	r := Repo{Dir: filepath.Dir(path), File: filepath.Base(path)}
+	if _, err := r.git(ctx, "init", "--quiet"); err != nil {
+		return r, err
+	}
+	gi := filepath.Join(r.Dir, ".gitignore")
+	if _, err := os.Stat(gi); os.IsNotExist(err) {
+		if err := os.WriteFile(gi, []byte(ignored), 0o600); err != nil {
+			return r, err
+		}
+	}
+	if _, err := r.commit(ctx, "init: gotodo store", ".gitignore", r.File); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Commit commits the store file if it changed. It reports whether a commit
+// was made.
+func (r Repo) Commit(ctx context.Context, msg string) (bool, error) {
+	return r.commit(ctx, msg, r.File)
+}
+
+func (r Repo) commit(ctx context.Context, msg string, paths ...string) (bool, error) {
+	var existing []string
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(r.Dir, p)); err == nil {
+			existing = append(existing, p)
+		}
+	}
+	if len(existing) > 0 {
+		if _, err := r.git(ctx, append([]string{"add", "--"}, existing...)...); err != nil {
+			return false, err
+		}
+	}
+	if _, err := r.git(ctx, "diff", "--cached", "--quiet"); err == nil {
+		return false, nil // nothing staged
+	}
+	if _, err := r.git(ctx, "commit", "--quiet", "--no-verify", "-m", msg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Entry is one commit in the store's history.
+type Entry struct {
+	Hash    string
+	Time    time.Time
+	Author  string
+	Subject string
+}
+
+// Log returns up to n commits touching the store file, newest first
+// (n <= 0: all of them).
+func (r Repo) Log(ctx context.Context, n int) ([]Entry, error) {
+	args := []string{"log", "--format=%H%x00%ct%x00%an%x00%s"}
+	if n > 0 {
+		args = append(args, "-n", strconv.Itoa(n))
+	}
+	out, err := r.git(ctx, append(args, "--", r.File)...)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := strings.Split(line, "\x00")
+		if len(f) != 4 {
+			continue
+		}
+		sec, _ := strconv.ParseInt(f[1], 10, 64)
+		entries = append(entries, Entry{Hash: f[0], Time: time.Unix(sec, 0), Author: f[2], Subject: f[3]})
+	}
+	return entries, nil
+}
+
+// Show returns the store file as of rev, or nil if it didn't exist then.
+func (r Repo) Show(ctx context.Context, rev string) ([]byte, error) {
+	out, err := r.git(ctx, "show", rev+":./"+r.File)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "exists on disk, but not in") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// git runs a git command in the work tree.
+func (r Repo) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if args[0] == "commit" || args[0] == "merge" {
+		cmd.Env = append(cmd.Env, r.identity()...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return stdout.String(), fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// identity is a fallback author so a machine without user.name and
+// user.email configured still records history.
+func (r Repo) identity() []string {
+	var env []string
+	for _, id := range []struct{ key, env, def string }{
+		{"user.name", "NAME", "gotodo"},
+		{"user.email", "EMAIL", "gotodo@localhost"},
+	} {
+		cmd := exec.Command("git", "config", "--get", id.key)
+		cmd.Dir = r.Dir
+		if out, _ := cmd.Output(); len(bytes.TrimSpace(out)) > 0 {
+			continue
+		}
+		for _, who := range []string{"GIT_AUTHOR_", "GIT_COMMITTER_"} {
+			if os.Getenv(who+id.env) == "" {
+				env = append(env, who+id.env+"="+id.def)
+			}
+		}
+	}
+	return env
+}
@@ -0,0 +1,214 @@
+package gitstore
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+type replica struct {
+	t    *testing.T
+	path string
+	repo Repo
+	todo *jsonstore.Repository
+	pub  Committer
+}
+
+func newReplica(t *testing.T) *replica {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	path := filepath.Join(t.TempDir(), "todos.json")
+	repo, err := Init(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Init err=%v", err)
+	}
+	r := &replica{t: t, path: path, repo: repo, todo: jsonstore.NewRepository(path)}
+	r.pub = Committer{Repo: repo, Todos: r.todo}
+	return r
+}
+
+func (r *replica) add(id, title string) {
+	r.t.Helper()
+	tt, _ := todo.NewTitle(title)
+	td, evs, err := todo.NewTodo(todo.NewTodoParams{ID: todo.TodoID(id), Title: tt, Priority: todo.PriorityLow, Now: time.Now()})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if err := r.todo.Create(context.Background(), td); err != nil {
+		r.t.Fatal(err)
+	}
+	r.publish(evs)
+}
+
+func (r *replica) update(id string, change func(todo.Todo) (todo.Todo, []todo.Event)) {
+	r.t.Helper()
+	ctx := context.Background()
+	td, err := r.todo.GetByID(ctx, todo.TodoID(id))
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	td, evs := change(td)
+	if err := r.todo.Update(ctx, td); err != nil {
+		r.t.Fatal(err)
+	}
+	r.publish(evs)
+}
+
+func (r *replica) publish(evs []todo.Event) {
+	r.t.Helper()
+	if err := r.pub.Publish(context.Background(), evs); err != nil {
+		r.t.Fatalf("Publish err=%v", err)
+	}
+}
+
+func (r *replica) subjects() []string {
+	r.t.Helper()
+	log, err := r.repo.Log(context.Background(), 0)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	var out []string
+	for _, e := range log {
+		out = append(out, e.Subject)
+	}
+	return out
+}
+
+func (r *replica) titles() string {
+	r.t.Helper()
+	todos, err := r.repo.Load(context.Background(), "")
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	var out []string
+	for _, td := range todos {
+		out = append(out, td.ID.String()+"="+td.Title.String()+"/"+string(td.Status))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestCommitMessagesAndDiff(t *testing.T) {
+	r := newReplica(t)
+	ctx := context.Background()
+
+	r.add("t1", "Write report")
+	r.update("t1", func(td todo.Todo) (todo.Todo, []todo.Event) {
+		td, evs, _ := td.Complete(time.Now())
+		return td, evs
+	})
+	r.update("t1", func(td todo.Todo) (todo.Todo, []todo.Event) {
+		td.Priority = todo.PriorityHigh // no event, like EditTodo
+		return td, nil
+	})
+	r.publish(nil) // nothing changed: no commit
+
+	want := []string{"edit: Write report", "complete: Write report", "add: Write report"}
+	if got := r.subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("log=%q want=%q", got, want)
+	}
+
+	changes, err := r.repo.DiffRevs(ctx, "HEAD~2", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Kind != "changed" || len(changes[0].Fields) != 2 {
+		t.Fatalf("changes=%+v", changes)
+	}
+	if f := changes[0].Fields; f[0].Field != "status" || f[0].New != "done" || f[1].Field != "priority" || f[1].New != "high" {
+		t.Fatalf("fields=%+v", f)
+	}
+
+	// the very first commit had no store
+	changes, err = r.repo.DiffRevs(ctx, "HEAD~3", "HEAD~2")
+	if err != nil || len(changes) != 1 || changes[0].Kind != "added" {
+		t.Fatalf("changes=%+v err=%v", changes, err)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		changes []Change
+		want    string
+	}{
+		{nil, "update"},
+		{[]Change{{Title: "A", Kind: "removed"}}, "purge: A"},
+		{[]Change{{Title: "A", Kind: "changed"}, {Title: "B", Kind: "added"}}, "edit: A (+1 more)\n\nedit: A\nadd: B"},
+	}
+	for _, tt := range tests {
+		if got := Summary(tt.changes); got != tt.want {
+			t.Fatalf("Summary=%q want=%q", got, tt.want)
+		}
+	}
+}
+
+func TestSyncThroughBareRemote(t *testing.T) {
+	ctx := context.Background()
+	laptop, desktop := newReplica(t), newReplica(t)
+
+	remote := filepath.Join(t.TempDir(), "todos.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v %s", err, out)
+	}
+	for _, r := range []*replica{laptop, desktop} {
+		if err := r.repo.AddRemote(ctx, "origin", remote); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// laptop publishes first; desktop, set up separately, merges into it
+	laptop.add("t1", "Write report")
+	if res, err := laptop.repo.Sync(ctx, "origin"); err != nil || !res.Pushed {
+		t.Fatalf("laptop sync res=%+v err=%v", res, err)
+	}
+	desktop.add("t2", "Buy milk")
+	res, err := desktop.repo.Sync(ctx, "origin")
+	if err != nil || !res.Merged || !res.Pushed || res.Pulled != 1 {
+		t.Fatalf("desktop sync res=%+v err=%v", res, err)
+	}
+
+	// laptop only needs to fast-forward
+	res, err = laptop.repo.Sync(ctx, "origin")
+	if err != nil || res.Merged || res.Pushed || res.Pulled != 1 {
+		t.Fatalf("laptop ff res=%+v err=%v", res, err)
+	}
+	if laptop.titles() != desktop.titles() {
+		t.Fatalf("laptop=%s desktop=%s", laptop.titles(), desktop.titles())
+	}
+
+	// concurrent changes to different todos merge cleanly
+	laptop.update("t2", func(td todo.Todo) (todo.Todo, []todo.Event) {
+		td, evs, _ := td.Complete(time.Now())
+		return td, evs
+	})
+	desktop.update("t1", func(td todo.Todo) (todo.Todo, []todo.Event) {
+		tt, _ := todo.NewTitle("Write quarterly report")
+		td, evs, _ := td.ChangeTitle(tt, time.Now())
+		return td, evs
+	})
+	if _, err := laptop.repo.Sync(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+	res, err = desktop.repo.Sync(ctx, "origin")
+	if err != nil || !res.Merged || len(res.Conflicts) != 0 {
+		t.Fatalf("desktop merge res=%+v err=%v", res, err)
+	}
+	if _, err := laptop.repo.Sync(ctx, "origin"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "t1=Write quarterly report/active t2=Buy milk/done"
+	if got := laptop.titles(); got != want || desktop.titles() != want {
+		t.Fatalf("laptop=%s desktop=%s want=%s", got, desktop.titles(), want)
+	}
+	if s := desktop.subjects(); !strings.HasPrefix(s[0], "sync: merge") {
+		t.Fatalf("desktop log=%q", s)
+	}
+}
@@ -0,0 +1,104 @@
+package gitstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// verbs name each event in commit messages, e.g. "complete: Write report".
+var verbs = map[string]string{
+	"todo.created":       "add",
+	"todo.title_changed": "rename",
+	"todo.completed":     "complete",
+	"todo.reopened":      "reopen",
+	"todo.archived":      "archive",
+	"todo.restored":      "restore",
+	"todo.deleted":       "delete",
+	"todo.moved":         "move",
+	"todo.scheduled":     "schedule",
+	"todo.reminder_set":  "remind",
+	"todo.timer_started": "start",
+	"todo.timer_stopped": "stop",
+	"todo.time_logged":   "track",
+	"todo.estimated":     "estimate",
+}
+
+// Committer commits the store after every published batch of events. It
+// is also called with no events (e.g. after a priority change); then the
+// message is worked out from what changed since the last commit.
+type Committer struct {
+	Repo  Repo
+	Todos ports.TodoRepository // for titles in messages
+}
+
+func (c Committer) Publish(ctx context.Context, evs []todo.Event) error {
+	msg, err := c.message(ctx, evs)
+	if err != nil {
+		return err
+	}
+	_, err = c.Repo.Commit(ctx, msg)
+	return err
+}
+
+func (c Committer) message(ctx context.Context, evs []todo.Event) (string, error) {
+	titles := map[todo.TodoID]string{}
+	if len(evs) > 0 {
+		all, err := c.Todos.List(ctx, ports.ListSpec{IncludeDeleted: true, IncludeUnstarted: true})
+		if err != nil {
+			return "", err
+		}
+		for _, t := range all {
+			titles[t.ID] = t.Title.String()
+		}
+	}
+
+	var lines []string
+	for _, e := range evs {
+		verb, ok := verbs[todo.EventName(e)]
+		if !ok {
+			verb = strings.TrimPrefix(todo.EventName(e), "todo.")
+		}
+		title, ok := titles[todo.EventTodoID(e)]
+		if !ok {
+			title = todo.EventTodoID(e).String()
+		}
+		if line := verb + ": " + title; !slices.Contains(lines, line) {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		changes, err := c.Repo.DiffRevs(ctx, "HEAD", "")
+		if err != nil {
+			return "", err
+		}
+		return Summary(changes), nil
+	}
+	return subject(lines), nil
+}
+
+// Summary describes changes found by diffing, for commits without events.
+func Summary(changes []Change) string {
+	var lines []string
+	for _, ch := range changes {
+		verb := map[string]string{"added": "add", "removed": "purge", "changed": "edit"}[ch.Kind]
+		lines = append(lines, verb+": "+ch.Title)
+	}
+	if len(lines) == 0 {
+		return "update"
+	}
+	return subject(lines)
+}
+
+// subject is the first line plus a count, with every line in the body
+// when there is more than one.
+func subject(lines []string) string {
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	return fmt.Sprintf("%s (+%d more)\n\n%s", lines[0], len(lines)-1, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,168 @@
+package gitstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+// SyncResult describes what Sync did.
+type SyncResult struct {
+	Branch    string
+	Pulled    int  // todos changed locally by the remote's history
+	Pushed    bool // the remote branch was updated
+	Merged    bool // histories had diverged and were merged
+	Conflicts []jsonstore.Conflict
+}
+
+// Sync exchanges history with remote's branch of the same name. Diverged
+// histories are joined with a merge commit whose store file is the replica
+// merge of both sides (see jsonstore.MergeInto), so git never has to
+// resolve the JSON itself.
+func (r Repo) Sync(ctx context.Context, remote string) (SyncResult, error) {
+	var res SyncResult
+
+	if changes, err := r.DiffRevs(ctx, "HEAD", ""); err != nil {
+		return res, err
+	} else if _, err := r.Commit(ctx, Summary(changes)); err != nil {
+		return res, err
+	}
+
+	branch, err := r.git(ctx, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return res, err
+	}
+	res.Branch = strings.TrimSpace(branch)
+
+	if _, err := r.git(ctx, "fetch", "--quiet", remote); err != nil {
+		return res, err
+	}
+	theirs := "refs/remotes/" + remote + "/" + res.Branch
+
+	if _, err := r.git(ctx, "rev-parse", "--verify", "--quiet", theirs); err != nil {
+		return res, r.push(ctx, remote, &res) // new remote or branch
+	}
+	if r.isAncestor(ctx, theirs, "HEAD") {
+		if r.isAncestor(ctx, "HEAD", theirs) {
+			return res, nil // in sync
+		}
+		return res, r.push(ctx, remote, &res)
+	}
+
+	before, err := r.Load(ctx, "HEAD")
+	if err != nil {
+		return res, err
+	}
+
+	if r.isAncestor(ctx, "HEAD", theirs) {
+		if _, err := r.git(ctx, "merge", "--quiet", "--ff-only", theirs); err != nil {
+			return res, err
+		}
+	} else {
+		if err := r.merge(ctx, theirs, &res); err != nil {
+			return res, err
+		}
+		if err := r.push(ctx, remote, &res); err != nil {
+			return res, err
+		}
+	}
+
+	after, err := r.Load(ctx, "HEAD")
+	if err != nil {
+		return res, err
+	}
+	res.Pulled = len(Diff(before, after))
+	return res, nil
+}
+
+func (r Repo) merge(ctx context.Context, theirs string, res *SyncResult) error {
+	other, err := r.Show(ctx, theirs)
+	if err != nil {
+		return err
+	}
+
+	// Let git merge everything else (config files); the store may come
+	// out conflicted, which is fine: it is replaced below. Stores set up
+	// separately on two machines have unrelated histories.
+	_, mergeErr := r.git(ctx, "merge", "--quiet", "--no-ff", "--no-commit", "--allow-unrelated-histories", theirs)
+	out, err := r.git(ctx, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return err
+	}
+	for _, f := range strings.Fields(out) {
+		if f != r.File {
+			_, _ = r.git(ctx, "merge", "--abort")
+			return fmt.Errorf("gitstore: merge conflict in %s; resolve it with git in %s", f, r.Dir)
+		}
+	}
+	if mergeErr != nil && strings.TrimSpace(out) == "" {
+		return mergeErr
+	}
+
+	ours, err := r.Show(ctx, "HEAD")
+	if err != nil {
+		_, _ = r.git(ctx, "merge", "--abort")
+		return err
+	}
+	switch {
+	case ours == nil:
+		_, err = r.git(ctx, "checkout", theirs, "--", r.File)
+	case other == nil:
+		_, err = r.git(ctx, "checkout", "HEAD", "--", r.File)
+	default:
+		if _, err = r.git(ctx, "checkout", "HEAD", "--", r.File); err == nil {
+			var rep jsonstore.SyncReport
+			rep, err = jsonstore.MergeInto(filepath.Join(r.Dir, r.File), other)
+			res.Conflicts = rep.Conflicts
+		}
+	}
+	if err != nil {
+		_, _ = r.git(ctx, "merge", "--abort")
+		return err
+	}
+
+	msg := "sync: merge " + theirs
+	if n := len(res.Conflicts); n > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s (%d conflicts)\n\n", msg, n)
+		for _, c := range res.Conflicts {
+			fmt.Fprintf(&b, "%s %q %s: kept %s, dropped %s\n", c.ID, c.Title, c.Field, c.Kept, c.Lost)
+		}
+		msg = b.String()
+	}
+	if _, err := r.git(ctx, "add", "--", r.File); err != nil {
+		return err
+	}
+	if _, err := r.git(ctx, "commit", "--quiet", "--no-verify", "-m", msg); err != nil {
+		return err
+	}
+	res.Merged = true
+	return nil
+}
+
+func (r Repo) push(ctx context.Context, remote string, res *SyncResult) error {
+	if _, err := r.git(ctx, "push", "--quiet", "--set-upstream", remote, "HEAD:refs/heads/"+res.Branch); err != nil {
+		return err
+	}
+	res.Pushed = true
+	return nil
+}
+
+func (r Repo) isAncestor(ctx context.Context, a, b string) bool {
+	_, err := r.git(ctx, "merge-base", "--is-ancestor", a, b)
+	return err == nil
+}
+
+// AddRemote registers url as remote name, or updates its URL.
+func (r Repo) AddRemote(ctx context.Context, name, url string) error {
+	if _, err := r.git(ctx, "remote", "add", name, url); err != nil {
+		if _, err2 := r.git(ctx, "remote", "set-url", name, url); err2 != nil {
+			return errors.Join(err, err2)
+		}
+	}
+	return nil
+}
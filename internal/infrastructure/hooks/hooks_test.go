@@ -0,0 +1,167 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+}
+
+func mkTodo(t *testing.T, title string) todo.Todo {
+	t.Helper()
+	tt, _ := todo.NewTitle(title)
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID:       todo.TodoID("t1"),
+		Title:    tt,
+		Priority: todo.PriorityLow,
+		Tags:     todo.NewTags(nil),
+		Now:      time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("NewTodo err=%v", err)
+	}
+	return td
+}
+
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks are not supported on windows")
+	}
+}
+
+func TestPublisher_RunsHookWithPayload(t *testing.T) {
+	skipOnWindows(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+	writeHook(t, dir, "on-complete", "cat > "+out+"\n")
+
+	repo := jsonstore.NewRepository(filepath.Join(dir, "todos.json"))
+	td := mkTodo(t, "Write report")
+	if err := repo.Create(ctx, td); err != nil {
+		t.Fatalf("Create err=%v", err)
+	}
+
+	p := Publisher{Runner: Runner{Dir: dir}, Repo: repo}
+	ev := todo.TodoCompleted{ID: td.ID, OccurredAt: td.CreatedAt}
+	if err := p.Publish(ctx, []todo.Event{ev}); err != nil {
+		t.Fatalf("Publish err=%v", err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if !strings.Contains(string(b), `"event":"todo.completed"`) || !strings.Contains(string(b), `"title":"Write report"`) {
+		t.Fatalf("payload=%s", b)
+	}
+}
+
+func TestPublisher_DeleteSendsLastState(t *testing.T) {
+	skipOnWindows(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+	writeHook(t, dir, "on-delete", "cat > "+out+"\n")
+
+	// the todo is no longer in the store
+	repo := jsonstore.NewRepository(filepath.Join(dir, "todos.json"))
+	td, evs, err := mkTodo(t, "Write report").SoftDelete(time.Now())
+	if err != nil {
+		t.Fatalf("SoftDelete err=%v", err)
+	}
+	if err := (Publisher{Runner: Runner{Dir: dir}, Repo: repo}).Publish(ctx, evs); err != nil {
+		t.Fatalf("Publish err=%v", err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if !strings.Contains(string(b), `"title":"Write report"`) || !strings.Contains(string(b), `"id":"`+td.ID.String()+`"`) {
+		t.Fatalf("payload=%s want the deleted todo", b)
+	}
+}
+
+func TestPublisher_FailingHookIsNotAnError(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHook(t, dir, "on-create", "exit 3\n")
+
+	repo := jsonstore.NewRepository(filepath.Join(dir, "todos.json"))
+	p := Publisher{Runner: Runner{Dir: dir}, Repo: repo}
+	ev := todo.TodoCreated{ID: "missing", OccurredAt: time.Now()}
+	if err := p.Publish(context.Background(), []todo.Event{ev}); err != nil {
+		t.Fatalf("Publish err=%v want nil", err)
+	}
+}
+
+func TestPreAdd_Veto(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-add", "echo 'no todos on weekends' >&2\nexit 1\n")
+
+	_, err := PreAdd{Runner: Runner{Dir: dir}}.BeforeAdd(context.Background(), mkTodo(t, "X"))
+	if !errors.Is(err, appErr.ErrVetoed) {
+		t.Fatalf("err=%v want ErrVetoed", err)
+	}
+	if !strings.Contains(err.Error(), "no todos on weekends") {
+		t.Fatalf("err=%v want reason", err)
+	}
+}
+
+func TestPreAdd_Modify(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-add", `echo '{"title":"Renamed","priority":"high","tags":["Hooked"]}'`+"\n")
+
+	got, err := PreAdd{Runner: Runner{Dir: dir}}.BeforeAdd(context.Background(), mkTodo(t, "X"))
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if got.Title != "Renamed" || got.Priority != todo.PriorityHigh || !got.Tags.Contains("hooked") {
+		t.Fatalf("got=%+v", got)
+	}
+	if got.ID != "t1" {
+		t.Fatalf("id=%s want t1 (hooks must not change identity)", got.ID)
+	}
+}
+
+func TestPreAdd_TimeoutIsIgnored(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-add", "sleep 5\n")
+
+	in := mkTodo(t, "X")
+	got, err := PreAdd{Runner: Runner{Dir: dir, Timeout: 50 * time.Millisecond}}.BeforeAdd(context.Background(), in)
+	if err != nil {
+		t.Fatalf("err=%v want nil", err)
+	}
+	if got.Title != in.Title {
+		t.Fatalf("got=%+v", got)
+	}
+}
+
+func TestHookName(t *testing.T) {
+	if got := HookName("todo.completed"); got != "on-complete" {
+		t.Fatalf("got=%s", got)
+	}
+	if got := HookName("todo.something_new"); got != "on-something-new" {
+		t.Fatalf("got=%s", got)
+	}
+}
@@ -0,0 +1,39 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+)
+
+// RemindHook is the script Notifier runs for each reminder.
+const RemindHook = "on-remind"
+
+// Notifier runs the on-remind hook for reminders, with the usual payload
+// (event "reminder", occurredAt the reminder's time) on stdin.
+type Notifier struct {
+	Runner Runner
+}
+
+var _ ports.Notifier = Notifier{}
+
+func (n Notifier) Notify(ctx context.Context, r ports.Reminder) error {
+	dto := queries.ToDTO(r.Todo)
+	b, err := json.Marshal(Payload{Event: "reminder", OccurredAt: r.At, Todo: &dto})
+	if err != nil {
+		return err
+	}
+	_, ok, err := n.Runner.Run(ctx, RemindHook, b,
+		"GOTODO_EVENT=reminder",
+		"GOTODO_TODO_ID="+r.Todo.ID.String(),
+		"GOTODO_LATE="+strconv.FormatBool(r.Late),
+	)
+	if !ok {
+		return fmt.Errorf("hooks: no executable %s in %s", RemindHook, n.Runner.Dir)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+const preAddHook = "pre-add"
+
+// PreAdd runs the "pre-add" hook before a todo is stored.
+//
+// Like a git pre-commit hook: a non-zero exit vetoes the add (stderr is the
+// reason), and a todo JSON object on stdout replaces title, priority, tags,
+// due date, scheduled date, reminder and project. A hook that times out or can't
+// start is logged and ignored.
+type PreAdd struct {
+	Runner Runner
+}
+
+var _ ports.PreAddHook = PreAdd{}
+
+func (h PreAdd) BeforeAdd(ctx context.Context, t todo.Todo) (todo.Todo, error) {
+	dto := queries.ToDTO(t)
+	b, err := json.Marshal(Payload{Event: "todo.pre_add", OccurredAt: t.CreatedAt, Todo: &dto})
+	if err != nil {
+		return t, nil
+	}
+
+	out, ok, err := h.Runner.Run(ctx, preAddHook, b, "GOTODO_EVENT=todo.pre_add")
+	if !ok {
+		return t, nil
+	}
+	if err != nil {
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Stderr == "" {
+				return t, appErr.ErrVetoed
+			}
+			return t, fmt.Errorf("%w: %s", appErr.ErrVetoed, exitErr.Stderr)
+		}
+		h.Runner.logf("hook %s: %v", preAddHook, err)
+		return t, nil
+	}
+
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return t, nil
+	}
+
+	var mod queries.TodoDTO
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return t, fmt.Errorf("%w: %s returned invalid JSON", appErr.ErrValidation, preAddHook)
+	}
+	return applyDTO(t, mod)
+}
+
+// applyDTO copies the user-editable fields of mod onto t, re-validating
+// them through the domain constructors. Empty fields are left untouched.
+func applyDTO(t todo.Todo, mod queries.TodoDTO) (todo.Todo, error) {
+	if mod.Title != "" {
+		title, err := todo.NewTitle(mod.Title)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.Title = title
+	}
+	if mod.Priority != "" {
+		p, err := todo.NewPriority(mod.Priority)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.Priority = p
+	}
+	if mod.Tags != nil {
+		t.Tags = todo.NewTags(mod.Tags)
+	}
+	if mod.DueDate != nil {
+		d, err := todo.ParseDueDate(*mod.DueDate)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.DueDate = &d
+	}
+	if mod.Scheduled != nil {
+		d, err := todo.ParseDueDate(*mod.Scheduled)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.Scheduled = &d
+	}
+	if mod.RemindAt != nil {
+		d, err := todo.ParseDueDate(*mod.RemindAt)
+		if err == nil && !d.HasTime() {
+			err = todo.ErrInvalidReminder
+		}
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.RemindAt = &d
+	}
+	if mod.Estimate != "" {
+		e, err := todo.ParseEstimate(mod.Estimate)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.Estimate = &e
+	}
+	if mod.Project != "" {
+		p, err := todo.NewProject(mod.Project)
+		if err != nil {
+			return t, fmt.Errorf("%w: %s: %v", appErr.ErrValidation, preAddHook, err)
+		}
+		t.Project = p
+	}
+	return t, nil
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Payload is what every hook receives on stdin.
+type Payload struct {
+	Event      string           `json:"event"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Todo       *queries.TodoDTO `json:"todo"`
+}
+
+// Publisher runs the matching on-<event> hook for every published event.
+// Hook failures are logged and never returned, so a broken script can't
+// break the command that triggered it.
+type Publisher struct {
+	Runner Runner
+	Repo   ports.TodoRepository
+}
+
+func (p Publisher) Publish(ctx context.Context, evs []todo.Event) error {
+	for _, e := range evs {
+		name := todo.EventName(e)
+		pl := Payload{Event: name, OccurredAt: todo.EventTime(e)}
+
+		if d, ok := e.(todo.TodoDeleted); ok {
+			dto := queries.ToDTO(d.Todo) // it may be gone from the store
+			pl.Todo = &dto
+		} else if td, err := p.Repo.GetByID(ctx, todo.EventTodoID(e)); err == nil {
+			dto := queries.ToDTO(td)
+			pl.Todo = &dto
+		}
+
+		b, err := json.Marshal(pl)
+		if err != nil {
+			p.Runner.logf("hook payload for %s: %v", name, err)
+			continue
+		}
+
+		_, _, err = p.Runner.Run(ctx, HookName(name), b,
+			"GOTODO_EVENT="+name,
+			"GOTODO_TODO_ID="+todo.EventTodoID(e).String(),
+		)
+		if err != nil {
+			p.Runner.logf("hook %s: %v", HookName(name), err)
+		}
+	}
+	return nil
+}
+
+// hookNames maps event names to the verb-style script names users expect.
+var hookNames = map[string]string{
+	"todo.created":       "on-create",
+	"todo.title_changed": "on-edit",
+	"todo.completed":     "on-complete",
+	"todo.reopened":      "on-reopen",
+	"todo.archived":      "on-archive",
+	"todo.restored":      "on-restore",
+	"todo.deleted":       "on-delete",
+	"todo.moved":         "on-move",
+	"todo.scheduled":     "on-schedule",
+	"todo.reminder_set":  "on-remind-set",
+	"todo.timer_started": "on-start",
+	"todo.timer_stopped": "on-stop",
+	"todo.time_logged":   "on-track",
+	"todo.estimated":     "on-estimate",
+}
+
+// HookName returns the script name for an event, e.g. "on-complete".
+// Unmapped events fall back to "on-" + the event suffix with dashes.
+func HookName(event string) string {
+	if n, ok := hookNames[event]; ok {
+		return n
+	}
+	s := strings.TrimPrefix(event, "todo.")
+	return "on-" + strings.ReplaceAll(s, "_", "-")
+}
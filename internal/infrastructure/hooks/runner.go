@@ -0,0 +1,102 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const DefaultTimeout = 5 * time.Second
+
+// ExitError is returned when a hook exits non-zero.
+type ExitError struct {
+	Hook   string
+	Code   int
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("hooks: %s exited with status %d", e.Hook, e.Code)
+	}
+	return fmt.Sprintf("hooks: %s exited with status %d: %s", e.Hook, e.Code, e.Stderr)
+}
+
+// Runner executes user scripts from Dir (the hooks dir next to the store).
+// A hook is any executable file named after the event, e.g. "on-complete".
+type Runner struct {
+	Dir     string
+	Timeout time.Duration
+	Logger  *log.Logger
+}
+
+// Run feeds payload on stdin to the named hook and returns its stdout.
+// ok is false when no such hook is installed.
+func (r Runner) Run(ctx context.Context, name string, payload []byte, env ...string) (out []byte, ok bool, err error) {
+	path, ok := r.lookup(name)
+	if !ok {
+		return nil, false, nil
+	}
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = r.Dir
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), env...)
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, true, fmt.Errorf("hooks: %s timed out after %s", name, timeout)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout.Bytes(), true, &ExitError{
+				Hook:   name,
+				Code:   exitErr.ExitCode(),
+				Stderr: strings.TrimSpace(stderr.String()),
+			}
+		}
+		return nil, true, err
+	}
+
+	return stdout.Bytes(), true, nil
+}
+
+func (r Runner) lookup(name string) (string, bool) {
+	if r.Dir == "" {
+		return "", false
+	}
+	path := filepath.Join(r.Dir, name)
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return "", false
+	}
+	if fi.Mode()&0o111 == 0 {
+		r.logf("hook %s is not executable, skipping", path)
+		return "", false
+	}
+	return path, true
+}
+
+func (r Runner) logf(format string, args ...any) {
+	if r.Logger != nil {
+		r.Logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,449 @@
+package jsonstore
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Replication: every file is a replica with its own ID and Lamport clock.
+// Each write stamps the fields it changed with (clock, replica), and two
+// files merge field by field, the later stamp winning. Ties (e.g. rows
+// from before stamping existed) go to the larger encoded value, so the
+// merge is a join: commutative, associative and idempotent, and replicas
+// that have seen the same writes hold the same todos however they synced.
+//
+// Deletion is a tombstone: a set DeletedAt always beats an unset one, so a
+// todo deleted on one replica stays deleted everywhere. A hard delete
+// leaves its ID in Purged, which drops the row from every replica it
+// merges with. Time entries are only ever added or closed, so they merge
+// as a set keyed by Start.
+
+// Stamp is the Lamport time of a field's last write.
+type Stamp struct {
+	Time    uint64 `json:"t"`
+	Replica string `json:"r"`
+}
+
+func (s Stamp) compare(o Stamp) int {
+	return cmp.Or(cmp.Compare(s.Time, o.Time), cmp.Compare(s.Replica, o.Replica))
+}
+
+// field is one independently merged part of a row. Fields that must stay
+// consistent with each other (status and its timestamps) merge as one.
+type field struct {
+	name string
+	val  func(todoRow) any
+	set  func(dst *todoRow, src todoRow)
+}
+
+var fields = []field{
+	{"title", func(r todoRow) any { return r.Title }, func(d *todoRow, s todoRow) { d.Title = s.Title }},
+	{"status", func(r todoRow) any { return []any{r.Status, r.CompletedAt, r.ArchivedAt} }, func(d *todoRow, s todoRow) {
+		d.Status, d.CompletedAt, d.ArchivedAt = s.Status, s.CompletedAt, s.ArchivedAt
+	}},
+	{"priority", func(r todoRow) any { return r.Priority }, func(d *todoRow, s todoRow) { d.Priority = s.Priority }},
+	{"tags", func(r todoRow) any { return r.Tags }, func(d *todoRow, s todoRow) { d.Tags = slices.Clone(s.Tags) }},
+	{"dueDate", func(r todoRow) any { return r.DueDate }, func(d *todoRow, s todoRow) { d.DueDate = s.DueDate }},
+	{"parentId", func(r todoRow) any { return r.ParentID }, func(d *todoRow, s todoRow) { d.ParentID = s.ParentID }},
+	{"project", func(r todoRow) any { return r.Project }, func(d *todoRow, s todoRow) { d.Project = s.Project }},
+	{"scheduled", func(r todoRow) any { return r.Scheduled }, func(d *todoRow, s todoRow) { d.Scheduled = s.Scheduled }},
+	{"remindAt", func(r todoRow) any { return r.RemindAt }, func(d *todoRow, s todoRow) { d.RemindAt = s.RemindAt }},
+	{"estimate", func(r todoRow) any { return r.Estimate }, func(d *todoRow, s todoRow) { d.Estimate = s.Estimate }},
+	{"timeEntries", func(r todoRow) any { return r.TimeEntries }, func(d *todoRow, s todoRow) { d.TimeEntries = slices.Clone(s.TimeEntries) }},
+	{"meta", func(r todoRow) any { return r.Meta }, func(d *todoRow, s todoRow) { d.Meta = maps.Clone(s.Meta) }},
+	{"deletedAt", func(r todoRow) any { return r.DeletedAt }, func(d *todoRow, s todoRow) { d.DeletedAt = s.DeletedAt }},
+}
+
+func (f field) encode(r todoRow) []byte {
+	b, _ := json.Marshal(f.val(r))
+	return b
+}
+
+func newReplicaID() string {
+	var b [6]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// tick advances the clock for a local write.
+func (fs *fileSchema) tick() Stamp {
+	fs.Clock++
+	if fs.Seen == nil {
+		fs.Seen = map[string]uint64{}
+	}
+	fs.Seen[fs.Replica] = fs.Clock
+	return Stamp{Time: fs.Clock, Replica: fs.Replica}
+}
+
+// stamp records a write of row over old (nil for a new todo): changed
+// fields get a fresh stamp, unchanged ones keep theirs.
+func (fs *fileSchema) stamp(old *todoRow, row *todoRow) {
+	row.Clocks = map[string]Stamp{}
+	if old != nil {
+		row.Clocks = maps.Clone(old.Clocks)
+		if row.Clocks == nil {
+			row.Clocks = map[string]Stamp{}
+		}
+	}
+	var now Stamp
+	for _, f := range fields {
+		if old != nil && bytes.Equal(f.encode(*old), f.encode(*row)) {
+			continue
+		}
+		if now == (Stamp{}) {
+			now = fs.tick()
+		}
+		row.Clocks[f.name] = now
+	}
+}
+
+// Conflict is a field both replicas changed without seeing the other's
+// change. The merge still resolves it; the report says what was dropped.
+type Conflict struct {
+	ID    string `json:"id"`
+	Title string `json:"title"` // after the merge
+	Field string `json:"field"`
+
+	Kept json.RawMessage `json:"kept"`
+	Lost json.RawMessage `json:"lost"`
+
+	KeptReplica string `json:"keptReplica"`
+	LostReplica string `json:"lostReplica"`
+}
+
+// merge joins two replicas. Its clock and Seen cover both inputs.
+func merge(a, b fileSchema) (fileSchema, []Conflict) {
+	out := fileSchema{
+		Version: schemaVersion,
+		Clock:   max(a.Clock, b.Clock),
+		Seen:    mergeSeen(a, b),
+	}
+	for _, ps := range []map[string]projectRow{a.Projects, b.Projects} {
+		for name, p := range ps {
+			if cur, ok := out.Projects[name]; !ok || p.Stamp.compare(cur.Stamp) > 0 || p.Stamp == cur.Stamp && p.Removed {
+				if out.Projects == nil {
+					out.Projects = map[string]projectRow{}
+				}
+				out.Projects[name] = p
+			}
+		}
+	}
+
+	for _, ps := range []map[string]Stamp{a.Purged, b.Purged} {
+		for id, s := range ps {
+			if cur, ok := out.Purged[id]; !ok || s.compare(cur) > 0 {
+				if out.Purged == nil {
+					out.Purged = map[string]Stamp{}
+				}
+				out.Purged[id] = s
+			}
+		}
+	}
+
+	rowsB := map[string]todoRow{}
+	for _, r := range b.Todos {
+		rowsB[r.ID] = r
+	}
+	seen := map[string]bool{}
+	for id := range out.Purged {
+		seen[id] = true
+	}
+
+	var conflicts []Conflict
+	for _, ra := range a.Todos {
+		if seen[ra.ID] {
+			continue
+		}
+		seen[ra.ID] = true
+		rb, ok := rowsB[ra.ID]
+		if !ok {
+			out.Todos = append(out.Todos, cloneRow(ra))
+			continue
+		}
+		row, cs := mergeRow(ra, rb, seenOf(a), seenOf(b))
+		out.Todos = append(out.Todos, row)
+		conflicts = append(conflicts, cs...)
+	}
+	for _, rb := range b.Todos {
+		if !seen[rb.ID] {
+			out.Todos = append(out.Todos, cloneRow(rb))
+		}
+	}
+
+	slices.SortFunc(out.Todos, func(x, y todoRow) int {
+		return cmp.Or(x.CreatedAt.Compare(y.CreatedAt), cmp.Compare(x.ID, y.ID))
+	})
+	slices.SortFunc(conflicts, func(x, y Conflict) int {
+		return cmp.Or(cmp.Compare(x.ID, y.ID), cmp.Compare(x.Field, y.Field))
+	})
+	return out, conflicts
+}
+
+func mergeRow(a, b todoRow, seenA, seenB map[string]uint64) (todoRow, []Conflict) {
+	out := cloneRow(a)
+	out.Clocks = nil
+	out.CreatedAt = minTime(a.CreatedAt, b.CreatedAt)
+	out.UpdatedAt = maxTime(a.UpdatedAt, b.UpdatedAt)
+
+	var conflicts []Conflict
+	for _, f := range fields {
+		sa, sb := a.Clocks[f.name], b.Clocks[f.name]
+		if f.name == "timeEntries" {
+			out.TimeEntries = unionEntries(a.TimeEntries, b.TimeEntries)
+			if sb.compare(sa) > 0 {
+				sa = sb
+			}
+			if sa != (Stamp{}) {
+				if out.Clocks == nil {
+					out.Clocks = map[string]Stamp{}
+				}
+				out.Clocks[f.name] = sa
+			}
+			continue
+		}
+		va, vb := f.encode(a), f.encode(b)
+
+		aWins := true
+		if f.name == "deletedAt" && (a.DeletedAt == nil) != (b.DeletedAt == nil) {
+			aWins = a.DeletedAt != nil // tombstones win
+		} else if c := sa.compare(sb); c != 0 {
+			aWins = c > 0
+		} else {
+			aWins = bytes.Compare(va, vb) >= 0
+		}
+
+		win, winSeen, ws, ls, wv, lv := a, seenA, sa, sb, va, vb
+		if !aWins {
+			win, winSeen, ws, ls, wv, lv = b, seenB, sb, sa, vb, va
+		}
+		f.set(&out, win)
+		if ws != (Stamp{}) {
+			if out.Clocks == nil {
+				out.Clocks = map[string]Stamp{}
+			}
+			out.Clocks[f.name] = ws
+		}
+
+		// the losing write is a conflict if the winner never saw it
+		if !bytes.Equal(wv, lv) && ls != (Stamp{}) && ls.Time > winSeen[ls.Replica] {
+			conflicts = append(conflicts, Conflict{
+				ID: a.ID, Field: f.name, Kept: wv, Lost: lv,
+				KeptReplica: ws.Replica, LostReplica: ls.Replica,
+			})
+		}
+	}
+	for i := range conflicts {
+		conflicts[i].Title = out.Title
+	}
+	return out, conflicts
+}
+
+// unionEntries keeps the entries of both sides, oldest first. An entry
+// closed on one side and still running on the other is closed; two
+// different ends (edited by hand) resolve to the later one.
+func unionEntries(a, b []timeEntryRow) []timeEntryRow {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	out := slices.Clone(a)
+	for _, e := range b {
+		i := slices.IndexFunc(out, func(x timeEntryRow) bool { return x.Start.Equal(e.Start) })
+		switch {
+		case i < 0:
+			out = append(out, e)
+		case e.End != nil && (out[i].End == nil || e.End.After(*out[i].End)):
+			out[i] = e
+		}
+	}
+	slices.SortStableFunc(out, func(x, y timeEntryRow) int { return x.Start.Compare(y.Start) })
+	return out
+}
+
+// seenOf is a copy of fs.Seen; tick keeps the replica's own entry current.
+func seenOf(fs fileSchema) map[string]uint64 {
+	s := maps.Clone(fs.Seen)
+	if s == nil {
+		s = map[string]uint64{}
+	}
+	return s
+}
+
+func mergeSeen(a, b fileSchema) map[string]uint64 {
+	out := seenOf(a)
+	for r, t := range seenOf(b) {
+		out[r] = max(out[r], t)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func cloneRow(r todoRow) todoRow {
+	r.Tags = slices.Clone(r.Tags)
+	r.Meta = maps.Clone(r.Meta)
+	r.Clocks = maps.Clone(r.Clocks)
+	return r
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// SyncReport describes what SyncFiles changed.
+type SyncReport struct {
+	Pulled    int // todos changed or added locally
+	Pushed    int // todos changed or added in the other file
+	Conflicts []Conflict
+
+	// Rekeyed is set when both files claimed the same replica ID (one
+	// directory was copied from the other); the other file got a new one.
+	Rekeyed bool
+}
+
+// SyncFiles merges the stores at localPath and otherPath and writes the
+// result to both. Either file may be missing. Both are locked for the
+// duration.
+func SyncFiles(localPath, otherPath string) (SyncReport, error) {
+	var rep SyncReport
+
+	// lock in a fixed order so two opposite syncs can't deadlock
+	paths := []string{localPath, otherPath}
+	slices.Sort(paths)
+	for _, p := range paths {
+		l, err := acquireLock(p)
+		if err != nil {
+			return rep, err
+		}
+		defer func() { _ = l.release() }()
+	}
+
+	local, other := New(localPath), New(otherPath)
+	a, err := local.Load()
+	if err != nil {
+		return rep, err
+	}
+	b, err := other.Load()
+	if err != nil {
+		return rep, err
+	}
+	if a.Replica, err = local.ReplicaID(); err != nil {
+		return rep, err
+	}
+	if b.Replica, err = other.ReplicaID(); err != nil {
+		return rep, err
+	}
+	if a.Replica == b.Replica {
+		// the whole data directory was copied, sidecar and all
+		rep.Rekeyed = true
+		if _, err := other.newReplicaID(); err != nil {
+			return rep, err
+		}
+	}
+
+	merged, conflicts := merge(a, b)
+	rep.Conflicts = conflicts
+
+	var errs []error
+	for _, side := range []struct {
+		store Store
+		prev  fileSchema
+		n     *int
+	}{{local, a, &rep.Pulled}, {other, b, &rep.Pushed}} {
+		*side.n = changedRows(side.prev, merged)
+		unchanged := *side.n == 0 && len(merged.Todos) == len(side.prev.Todos) &&
+			side.prev.Clock == merged.Clock && maps.Equal(side.prev.Seen, merged.Seen) &&
+			maps.Equal(side.prev.Projects, merged.Projects) && maps.Equal(side.prev.Purged, merged.Purged)
+		if !unchanged {
+			errs = append(errs, side.store.Save(merged))
+		}
+	}
+	return rep, errors.Join(errs...)
+}
+
+// MergeInto merges another copy of the store, given as file contents
+// (e.g. from another git branch), into the store at path. Pushed is
+// always zero: the other copy is not written.
+func MergeInto(path string, other []byte) (SyncReport, error) {
+	var rep SyncReport
+
+	var b fileSchema
+	if err := json.Unmarshal(other, &b); err != nil || b.Version != schemaVersion {
+		return rep, ErrCorruptData
+	}
+
+	l, err := acquireLock(path)
+	if err != nil {
+		return rep, err
+	}
+	defer func() { _ = l.release() }()
+
+	store := New(path)
+	a, err := store.Load()
+	if err != nil {
+		return rep, err
+	}
+
+	merged, conflicts := merge(a, b)
+	rep.Conflicts = conflicts
+	rep.Pulled = changedRows(a, merged)
+	return rep, store.Save(merged)
+}
+
+// Decode parses the contents of a store file.
+func Decode(b []byte) ([]todo.Todo, error) {
+	var fs fileSchema
+	if err := json.Unmarshal(b, &fs); err != nil || fs.Version != schemaVersion {
+		return nil, ErrCorruptData
+	}
+	out := make([]todo.Todo, 0, len(fs.Todos))
+	for _, row := range fs.Todos {
+		td, err := fromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, td)
+	}
+	return out, nil
+}
+
+// changedRows counts rows of next that are new or differ from prev, and
+// rows of prev that next purged.
+func changedRows(prev, next fileSchema) int {
+	before := map[string][]byte{}
+	for _, r := range prev.Todos {
+		before[r.ID], _ = json.Marshal(r)
+	}
+	n := 0
+	for _, r := range next.Todos {
+		b, _ := json.Marshal(r)
+		if old, ok := before[r.ID]; !ok || !bytes.Equal(old, b) {
+			n++
+		}
+	}
+	for id := range before {
+		if _, ok := next.Purged[id]; ok {
+			n++
+		}
+	}
+	return n
+}
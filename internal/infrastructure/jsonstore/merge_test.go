@@ -0,0 +1,371 @@
+package jsonstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// replica is a random store for property tests. IDs, values and stamps
+// come from small pools so that replicas overlap and collide often.
+type replica fileSchema
+
+func (replica) Generate(r *rand.Rand, size int) reflect.Value {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	pick := func(xs ...string) string { return xs[r.Intn(len(xs))] }
+	stamp := func() Stamp {
+		if r.Intn(5) == 0 {
+			return Stamp{} // unstamped, as in files from before replicas
+		}
+		return Stamp{Time: uint64(r.Intn(6)), Replica: pick("r1", "r2", "r3")}
+	}
+	ptime := func() *time.Time {
+		if r.Intn(2) == 0 {
+			return nil
+		}
+		t := base.Add(time.Duration(r.Intn(4)) * time.Hour)
+		return &t
+	}
+
+	fs := replica{Version: schemaVersion, Replica: pick("r1", "r2", "r3"), Clock: uint64(r.Intn(6))}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if r.Intn(3) == 0 {
+			continue
+		}
+		row := todoRow{
+			ID:          id,
+			Title:       pick("x", "y", "z"),
+			Status:      pick("active", "done", "archived"),
+			Priority:    pick("low", "high"),
+			Tags:        [][]string{nil, {"home"}, {"home", "work"}}[r.Intn(3)],
+			ParentID:    pick("", "a"),
+			Project:     pick("", "work"),
+			CreatedAt:   base.Add(time.Duration(r.Intn(3)) * time.Minute),
+			UpdatedAt:   base.Add(time.Duration(r.Intn(3)) * time.Hour),
+			CompletedAt: ptime(),
+			DeletedAt:   ptime(),
+			Clocks:      map[string]Stamp{},
+		}
+		if r.Intn(2) == 0 {
+			d := pick("2026-10-20", "2026-11-01")
+			row.DueDate = &d
+		}
+		if r.Intn(2) == 0 {
+			row.Meta = map[string]string{"src.k": pick("1", "2")}
+		}
+		for h := range 3 {
+			if r.Intn(2) == 0 {
+				start := base.Add(time.Duration(h) * time.Hour)
+				e := timeEntryRow{Start: start}
+				if r.Intn(3) > 0 {
+					end := start.Add(time.Duration(15*(1+r.Intn(2))) * time.Minute)
+					e.End = &end
+				}
+				row.TimeEntries = append(row.TimeEntries, e)
+			}
+		}
+		for _, f := range fields {
+			if s := stamp(); s != (Stamp{}) {
+				row.Clocks[f.name] = s
+			}
+		}
+		fs.Todos = append(fs.Todos, row)
+	}
+	for _, name := range []string{"home", "work"} {
+		if s := stamp(); s != (Stamp{}) {
+			if fs.Projects == nil {
+				fs.Projects = map[string]projectRow{}
+			}
+			fs.Projects[name] = projectRow{Removed: r.Intn(2) == 0, Stamp: s}
+		}
+	}
+	if r.Intn(4) == 0 {
+		fs.Purged = map[string]Stamp{pick("b", "d"): stamp()}
+	}
+	return reflect.ValueOf(fs)
+}
+
+func todosJSON(fs fileSchema) string {
+	b, _ := json.Marshal(struct {
+		Todos    []todoRow
+		Projects map[string]projectRow
+		Purged   map[string]Stamp
+	}{fs.Todos, fs.Projects, fs.Purged})
+	return string(b)
+}
+
+func mergeTodos(a, b fileSchema) fileSchema {
+	m, _ := merge(a, b)
+	return m
+}
+
+func TestMerge_Commutative(t *testing.T) {
+	f := func(a, b replica) bool {
+		return todosJSON(mergeTodos(fileSchema(a), fileSchema(b))) == todosJSON(mergeTodos(fileSchema(b), fileSchema(a)))
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMerge_Associative(t *testing.T) {
+	f := func(a, b, c replica) bool {
+		x, y, z := fileSchema(a), fileSchema(b), fileSchema(c)
+		return todosJSON(mergeTodos(mergeTodos(x, y), z)) == todosJSON(mergeTodos(x, mergeTodos(y, z)))
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMerge_Idempotent(t *testing.T) {
+	f := func(a, b replica) bool {
+		x, y := fileSchema(a), fileSchema(b)
+		once := mergeTodos(x, y)
+		return todosJSON(mergeTodos(x, x)) == todosJSON(mergeTodos(x, fileSchema{})) &&
+			todosJSON(mergeTodos(once, y)) == todosJSON(once) &&
+			todosJSON(mergeTodos(once, once)) == todosJSON(once)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMerge_KeepsTimeEntriesOfBothReplicas(t *testing.T) {
+	at := func(h, m int) *time.Time {
+		x := time.Date(2026, 10, 19, h, m, 0, 0, time.UTC)
+		return &x
+	}
+	row := func(replica string, clock uint64, entries ...timeEntryRow) fileSchema {
+		return fileSchema{Version: schemaVersion, Replica: replica, Clock: clock, Todos: []todoRow{{
+			ID: "a", Title: "x", Status: "active", Priority: "low", TimeEntries: entries,
+			Clocks: map[string]Stamp{"timeEntries": {Time: clock, Replica: replica}},
+		}}}
+	}
+	// both logged time since they last synced; r2 also left a timer
+	// running that r1 has since stopped
+	a := row("r1", 3, timeEntryRow{Start: *at(9, 0), End: at(9, 30)}, timeEntryRow{Start: *at(11, 0), End: at(12, 0)})
+	b := row("r2", 4, timeEntryRow{Start: *at(9, 0)}, timeEntryRow{Start: *at(10, 0), End: at(10, 15)})
+
+	merged, conflicts := merge(a, b)
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts=%+v", conflicts)
+	}
+	td, err := fromRow(merged.Todos[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range td.TimeEntries {
+		got = append(got, e.Start.Format("15:04")+"-"+e.End.Format("15:04"))
+	}
+	if want := "[09:00-09:30 10:00-10:15 11:00-12:00]"; fmt.Sprint(got) != want {
+		t.Fatalf("entries=%v want %s", got, want)
+	}
+
+	// a timer still running before logged time is read as stopped there
+	td, _ = fromRow(b.Todos[0])
+	if _, running := td.Timer(); running || !td.TimeEntries[0].End.Equal(*at(10, 0)) {
+		t.Fatalf("entries=%+v", td.TimeEntries)
+	}
+}
+
+func newTestTodo(t *testing.T, id, title string, now time.Time) todo.Todo {
+	t.Helper()
+	tt, _ := todo.NewTitle(title)
+	td, _, err := todo.NewTodo(todo.NewTodoParams{ID: todo.TodoID(id), Title: tt, Priority: todo.PriorityLow, Now: now})
+	if err != nil {
+		t.Fatalf("NewTodo err=%v", err)
+	}
+	return td
+}
+
+func TestSyncFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	laptop, desktop := filepath.Join(dir, "laptop.json"), filepath.Join(dir, "desktop.json")
+	lr, dr := NewRepository(laptop), NewRepository(desktop)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	// start from a common state
+	if err := lr.Create(ctx, newTestTodo(t, "t1", "Write report", now)); err != nil {
+		t.Fatal(err)
+	}
+	if err := lr.Create(ctx, newTestTodo(t, "t2", "Buy milk", now)); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := SyncFiles(laptop, desktop)
+	if err != nil || rep.Pushed != 2 || rep.Pulled != 0 || len(rep.Conflicts) != 0 {
+		t.Fatalf("first sync rep=%+v err=%v", rep, err)
+	}
+
+	// laptop: complete t1, retitle t2. desktop: retitle t2 too, delete t1, add t3.
+	later := now.Add(time.Hour)
+	t1, _ := lr.GetByID(ctx, "t1")
+	t1, _, _ = t1.Complete(later)
+	_ = lr.Update(ctx, t1)
+	t2, _ := lr.GetByID(ctx, "t2")
+	title, _ := todo.NewTitle("Buy oat milk")
+	t2, _, _ = t2.ChangeTitle(title, later)
+	_ = lr.Update(ctx, t2)
+
+	d1, _ := dr.GetByID(ctx, "t1")
+	d1, _, _ = d1.SoftDelete(later)
+	_ = dr.Update(ctx, d1)
+	d2, _ := dr.GetByID(ctx, "t2")
+	title, _ = todo.NewTitle("Buy soy milk")
+	d2, _, _ = d2.ChangeTitle(title, later)
+	_ = dr.Update(ctx, d2)
+	_ = dr.Create(ctx, newTestTodo(t, "t3", "Call mum", later))
+
+	rep, err = SyncFiles(laptop, desktop)
+	if err != nil {
+		t.Fatalf("sync err=%v", err)
+	}
+	if len(rep.Conflicts) != 1 || rep.Conflicts[0].ID != "t2" || rep.Conflicts[0].Field != "title" {
+		t.Fatalf("conflicts=%+v", rep.Conflicts)
+	}
+
+	a, _ := New(laptop).Load()
+	b, _ := New(desktop).Load()
+	if todosJSON(a) != todosJSON(b) {
+		t.Fatalf("replicas differ after sync:\n%s\n%s", todosJSON(a), todosJSON(b))
+	}
+	ra, _ := New(laptop).ReplicaID()
+	rb, _ := New(desktop).ReplicaID()
+	if ra == rb {
+		t.Fatalf("replica ids %q %q", ra, rb)
+	}
+
+	got1, _ := lr.GetByID(ctx, "t1")
+	if got1.Status != todo.StatusDone || got1.DeletedAt == nil {
+		t.Fatalf("t1 status=%s deleted=%v; want done and deleted", got1.Status, got1.DeletedAt)
+	}
+	got2, _ := lr.GetByID(ctx, "t2")
+	if kept := string(rep.Conflicts[0].Kept); kept != `"`+got2.Title.String()+`"` {
+		t.Fatalf("conflict kept=%s title=%s", kept, got2.Title)
+	}
+	if _, err := lr.GetByID(ctx, "t3"); err != nil {
+		t.Fatalf("t3 not pulled: %v", err)
+	}
+
+	// nothing left to do; the files aren't rewritten
+	before, _ := New(desktop).Load()
+	rep, err = SyncFiles(laptop, desktop)
+	after, _ := New(desktop).Load()
+	if err != nil || rep.Pulled+rep.Pushed != 0 || len(rep.Conflicts) != 0 || !after.SavedAt.Equal(before.SavedAt) {
+		t.Fatalf("resync rep=%+v err=%v", rep, err)
+	}
+
+	// an edit after seeing the other side's change is not a conflict
+	got2, _ = dr.GetByID(ctx, "t2")
+	title, _ = todo.NewTitle("Buy milk (any)")
+	got2, _, _ = got2.ChangeTitle(title, later.Add(time.Hour))
+	_ = dr.Update(ctx, got2)
+	if rep, err = SyncFiles(laptop, desktop); err != nil || rep.Pulled != 1 || len(rep.Conflicts) != 0 {
+		t.Fatalf("follow-up rep=%+v err=%v", rep, err)
+	}
+}
+
+func TestSyncFiles_HardDeleteStaysDeleted(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	laptop, desktop := filepath.Join(dir, "laptop.json"), filepath.Join(dir, "desktop.json")
+	lr, dr := NewRepository(laptop), NewRepository(desktop)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	if err := lr.Create(ctx, newTestTodo(t, "t1", "Write report", now)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncFiles(laptop, desktop); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lr.HardDelete(ctx, "t1"); err != nil {
+		t.Fatalf("HardDelete err=%v", err)
+	}
+	rep, err := SyncFiles(laptop, desktop)
+	if err != nil || rep.Pushed != 1 || rep.Pulled != 0 {
+		t.Fatalf("sync rep=%+v err=%v", rep, err)
+	}
+	for _, r := range []*Repository{lr, dr} {
+		if _, err := r.GetByID(ctx, "t1"); err == nil {
+			t.Fatalf("t1 came back in %s", r.store.Path)
+		}
+	}
+
+	// undoing the delete before a sync keeps the todo
+	if err := lr.Create(ctx, newTestTodo(t, "t2", "Buy milk", now)); err != nil {
+		t.Fatal(err)
+	}
+	_ = lr.HardDelete(ctx, "t2")
+	if err := lr.Create(ctx, newTestTodo(t, "t2", "Buy milk", now)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncFiles(laptop, desktop); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dr.GetByID(ctx, "t2"); err != nil {
+		t.Fatalf("t2 not pushed: %v", err)
+	}
+}
+
+func TestSyncFiles_CopiedDirIsRekeyed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	_ = NewRepository(a).Create(ctx, newTestTodo(t, "t1", "Write report", time.Now()))
+
+	// copying just the file gives the copy its own identity
+	fs, _ := New(a).Load()
+	_ = New(b).Save(fs)
+	if rep, err := SyncFiles(a, b); err != nil || rep.Rekeyed {
+		t.Fatalf("file copy rep=%+v err=%v", rep, err)
+	}
+
+	// copying the sidecar too is detected
+	id, _ := New(a).ReplicaID()
+	_ = os.WriteFile(b+".replica", []byte(id), 0o600)
+	rep, err := SyncFiles(a, b)
+	if err != nil || !rep.Rekeyed {
+		t.Fatalf("dir copy rep=%+v err=%v", rep, err)
+	}
+	ra, _ := New(a).ReplicaID()
+	rb, _ := New(b).ReplicaID()
+	fa, _ := New(a).Load()
+	fb, _ := New(b).Load()
+	if ra == rb || todosJSON(fa) != todosJSON(fb) {
+		t.Fatalf("replicas %q %q", ra, rb)
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	_ = NewRepository(a).Create(ctx, newTestTodo(t, "t1", "Write report", time.Now()))
+	_ = NewRepository(b).Create(ctx, newTestTodo(t, "t2", "Buy milk", time.Now()))
+
+	other, _ := os.ReadFile(b)
+	rep, err := MergeInto(a, other)
+	if err != nil || rep.Pulled != 1 {
+		t.Fatalf("rep=%+v err=%v", rep, err)
+	}
+	got, _ := os.ReadFile(a)
+	todos, err := Decode(got)
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("todos=%v err=%v", todos, err)
+	}
+	if _, err := MergeInto(a, []byte("not json")); err != ErrCorruptData {
+		t.Fatalf("bad input err=%v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package jsonstore
+
+import (
+	"context"
+	"slices"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+func (r *Repository) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	fs, err := r.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	var out []todo.Project
+	for name, p := range fs.Projects {
+		if !p.Removed {
+			out = append(out, todo.Project(name))
+		}
+	}
+	for _, row := range fs.Todos {
+		if row.Project != "" && row.DeletedAt == nil {
+			out = append(out, todo.Project(row.Project))
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+func (r *Repository) AddProject(ctx context.Context, p todo.Project) error {
+	return r.setProject(p, false)
+}
+
+// RemoveProject unregisters p. Todos still in p keep it listed.
+func (r *Repository) RemoveProject(ctx context.Context, p todo.Project) error {
+	return r.setProject(p, true)
+}
+
+func (r *Repository) setProject(p todo.Project, removed bool) error {
+	if p.IsInbox() {
+		return appErr.ErrValidation
+	}
+	return r.withLock(func(fs *fileSchema) error {
+		if cur, ok := fs.Projects[p.String()]; ok && cur.Removed == removed {
+			return nil
+		} else if !ok && removed {
+			return nil
+		}
+		if fs.Projects == nil {
+			fs.Projects = map[string]projectRow{}
+		}
+		fs.Projects[p.String()] = projectRow{Removed: removed, Stamp: fs.tick()}
+		return nil
+	})
+}
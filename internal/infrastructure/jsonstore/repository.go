@@ -1,7 +1,9 @@
 package jsonstore
 
 import (
+	"cmp"
 	"context"
+	"maps"
 	"strings"
 	"time"
 
@@ -25,7 +27,12 @@ func (r *Repository) Create(ctx context.Context, t todo.Todo) error {
 				return appErr.ErrConflict
 			}
 		}
-		fs.Todos = append(fs.Todos, toRow(t))
+		row := toRow(t)
+		fs.stamp(nil, &row)
+		fs.Todos = append(fs.Todos, row)
+		// recreated by undoing a hard delete; replicas that already
+		// merged the purge still drop it
+		delete(fs.Purged, row.ID)
 		return nil
 	})
 }
@@ -34,7 +41,9 @@ func (r *Repository) Update(ctx context.Context, t todo.Todo) error {
 	return r.withLock(func(fs *fileSchema) error {
 		for i := range fs.Todos {
 			if fs.Todos[i].ID == t.ID.String() {
-				fs.Todos[i] = toRow(t)
+				row := toRow(t)
+				fs.stamp(&fs.Todos[i], &row)
+				fs.Todos[i] = row
 				return nil
 			}
 		}
@@ -63,6 +72,7 @@ func (r *Repository) List(ctx context.Context, spec ports.ListSpec) ([]todo.Todo
 	}
 
 	// convert + filter
+	now := timeNowUTC()
 	var out []todo.Todo
 	for _, row := range fs.Todos {
 		td, err := fromRow(row)
@@ -79,6 +89,12 @@ func (r *Repository) List(ctx context.Context, spec ports.ListSpec) ([]todo.Todo
 		if spec.Tag != nil && !td.Tags.Contains(*spec.Tag) {
 			continue
 		}
+		if spec.Project != nil && td.Project != *spec.Project {
+			continue
+		}
+		if !spec.IncludeUnstarted && !td.IsStarted(cmp.Or(spec.Now, now)) {
+			continue
+		}
 		if spec.Search != nil {
 			q := strings.ToLower(strings.TrimSpace(*spec.Search))
 			if q != "" && !strings.Contains(strings.ToLower(td.Title.String()), q) {
@@ -121,6 +137,10 @@ func (r *Repository) HardDelete(ctx context.Context, id todo.TodoID) error {
 		if !found {
 			return appErr.ErrNotFound
 		}
+		if fs.Purged == nil {
+			fs.Purged = map[string]Stamp{}
+		}
+		fs.Purged[id.String()] = fs.tick()
 		return nil
 	})
 }
@@ -137,6 +157,9 @@ func (r *Repository) withLock(mut func(fs *fileSchema) error) error {
 	if err != nil {
 		return err
 	}
+	if fs.Replica, err = r.store.ReplicaID(); err != nil {
+		return err
+	}
 	if err := mut(&fs); err != nil {
 		return err
 	}
@@ -167,13 +190,30 @@ func fromRow(row todoRow) (todo.Todo, error) {
 		return todo.Todo{}, ErrCorruptData
 	}
 
-	var dd *todo.DueDate
-	if row.DueDate != nil {
-		d, err := todo.ParseDueDate(*row.DueDate)
+	dd, err := parseDate(row.DueDate)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	scheduled, err := parseDate(row.Scheduled)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	remindAt, err := parseDate(row.RemindAt)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	var estimate *todo.Estimate
+	if row.Estimate != "" {
+		e, err := todo.ParseEstimate(row.Estimate)
 		if err != nil {
 			return todo.Todo{}, ErrCorruptData
 		}
-		dd = &d
+		estimate = &e
+	}
+
+	project, err := todo.NewProject(row.Project)
+	if err != nil {
+		return todo.Todo{}, ErrCorruptData
 	}
 
 	td := todo.Todo{
@@ -183,6 +223,13 @@ func fromRow(row todoRow) (todo.Todo, error) {
 		Priority:    priority,
 		Tags:        todo.NewTags(row.Tags),
 		DueDate:     dd,
+		ParentID:    todo.TodoID(row.ParentID),
+		Project:     project,
+		Scheduled:   scheduled,
+		RemindAt:    remindAt,
+		Estimate:    estimate,
+		TimeEntries: fromEntryRows(row.TimeEntries),
+		Meta:        maps.Clone(row.Meta),
 		CreatedAt:   row.CreatedAt,
 		UpdatedAt:   row.UpdatedAt,
 		CompletedAt: row.CompletedAt,
@@ -193,12 +240,33 @@ func fromRow(row todoRow) (todo.Todo, error) {
 	return td, nil
 }
 
-func toRow(t todo.Todo) todoRow {
-	var due *string
-	if t.DueDate != nil {
-		s := t.DueDate.String()
-		due = &s
+func parseDate(s *string) (*todo.DueDate, error) {
+	if s == nil {
+		return nil, nil
+	}
+	d, err := todo.ParseDueDate(*s)
+	if err != nil {
+		return nil, ErrCorruptData
+	}
+	return &d, nil
+}
+
+func formatDate(d *todo.DueDate) *string {
+	if d == nil {
+		return nil
 	}
+	s := d.String()
+	return &s
+}
+
+func estimateString(e *todo.Estimate) string {
+	if e == nil {
+		return ""
+	}
+	return e.String()
+}
+
+func toRow(t todo.Todo) todoRow {
 
 	// copy tags so we never serialize shared slice
 	tags := make([]string, len(t.Tags))
@@ -210,12 +278,57 @@ func toRow(t todo.Todo) todoRow {
 		Status:   string(t.Status),
 		Priority: t.Priority.String(),
 		Tags:     tags,
-		DueDate:  due,
+		DueDate:  formatDate(t.DueDate),
+		ParentID: t.ParentID.String(),
+		Project:  t.Project.String(),
+		Meta:     maps.Clone(t.Meta),
+
+		Scheduled: formatDate(t.Scheduled),
+		RemindAt:  formatDate(t.RemindAt),
+		Estimate:  estimateString(t.Estimate),
+
+		TimeEntries: toEntryRows(t.TimeEntries),
+
+		// stored in UTC whatever zone the clock is in
+		CreatedAt:   t.CreatedAt.UTC(),
+		UpdatedAt:   t.UpdatedAt.UTC(),
+		CompletedAt: utcPtr(t.CompletedAt),
+		ArchivedAt:  utcPtr(t.ArchivedAt),
+		DeletedAt:   utcPtr(t.DeletedAt),
+	}
+}
 
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
-		CompletedAt: t.CompletedAt,
-		ArchivedAt:  t.ArchivedAt,
-		DeletedAt:   t.DeletedAt,
+func fromEntryRows(rows []timeEntryRow) []todo.TimeEntry {
+	if len(rows) == 0 {
+		return nil
+	}
+	out := make([]todo.TimeEntry, len(rows))
+	for i, r := range rows {
+		out[i] = todo.TimeEntry{Start: r.Start, End: r.End}
+		// a timer left running on a replica that another one logged time
+		// after (see unionEntries) ends where the next entry starts
+		if i > 0 && out[i-1].End == nil {
+			out[i-1].End = &rows[i].Start
+		}
+	}
+	return out
+}
+
+func toEntryRows(es []todo.TimeEntry) []timeEntryRow {
+	if len(es) == 0 {
+		return nil
+	}
+	out := make([]timeEntryRow, len(es))
+	for i, e := range es {
+		out[i] = timeEntryRow{Start: e.Start.UTC(), End: utcPtr(e.End)}
+	}
+	return out
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
 	}
+	u := t.UTC()
+	return &u
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -92,3 +93,54 @@ func TestRepository_AtomicWrite_FileExists(t *testing.T) {
 		t.Fatalf("expected file to exist: %v", err)
 	}
 }
+
+func TestRepository_Projects(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(filepath.Join(t.TempDir(), "todos.json"))
+	now := time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+
+	title, _ := todo.NewTitle("Ship it")
+	td, _, _ := todo.NewTodo(todo.NewTodoParams{ID: "t1", Title: title, Priority: todo.PriorityLow, Project: "work", Now: now})
+	if err := repo.Create(ctx, td); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddProject(ctx, "home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddProject(ctx, ""); err == nil {
+		t.Fatalf("inbox registered")
+	}
+
+	list := func() string {
+		t.Helper()
+		ps, err := repo.ListProjects(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s []string
+		for _, p := range ps {
+			s = append(s, p.String())
+		}
+		return strings.Join(s, ",")
+	}
+	if got := list(); got != "home,work" {
+		t.Fatalf("projects=%s", got)
+	}
+
+	// a removed project stays listed while todos use it
+	_ = repo.RemoveProject(ctx, "home")
+	_ = repo.RemoveProject(ctx, "work")
+	if got := list(); got != "work" {
+		t.Fatalf("after remove projects=%s", got)
+	}
+
+	work := todo.Project("work")
+	got, err := repo.List(ctx, ports.ListSpec{Project: &work})
+	if err != nil || len(got) != 1 || got[0].Project != "work" {
+		t.Fatalf("List(work)=%+v err=%v", got, err)
+	}
+	inbox := todo.Project("")
+	if got, _ := repo.List(ctx, ports.ListSpec{Project: &inbox}); len(got) != 0 {
+		t.Fatalf("List(inbox)=%+v", got)
+	}
+}
@@ -7,7 +7,30 @@ const schemaVersion = 1
 type fileSchema struct {
 	Version int       `json:"version"`
 	SavedAt time.Time `json:"savedAt"`
-	Todos   []todoRow `json:"todos"`
+
+	// Replication state; see merge.go. Files written before replicas
+	// existed have none and are stamped from the next write on.
+	Clock uint64            `json:"clock,omitempty"` // Lamport clock
+	Seen  map[string]uint64 `json:"seen,omitempty"`  // highest clock known per replica
+
+	// Replica is kept beside the file (Store.ReplicaID), not in it, so a
+	// copied or git-cloned store doesn't take on the original's identity.
+	Replica string `json:"-"`
+
+	// Projects registered by name, so empty ones are still listed.
+	// Entries are never dropped: removal is a flag, merged like a field.
+	Projects map[string]projectRow `json:"projects,omitempty"`
+
+	// Purged holds the IDs of hard-deleted todos, stamped when they were
+	// removed, so a sync doesn't bring them back from another replica.
+	Purged map[string]Stamp `json:"purged,omitempty"`
+
+	Todos []todoRow `json:"todos"`
+}
+
+type projectRow struct {
+	Removed bool  `json:"removed,omitempty"`
+	Stamp   Stamp `json:"stamp"`
 }
 
 type todoRow struct {
@@ -17,10 +40,27 @@ type todoRow struct {
 	Priority string   `json:"priority"`
 	Tags     []string `json:"tags"`
 	DueDate  *string  `json:"dueDate"`
+	ParentID string   `json:"parentId,omitempty"`
+	Project  string   `json:"project,omitempty"`
+
+	Scheduled *string `json:"scheduled,omitempty"`
+	RemindAt  *string `json:"remindAt,omitempty"`
+	Estimate  string  `json:"estimate,omitempty"` // "1h30m" or "3pt"
+
+	TimeEntries []timeEntryRow `json:"timeEntries,omitempty"`
+
+	Meta map[string]string `json:"meta,omitempty"`
 
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
 	CompletedAt *time.Time `json:"completedAt"`
 	ArchivedAt  *time.Time `json:"archivedAt"`
 	DeletedAt   *time.Time `json:"deletedAt"`
+
+	Clocks map[string]Stamp `json:"clocks,omitempty"` // per field, see fields
+}
+
+type timeEntryRow struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"` // nil while running
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -81,3 +82,21 @@ func (s Store) Save(fs fileSchema) error {
 
 	return nil
 }
+
+// ReplicaID returns the ID this store stamps its writes with, creating it
+// on first use. It lives in "<path>.replica".
+func (s Store) ReplicaID() (string, error) {
+	b, err := os.ReadFile(s.Path + ".replica")
+	if err == nil && len(strings.TrimSpace(string(b))) > 0 {
+		return strings.TrimSpace(string(b)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return s.newReplicaID()
+}
+
+func (s Store) newReplicaID() (string, error) {
+	id := newReplicaID()
+	return id, os.WriteFile(s.Path+".replica", []byte(id+"\n"), 0o600)
+}
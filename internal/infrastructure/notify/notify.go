@@ -0,0 +1,69 @@
+// Package notify has the ways `todo daemon` can tell about a reminder.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+)
+
+// Bell rings the terminal bell and prints the reminder to W.
+type Bell struct {
+	W io.Writer
+}
+
+func (b Bell) Notify(ctx context.Context, r ports.Reminder) error {
+	line := fmt.Sprintf("\a%s reminder: %s (%s)", r.At.Format("15:04"), r.Todo.Title, r.Todo.ID)
+	if r.Todo.DueDate != nil {
+		line += ", due " + r.Todo.DueDate.String()
+	}
+	if r.Late {
+		line += " [missed]"
+	}
+	_, err := fmt.Fprintln(b.W, line)
+	return err
+}
+
+// Message is what File writes, one JSON object per line.
+type Message struct {
+	At   time.Time       `json:"at"`
+	Late bool            `json:"late,omitempty"`
+	Todo queries.TodoDTO `json:"todo"`
+}
+
+// File appends each reminder to Path as a line of JSON. Path can be a
+// FIFO; opening it then waits for a reader.
+type File struct {
+	Path string
+}
+
+func (f File) Notify(ctx context.Context, r ports.Reminder) error {
+	b, err := json.Marshal(Message{At: r.At, Late: r.Late, Todo: queries.ToDTO(r.Todo)})
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(f.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(b, '\n'))
+	return errors.Join(err, out.Close())
+}
+
+// Multi notifies through each of its notifiers, trying all of them.
+type Multi []ports.Notifier
+
+func (m Multi) Notify(ctx context.Context, r ports.Reminder) error {
+	var errs []error
+	for _, n := range m {
+		errs = append(errs, n.Notify(ctx, r))
+	}
+	return errors.Join(errs...)
+}
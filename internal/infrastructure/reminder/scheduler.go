@@ -0,0 +1,133 @@
+// Package reminder runs the reminder loop behind `todo daemon`.
+package reminder
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+)
+
+const (
+	DefaultPoll  = time.Minute
+	DefaultGrace = time.Minute
+)
+
+// Scheduler fires reminders as they come up. It sleeps until the next
+// one but never longer than Poll, so a clock that jumps (a laptop waking
+// up) is noticed soon; reminders missed meanwhile fire late rather than
+// not at all.
+//
+// What has fired is tracked by a watermark, the last time checked: each
+// tick fires the reminders set for after it, up to now. State keeps the
+// watermark across restarts.
+type Scheduler struct {
+	Reminders queries.Reminders
+	Notifier  ports.Notifier
+	Changes   <-chan struct{} // optional; wakes the loop to re-plan
+	Poll      time.Duration   // default DefaultPoll
+	Grace     time.Duration   // reminders fired later than this are Late; default DefaultGrace
+	State     string          // optional file for the watermark
+	Logger    *log.Logger
+
+	last time.Time
+}
+
+// Run ticks until ctx is done. Failing to read the store is logged and
+// retried on the next tick.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		wait := s.Poll
+		if wait <= 0 {
+			wait = DefaultPoll
+		}
+		next, err := s.Tick(ctx)
+		if err != nil {
+			s.logf("reminders: %v", err)
+		} else if !next.IsZero() {
+			wait = min(wait, max(next.Sub(s.Reminders.Clock.Now()), 0))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-s.Changes:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Tick fires the reminders that came up since the last tick and returns
+// when the next one is set for, zero when there is none.
+func (s *Scheduler) Tick(ctx context.Context) (time.Time, error) {
+	now := s.Reminders.Clock.Now()
+	if s.last.IsZero() {
+		s.last = s.load(now)
+	}
+	if now.Before(s.last) {
+		s.last = now // the clock went back
+	}
+
+	res := s.Reminders.Execute(ctx, s.last, now)
+	if res.Err != nil {
+		return time.Time{}, res.Err
+	}
+	grace := s.Grace
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+	for _, r := range res.Value {
+		r.Late = now.Sub(r.At) > grace
+		if err := s.Notifier.Notify(ctx, r); err != nil {
+			s.logf("reminder for %s: %v", r.Todo.ID, err)
+		}
+	}
+	s.last = now
+	s.save(now)
+
+	upcoming := s.Reminders.Execute(ctx, now, time.Time{})
+	if upcoming.Err != nil || len(upcoming.Value) == 0 {
+		return time.Time{}, upcoming.Err
+	}
+	return upcoming.Value[0].At, nil
+}
+
+// load reads the watermark from State; without one, only reminders from
+// now on fire.
+func (s *Scheduler) load(now time.Time) time.Time {
+	if s.State == "" {
+		return now
+	}
+	b, err := os.ReadFile(s.State)
+	if err != nil {
+		return now
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+	if err != nil {
+		s.logf("reminders: ignoring %s: %v", s.State, err)
+		return now
+	}
+	return t
+}
+
+func (s *Scheduler) save(now time.Time) {
+	if s.State == "" {
+		return
+	}
+	if err := os.WriteFile(s.State, []byte(now.UTC().Format(time.RFC3339Nano)+"\n"), 0o600); err != nil {
+		s.logf("reminders: %v", err)
+	}
+}
+
+func (s *Scheduler) logf(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf(format, args...)
+	}
+}
@@ -0,0 +1,86 @@
+package reminder
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+type recorder []ports.Reminder
+
+func (r *recorder) Notify(ctx context.Context, rem ports.Reminder) error {
+	*r = append(*r, rem)
+	return nil
+}
+
+func addReminder(t *testing.T, repo *jsonstore.Repository, id, at string, now time.Time) {
+	t.Helper()
+	title, _ := todo.NewTitle("todo " + id)
+	td, _, _ := todo.NewTodo(todo.NewTodoParams{ID: todo.TodoID(id), Title: title, Priority: todo.PriorityLow, Now: now})
+	d, err := todo.ParseDueDate(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if td, _, err = td.SetReminder(&d, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Create(context.Background(), td); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduler_FiresOnceAndLate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := jsonstore.NewRepository(filepath.Join(dir, "todos.json"))
+	loc := time.FixedZone("UTC+2", 2*3600)
+	clk := &clock{now: time.Date(2026, 10, 19, 8, 59, 0, 0, loc)}
+
+	addReminder(t, repo, "t1", "2026-10-19T09:00", clk.now)
+	addReminder(t, repo, "t2", "2026-10-19T09:30", clk.now)
+
+	var got recorder
+	state := filepath.Join(dir, "reminders.state")
+	s := &Scheduler{Reminders: queries.Reminders{Repo: repo, Clock: clk}, Notifier: &got, State: state}
+
+	next, err := s.Tick(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, loc); !next.Equal(want) || len(got) != 0 {
+		t.Fatalf("next=%v fired=%d want=%v,0", next, len(got), want)
+	}
+
+	clk.now = next
+	if _, err := s.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Todo.ID != "t1" || got[0].Late {
+		t.Fatalf("fired=%v want t1 on time", got)
+	}
+
+	// asleep past 09:30; a fresh daemon picks up from the saved state
+	clk.now = time.Date(2026, 10, 19, 11, 0, 0, 0, loc)
+	s = &Scheduler{Reminders: queries.Reminders{Repo: repo, Clock: clk}, Notifier: &got, State: state}
+	next, err = s.Tick(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Todo.ID != "t2" || !got[1].Late || !next.IsZero() {
+		t.Fatalf("fired=%v next=%v want t2 late, nothing next", got, next)
+	}
+
+	if _, err := s.Tick(ctx); err != nil || len(got) != 2 {
+		t.Fatalf("fired=%d err=%v want no repeats", len(got), err)
+	}
+}
@@ -0,0 +1,44 @@
+package reminder
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch polls path every interval and signals when its size or
+// modification time changes, so other processes' writes to the store
+// re-plan the daemon. Signals coalesce; the channel is never closed.
+func Watch(ctx context.Context, path string, every time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	stat := func() (time.Time, int64) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return fi.ModTime(), fi.Size()
+	}
+
+	go func() {
+		mod, size := stat()
+		tick := time.NewTicker(every)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			}
+			m, sz := stat()
+			if m.Equal(mod) && sz == size {
+				continue
+			}
+			mod, size = m, sz
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch
+}
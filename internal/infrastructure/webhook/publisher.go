@@ -0,0 +1,331 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+const (
+	SignatureHeader = "X-Gotodo-Signature"
+	EventHeader     = "X-Gotodo-Event"
+	DeliveryHeader  = "X-Gotodo-Delivery"
+
+	defaultMaxAttempts = 8
+	defaultBaseBackoff = 30 * time.Second
+	maxBackoff         = 6 * time.Hour
+
+	sendTimeout = 10 * time.Second
+	// claimLease holds claimed deliveries back from other senders. At most
+	// claimBatch are claimed at a time so sending them one after another
+	// ends within the lease, and only a dead sender's deliveries are
+	// claimed again.
+	claimLease = time.Minute
+	claimBatch = int(claimLease/sendTimeout) - 1
+)
+
+// Endpoint is a configured webhook target.
+type Endpoint struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events,omitempty"` // empty = every event
+}
+
+func (e Endpoint) wants(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, x := range e.Events {
+		if x == event || x == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadEndpoints reads a JSON array of endpoints. A missing file means none.
+func LoadEndpoints(path string) ([]Endpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var eps []Endpoint
+	if err := json.Unmarshal(b, &eps); err != nil {
+		return nil, fmt.Errorf("webhook: %s: %w", path, err)
+	}
+	return eps, nil
+}
+
+// Payload is the JSON body POSTed for every event.
+type Payload struct {
+	Event      string           `json:"event"`
+	TodoID     string           `json:"todoId"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Todo       *queries.TodoDTO `json:"todo,omitempty"`
+}
+
+// Publisher POSTs signed event payloads to every matching endpoint.
+// Publish only queues the deliveries and sends them on a background
+// goroutine, so a slow or dead endpoint never holds up a command. Failed
+// deliveries stay queued and are retried with exponential backoff by
+// Retry (started on every Publish and from Run). Without a Queue they
+// are kept in memory.
+type Publisher struct {
+	Endpoints []Endpoint
+	Queue     *Queue
+	Repo      ports.TodoRepository // optional: embeds the current todo
+	Clock     ports.Clock
+	Client    *http.Client
+	Logger    *log.Logger
+
+	MaxAttempts int
+	BaseBackoff time.Duration
+
+	init     sync.Once
+	inflight sync.WaitGroup
+}
+
+func (p *Publisher) Publish(ctx context.Context, evs []todo.Event) error {
+	for _, e := range evs {
+		name := todo.EventName(e)
+		pl := Payload{Event: name, TodoID: todo.EventTodoID(e).String(), OccurredAt: todo.EventTime(e)}
+		if d, ok := e.(todo.TodoDeleted); ok {
+			dto := queries.ToDTO(d.Todo) // it may be gone from the store
+			pl.Todo = &dto
+		} else if p.Repo != nil {
+			if td, err := p.Repo.GetByID(ctx, todo.EventTodoID(e)); err == nil {
+				dto := queries.ToDTO(td)
+				pl.Todo = &dto
+			}
+		}
+		body, err := json.Marshal(pl)
+		if err != nil {
+			p.logf("webhook: encode %s: %v", name, err)
+			continue
+		}
+
+		for _, ep := range p.Endpoints {
+			if !ep.wants(name) {
+				continue
+			}
+			d := Delivery{ID: newDeliveryID(), URL: ep.URL, Event: name, Body: body, NextAttempt: p.now()}
+			if err := p.queue().Push(d); err != nil {
+				p.logf("webhook: queue %s: %v", d.ID, err)
+			}
+		}
+	}
+
+	// the command's context may end as soon as it returns
+	ctx = context.WithoutCancel(ctx)
+	p.inflight.Add(1)
+	go func() {
+		defer p.inflight.Done()
+		p.Retry(ctx)
+	}()
+	return nil
+}
+
+// Wait blocks until the deliveries started by Publish are done or ctx
+// is; whatever is left stays queued.
+func (p *Publisher) Wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		p.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// Retry sends every queued delivery that is due, claimBatch at a time.
+func (p *Publisher) Retry(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := p.queue().Claim(p.now(), claimLease, claimBatch)
+		if err != nil {
+			p.logf("webhook: load queue: %v", err)
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+		for _, d := range due {
+			p.attempt(ctx, d)
+		}
+	}
+}
+
+// Run retries queued deliveries every interval until ctx is done.
+func (p *Publisher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.Retry(ctx)
+		}
+	}
+}
+
+// attempt sends a claimed delivery, then takes it off the queue or puts
+// it back for a later try.
+func (p *Publisher) attempt(ctx context.Context, d Delivery) {
+	ep, ok := p.endpoint(d.URL)
+	if !ok {
+		p.logf("webhook: dropping delivery %s: endpoint %s no longer configured", d.ID, d.URL)
+		p.remove(d)
+		return
+	}
+
+	err := p.send(ctx, ep, d)
+	if err == nil {
+		p.remove(d)
+		return
+	}
+
+	d.Attempts++
+	d.LastError = err.Error()
+	if d.Attempts >= p.maxAttempts() {
+		p.logf("webhook: giving up on %s to %s after %d attempts: %v", d.Event, d.URL, d.Attempts, err)
+		p.remove(d)
+		return
+	}
+	d.NextAttempt = p.now().Add(p.backoff(d.Attempts))
+	if qerr := p.queue().Put(d); qerr != nil {
+		p.logf("webhook: queue %s: %v", d.ID, qerr)
+	}
+}
+
+func (p *Publisher) remove(d Delivery) {
+	if err := p.queue().Remove(d.ID); err != nil {
+		p.logf("webhook: queue %s: %v", d.ID, err)
+	}
+}
+
+func (p *Publisher) queue() *Queue {
+	p.init.Do(func() {
+		if p.Queue == nil {
+			p.Queue = NewQueue("")
+		}
+	})
+	return p.Queue
+}
+
+func (p *Publisher) send(ctx context.Context, ep Endpoint, d Delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.ID)
+	if ep.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(ep.Secret, d.Body))
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s responded %s", ep.URL, resp.Status)
+	}
+	return nil
+}
+
+// backoff doubles the base delay per attempt, capped at maxBackoff.
+func (p *Publisher) backoff(attempts int) time.Duration {
+	base := p.BaseBackoff
+	if base <= 0 {
+		base = defaultBaseBackoff
+	}
+	d := base
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+func (p *Publisher) endpoint(url string) (Endpoint, bool) {
+	for _, ep := range p.Endpoints {
+		if ep.URL == url {
+			return ep, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+func (p *Publisher) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (p *Publisher) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return http.DefaultClient
+}
+
+func (p *Publisher) now() time.Time {
+	if p.Clock != nil {
+		return p.Clock.Now()
+	}
+	return time.Now().UTC()
+}
+
+func (p *Publisher) logf(format string, args ...any) {
+	if p.Logger != nil {
+		p.Logger.Printf(format, args...)
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=<hex hmac>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value in constant time.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func newDeliveryID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) Now() time.Time { return f.t }
+
+type recorder struct {
+	mu       sync.Mutex
+	fail     int // respond 500 this many times first
+	bodies   [][]byte
+	sigs     []string
+	events   []string
+	attempts int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if r.fail > 0 {
+		r.fail--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	b, _ := io.ReadAll(req.Body)
+	r.bodies = append(r.bodies, b)
+	r.sigs = append(r.sigs, req.Header.Get(SignatureHeader))
+	r.events = append(r.events, req.Header.Get(EventHeader))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func completed(id string) []todo.Event {
+	return []todo.Event{todo.TodoCompleted{ID: todo.TodoID(id), OccurredAt: time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)}}
+}
+
+func TestPublisher_SignsPayload(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	p := &Publisher{Endpoints: []Endpoint{{URL: srv.URL, Secret: "s3cret"}}}
+	if err := p.Publish(context.Background(), completed("t1")); err != nil {
+		t.Fatalf("Publish err=%v", err)
+	}
+	p.Wait(context.Background())
+
+	if len(rec.bodies) != 1 {
+		t.Fatalf("deliveries=%d want=1", len(rec.bodies))
+	}
+	if !Verify("s3cret", rec.bodies[0], rec.sigs[0]) {
+		t.Fatalf("bad signature %q", rec.sigs[0])
+	}
+	if rec.events[0] != "todo.completed" {
+		t.Fatalf("event header=%q", rec.events[0])
+	}
+
+	var pl Payload
+	if err := json.Unmarshal(rec.bodies[0], &pl); err != nil {
+		t.Fatalf("body: %v", err)
+	}
+	if pl.Event != "todo.completed" || pl.TodoID != "t1" {
+		t.Fatalf("payload=%+v", pl)
+	}
+}
+
+func TestPublisher_EventFilter(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	p := &Publisher{Endpoints: []Endpoint{{URL: srv.URL, Events: []string{"todo.created"}}}}
+	_ = p.Publish(context.Background(), completed("t1"))
+	p.Wait(context.Background())
+
+	if rec.attempts != 0 {
+		t.Fatalf("attempts=%d want=0 (filtered)", rec.attempts)
+	}
+}
+
+func TestPublisher_RetriesWithBackoffFromPersistentQueue(t *testing.T) {
+	rec := &recorder{fail: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	clk := &fakeClock{t: time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)}
+	qpath := filepath.Join(t.TempDir(), "queue.json")
+	eps := []Endpoint{{URL: srv.URL, Secret: "k"}}
+
+	p := &Publisher{Endpoints: eps, Queue: NewQueue(qpath), Clock: clk, BaseBackoff: time.Minute}
+	_ = p.Publish(context.Background(), completed("t1"))
+	p.Wait(context.Background())
+	if p.Queue.Len() != 1 {
+		t.Fatalf("queue len=%d want=1", p.Queue.Len())
+	}
+
+	// not due yet
+	p.Retry(context.Background())
+	if rec.attempts != 1 {
+		t.Fatalf("attempts=%d want=1", rec.attempts)
+	}
+
+	// a fresh publisher (new process) picks the queue up from disk
+	p2 := &Publisher{Endpoints: eps, Queue: NewQueue(qpath), Clock: clk, BaseBackoff: time.Minute}
+
+	clk.t = clk.t.Add(time.Minute)
+	p2.Retry(context.Background()) // second failure, backoff doubles to 2m
+	if rec.attempts != 2 || p2.Queue.Len() != 1 {
+		t.Fatalf("attempts=%d queue=%d", rec.attempts, p2.Queue.Len())
+	}
+
+	clk.t = clk.t.Add(time.Minute)
+	p2.Retry(context.Background())
+	if rec.attempts != 2 {
+		t.Fatalf("attempts=%d want=2 (backoff not elapsed)", rec.attempts)
+	}
+
+	clk.t = clk.t.Add(time.Minute)
+	p2.Retry(context.Background())
+	if rec.attempts != 3 || len(rec.bodies) != 1 || p2.Queue.Len() != 0 {
+		t.Fatalf("attempts=%d delivered=%d queue=%d", rec.attempts, len(rec.bodies), p2.Queue.Len())
+	}
+	if !Verify("k", rec.bodies[0], rec.sigs[0]) {
+		t.Fatalf("bad signature after retry")
+	}
+}
+
+func TestPublisher_RetrySendsInBatchesWithinTheLease(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	now := time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+	q := NewQueue("")
+	n := 2*claimBatch + 1
+	for i := range n {
+		_ = q.Push(Delivery{ID: fmt.Sprint(i), URL: srv.URL, NextAttempt: now})
+	}
+	if due, _ := q.Claim(now, claimLease, claimBatch); len(due) != claimBatch {
+		t.Fatalf("claimed %d want %d", len(due), claimBatch)
+	}
+
+	p := &Publisher{Endpoints: []Endpoint{{URL: srv.URL}}, Queue: q, Clock: &fakeClock{t: now.Add(claimLease)}}
+	p.Retry(context.Background())
+	if len(rec.bodies) != n || q.Len() != 0 {
+		t.Fatalf("delivered=%d queue=%d want %d and 0", len(rec.bodies), q.Len(), n)
+	}
+}
+
+func TestPublisher_GivesUpAfterMaxAttempts(t *testing.T) {
+	rec := &recorder{fail: 100}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	clk := &fakeClock{t: time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)}
+	p := &Publisher{Endpoints: []Endpoint{{URL: srv.URL}}, Queue: NewQueue(""), Clock: clk, MaxAttempts: 2, BaseBackoff: time.Second}
+	_ = p.Publish(context.Background(), completed("t1"))
+	p.Wait(context.Background())
+
+	clk.t = clk.t.Add(time.Hour)
+	p.Retry(context.Background())
+	if p.Queue.Len() != 0 {
+		t.Fatalf("queue len=%d want=0", p.Queue.Len())
+	}
+	if rec.attempts != 2 {
+		t.Fatalf("attempts=%d want=2", rec.attempts)
+	}
+}
+
+func TestPublisher_DoesNotBlockOnSlowEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	defer srv.Close()
+	defer close(release)
+
+	p := &Publisher{Endpoints: []Endpoint{{URL: srv.URL}}}
+	start := time.Now()
+	_ = p.Publish(context.Background(), completed("t1"))
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Publish took %s", d)
+	}
+}
+
+func TestQueue_ClaimKeepsDeliveries(t *testing.T) {
+	now := time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "queue.json")
+	if err := NewQueue(path).Push(Delivery{ID: "d1", NextAttempt: now}); err != nil {
+		t.Fatalf("Push err=%v", err)
+	}
+
+	// claimed by a process that then dies before sending
+	if due, err := NewQueue(path).Claim(now, time.Minute, 10); err != nil || len(due) != 1 {
+		t.Fatalf("claim=%v err=%v", due, err)
+	}
+	other := NewQueue(path)
+	if due, _ := other.Claim(now, time.Minute, 10); len(due) != 0 {
+		t.Fatalf("claimed twice: %v", due)
+	}
+	if due, _ := other.Claim(now.Add(time.Minute), time.Minute, 10); len(due) != 1 {
+		t.Fatalf("claim after the lease=%v want d1", due)
+	}
+	if err := other.Remove("d1"); err != nil || other.Len() != 0 {
+		t.Fatalf("Remove err=%v len=%d", err, other.Len())
+	}
+}
+
+func TestQueue_BreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	// left behind by a process that died while holding it
+	dir := path + ".lockdir"
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	info := fmt.Sprintf("pid=1\nat=%s\n", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano))
+	if err := os.WriteFile(filepath.Join(dir, "info.txt"), []byte(info), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewQueue(path).Push(Delivery{ID: "d1"}); err != nil {
+		t.Fatalf("Push err=%v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("lock left behind: %v", err)
+	}
+}
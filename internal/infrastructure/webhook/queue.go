@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueueLocked is returned when another process holds the queue for
+// longer than lockWait.
+var ErrQueueLocked = errors.New("webhook: queue is locked")
+
+const (
+	lockWait = 5 * time.Second
+	// staleLock bounds how long a queue update holds the lock; an update
+	// only reads and rewrites the small queue file.
+	staleLock = 30 * time.Second
+)
+
+// Delivery is one pending POST of an event payload to an endpoint.
+type Delivery struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Event       string    `json:"event"`
+	Body        []byte    `json:"body"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// Queue is a small persistent delivery queue. A delivery stays in it
+// until it is delivered or given up on. The file is re-read under a lock
+// for every change, so several processes (the TUI, `todo serve`) can
+// share it. With an empty Path it only lives in memory (useful in tests).
+type Queue struct {
+	Path string
+
+	mu    sync.Mutex
+	items []Delivery // only without a Path
+}
+
+func NewQueue(path string) *Queue {
+	return &Queue{Path: path}
+}
+
+// Push appends d.
+func (q *Queue) Push(d Delivery) error {
+	return q.update(func(items []Delivery) []Delivery { return append(items, d) })
+}
+
+// Claim returns up to max deliveries whose NextAttempt is not after now,
+// and holds them back until now+lease so no one else sends them
+// meanwhile. They stay queued: a process that dies while sending leaves
+// them to be claimed again once the lease runs out.
+func (q *Queue) Claim(now time.Time, lease time.Duration, max int) ([]Delivery, error) {
+	var due []Delivery
+	err := q.update(func(items []Delivery) []Delivery {
+		for i, d := range items {
+			if len(due) < max && !d.NextAttempt.After(now) {
+				due = append(due, d)
+				items[i].NextAttempt = now.Add(lease)
+			}
+		}
+		return items
+	})
+	return due, err
+}
+
+// Put replaces the queued delivery with d's ID, e.g. after a failed
+// attempt.
+func (q *Queue) Put(d Delivery) error {
+	return q.update(func(items []Delivery) []Delivery {
+		for i := range items {
+			if items[i].ID == d.ID {
+				items[i] = d
+			}
+		}
+		return items
+	})
+}
+
+// Remove drops the delivery with the given ID, once it is delivered or
+// given up on.
+func (q *Queue) Remove(id string) error {
+	return q.update(func(items []Delivery) []Delivery {
+		return slices.DeleteFunc(items, func(d Delivery) bool { return d.ID == id })
+	})
+}
+
+// Len reports how many deliveries are waiting.
+func (q *Queue) Len() int {
+	n := 0
+	_ = q.update(func(items []Delivery) []Delivery {
+		n = len(items)
+		return items
+	})
+	return n
+}
+
+// update applies fn to the queued deliveries and saves the result.
+func (q *Queue) update(fn func([]Delivery) []Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.Path == "" {
+		q.items = fn(q.items)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.Path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(q.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var items []Delivery
+	b, err := os.ReadFile(q.Path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &items); err != nil {
+			return fmt.Errorf("webhook: %s: %w", q.Path, err)
+		}
+	}
+	items = fn(items)
+
+	b, err = json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	tmp := q.Path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.Path)
+}
+
+// lockFile takes the lock directory next to path, like the JSON store's,
+// waiting up to lockWait for another process to let go of it. A lock
+// older than staleLock was left by a process that died holding it and
+// is broken.
+func lockFile(path string) (unlock func(), err error) {
+	dir := path + ".lockdir"
+	deadline := time.Now().Add(lockWait)
+	for {
+		err := os.Mkdir(dir, 0o700)
+		if err == nil {
+			// owner info, read back by lockAge
+			_ = os.WriteFile(filepath.Join(dir, "info.txt"),
+				[]byte(fmt.Sprintf("pid=%d\nat=%s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339Nano))),
+				0o600,
+			)
+			return func() { _ = os.RemoveAll(dir) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if age, ok := lockAge(dir); ok && age > staleLock {
+			// rename first so two processes breaking it at once cannot
+			// remove a lock a third one has taken meanwhile
+			stale := fmt.Sprintf("%s.stale.%d", dir, os.Getpid())
+			if os.Rename(dir, stale) == nil {
+				_ = os.RemoveAll(stale)
+				continue
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrQueueLocked
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lockAge reports how long ago the lock in dir was taken, from its owner
+// info or, when the owner died before writing it, the directory itself.
+func lockAge(dir string) (time.Duration, bool) {
+	if b, err := os.ReadFile(filepath.Join(dir, "info.txt")); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if v, ok := strings.CutPrefix(line, "at="); ok {
+				if at, err := time.Parse(time.RFC3339Nano, v); err == nil {
+					return time.Since(at), true
+				}
+			}
+		}
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(fi.ModTime()), true
+}
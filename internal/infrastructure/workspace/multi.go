@@ -0,0 +1,221 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
+
+// Repository is what a Multi needs of each store.
+type Repository interface {
+	ports.TodoRepository
+	ports.ProjectRepository
+}
+
+// Source is one store of a Multi. Label "" is the global store; the todos
+// of every other source show up under the project Label, so project "x"
+// of workspace "api" reads as "api/x" and its inbox as "api".
+type Source struct {
+	Label string
+	Repo  Repository
+}
+
+// Multi reads several stores as one. Writes go to the store that holds
+// the todo; new todos go where their project points.
+type Multi []Source
+
+// ErrCrossWorkspace is returned when an update would move a todo out of
+// its store.
+var ErrCrossWorkspace = errors.New("workspace: todos cannot move between workspaces")
+
+// Labels names workspaces after their directories, unique and usable as
+// project names.
+func Labels(roots []string) []string {
+	out := make([]string, len(roots))
+	seen := map[string]bool{}
+	for i, root := range roots {
+		base, err := todo.NewProject(strings.ReplaceAll(filepath.Base(root), " ", "-"))
+		if err != nil || base.IsInbox() {
+			base = "workspace"
+		}
+		label := base.String()
+		for n := 2; seen[label]; n++ {
+			label = fmt.Sprintf("%s-%d", base, n)
+		}
+		seen[label] = true
+		out[i] = label
+	}
+	return out
+}
+
+// route finds the source a project belongs to and the project's name there.
+func (m Multi) route(p todo.Project) (Source, todo.Project, error) {
+	for _, s := range m {
+		if s.Label == "" {
+			continue
+		}
+		if p.String() == s.Label {
+			return s, "", nil
+		}
+		if rest, ok := strings.CutPrefix(p.String(), s.Label+"/"); ok {
+			return s, todo.Project(rest), nil
+		}
+	}
+	for _, s := range m {
+		if s.Label == "" {
+			return s, p, nil
+		}
+	}
+	return Source{}, "", appErr.ErrNotFound
+}
+
+func show(s Source, td todo.Todo) todo.Todo {
+	td.Project = qualify(s, td.Project)
+	return td
+}
+
+func qualify(s Source, p todo.Project) todo.Project {
+	switch {
+	case s.Label == "":
+		return p
+	case p.IsInbox():
+		return todo.Project(s.Label)
+	}
+	return todo.Project(s.Label + "/" + p.String())
+}
+
+// owner finds the source holding id.
+func (m Multi) owner(ctx context.Context, id todo.TodoID) (Source, todo.Todo, error) {
+	for _, s := range m {
+		td, err := s.Repo.GetByID(ctx, id)
+		if err == nil {
+			return s, td, nil
+		}
+		if !errors.Is(err, appErr.ErrNotFound) {
+			return Source{}, todo.Todo{}, err
+		}
+	}
+	return Source{}, todo.Todo{}, appErr.ErrNotFound
+}
+
+func (m Multi) Create(ctx context.Context, t todo.Todo) error {
+	s, p, err := m.route(t.Project)
+	if err != nil {
+		return err
+	}
+	t.Project = p
+	return s.Repo.Create(ctx, t)
+}
+
+func (m Multi) Update(ctx context.Context, t todo.Todo) error {
+	s, _, err := m.owner(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	target, p, err := m.route(t.Project)
+	if err != nil {
+		return err
+	}
+	if target.Label != s.Label {
+		return ErrCrossWorkspace
+	}
+	t.Project = p
+	return s.Repo.Update(ctx, t)
+}
+
+func (m Multi) GetByID(ctx context.Context, id todo.TodoID) (todo.Todo, error) {
+	s, td, err := m.owner(ctx, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+	return show(s, td), nil
+}
+
+func (m Multi) List(ctx context.Context, spec ports.ListSpec) ([]todo.Todo, error) {
+	sources := m
+	if spec.Project != nil {
+		s, p, err := m.route(*spec.Project)
+		if err != nil {
+			return nil, err
+		}
+		sources = Multi{s}
+		spec.Project = &p
+	}
+
+	var out []todo.Todo
+	for _, s := range sources {
+		tds, err := s.Repo.List(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, td := range tds {
+			out = append(out, show(s, td))
+		}
+	}
+	return out, nil
+}
+
+func (m Multi) SoftDelete(ctx context.Context, id todo.TodoID) error {
+	s, _, err := m.owner(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.Repo.SoftDelete(ctx, id)
+}
+
+func (m Multi) HardDelete(ctx context.Context, id todo.TodoID) error {
+	s, _, err := m.owner(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.Repo.HardDelete(ctx, id)
+}
+
+// ListProjects lists every store's projects, and each workspace's label
+// for its inbox.
+func (m Multi) ListProjects(ctx context.Context) ([]todo.Project, error) {
+	var out []todo.Project
+	for _, s := range m {
+		ps, err := s.Repo.ListProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if s.Label != "" {
+			out = append(out, todo.Project(s.Label))
+		}
+		for _, p := range ps {
+			out = append(out, qualify(s, p))
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+func (m Multi) AddProject(ctx context.Context, p todo.Project) error {
+	s, name, err := m.route(p)
+	if err != nil {
+		return err
+	}
+	if name.IsInbox() {
+		return nil // a workspace's own label
+	}
+	return s.Repo.AddProject(ctx, name)
+}
+
+func (m Multi) RemoveProject(ctx context.Context, p todo.Project) error {
+	s, name, err := m.route(p)
+	if err != nil {
+		return err
+	}
+	if name.IsInbox() {
+		return appErr.ErrValidation
+	}
+	return s.Repo.RemoveProject(ctx, name)
+}
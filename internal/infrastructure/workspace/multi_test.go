@@ -0,0 +1,109 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+func newTodo(t *testing.T, id, title string, p todo.Project) todo.Todo {
+	t.Helper()
+	ti, _ := todo.NewTitle(title)
+	pri, _ := todo.NewPriority("low")
+	td, _, err := todo.NewTodo(todo.NewTodoParams{
+		ID: todo.TodoID(id), Title: ti, Priority: pri, Project: p,
+		Now: time.Date(2025, 12, 14, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return td
+}
+
+func TestMulti(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	global := jsonstore.NewRepository(filepath.Join(dir, "global.json"))
+	api := jsonstore.NewRepository(filepath.Join(dir, "api.json"))
+	m := Multi{{Repo: global}, {Label: "api", Repo: api}}
+
+	for _, td := range []todo.Todo{
+		newTodo(t, "g1", "Groceries", ""),
+		newTodo(t, "g2", "Taxes", "home"),
+		newTodo(t, "a1", "Fix login", "api"),
+		newTodo(t, "a2", "Write docs", "api/docs"),
+	} {
+		if err := m.Create(ctx, td); err != nil {
+			t.Fatalf("Create %s err=%v", td.ID, err)
+		}
+	}
+
+	// stored without the label
+	if got, err := api.GetByID(ctx, "a2"); err != nil || got.Project != "docs" {
+		t.Fatalf("api a2=%+v err=%v", got, err)
+	}
+	if got, err := api.GetByID(ctx, "a1"); err != nil || !got.Project.IsInbox() {
+		t.Fatalf("api a1=%+v err=%v", got, err)
+	}
+
+	projectsOf := func(spec ports.ListSpec) []string {
+		t.Helper()
+		tds, err := m.List(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, td := range tds {
+			out = append(out, td.ID.String()+":"+td.Project.String())
+		}
+		slices.Sort(out)
+		return out
+	}
+	if got := projectsOf(ports.ListSpec{}); !slices.Equal(got, []string{"a1:api", "a2:api/docs", "g1:", "g2:home"}) {
+		t.Fatalf("List=%q", got)
+	}
+	api0 := todo.Project("api")
+	if got := projectsOf(ports.ListSpec{Project: &api0}); !slices.Equal(got, []string{"a1:api"}) {
+		t.Fatalf("List api=%q", got)
+	}
+	inbox := todo.Project("")
+	if got := projectsOf(ports.ListSpec{Project: &inbox}); !slices.Equal(got, []string{"g1:"}) {
+		t.Fatalf("List inbox=%q", got)
+	}
+
+	ps, err := m.ListProjects(ctx)
+	if err != nil || !slices.Equal(ps, []todo.Project{"api", "api/docs", "home"}) {
+		t.Fatalf("ListProjects=%q err=%v", ps, err)
+	}
+
+	// updates go to the owner; moving within it is fine, out of it is not
+	a2, err := m.GetByID(ctx, "a2")
+	if err != nil || a2.Project != "api/docs" {
+		t.Fatalf("GetByID=%+v err=%v", a2, err)
+	}
+	moved, _, _ := a2.MoveTo("api/web", a2.UpdatedAt.Add(time.Minute))
+	if err := m.Update(ctx, moved); err != nil {
+		t.Fatalf("Update err=%v", err)
+	}
+	if got, _ := api.GetByID(ctx, "a2"); got.Project != "web" {
+		t.Fatalf("api a2 project=%q", got.Project)
+	}
+	away, _, _ := moved.MoveTo("home", moved.UpdatedAt.Add(time.Minute))
+	if err := m.Update(ctx, away); !errors.Is(err, ErrCrossWorkspace) {
+		t.Fatalf("cross-workspace Update err=%v", err)
+	}
+
+	if err := m.SoftDelete(ctx, "g1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := global.GetByID(ctx, "g1"); got.DeletedAt == nil {
+		t.Fatalf("g1 not deleted in the global store")
+	}
+}
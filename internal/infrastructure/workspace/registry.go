@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Registry remembers the workspaces that have been used, so they can be
+// listed together. It is a JSON array of root directories.
+type Registry struct {
+	Path string
+}
+
+// Roots lists the known workspaces that still exist, sorted.
+func (r Registry) Roots() ([]string, error) {
+	roots, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	out := roots[:0]
+	for _, root := range roots {
+		if isDir(filepath.Join(root, DirName)) {
+			out = append(out, root)
+		}
+	}
+	return out, nil
+}
+
+// Add records root; known roots are left alone.
+func (r Registry) Add(root string) error {
+	roots, err := r.load()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(roots, root) {
+		return nil
+	}
+	roots = append(roots, root)
+	slices.Sort(roots)
+
+	b, err := json.MarshalIndent(roots, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o700); err != nil {
+		return err
+	}
+	tmp := r.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.Path)
+}
+
+func (r Registry) load() ([]string, error) {
+	b, err := os.ReadFile(r.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var roots []string
+	if err := json.Unmarshal(b, &roots); err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
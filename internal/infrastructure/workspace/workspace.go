@@ -0,0 +1,58 @@
+// Package workspace finds per-directory todo stores: a .gotodo directory
+// in the working directory or one of its parents, the way git finds .git.
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// DirName is the directory that marks a workspace root.
+const DirName = ".gotodo"
+
+// StorePath is the todos file of the workspace rooted at root.
+func StorePath(root string) string { return filepath.Join(root, DirName, "todos.json") }
+
+// Find returns the root of the nearest workspace at or above dir. The
+// .gotodo directory in the home directory is the old global store, not a
+// workspace, so it is skipped.
+func Find(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	home, _ := os.UserHomeDir()
+	for {
+		if dir != home && isDir(filepath.Join(dir, DirName)) {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Init makes dir a workspace. created is false when it already was one.
+func Init(dir string) (root string, created bool, err error) {
+	if root, err = filepath.Abs(dir); err != nil {
+		return "", false, err
+	}
+	if home, _ := os.UserHomeDir(); root == home {
+		return "", false, errors.New("workspace: the home directory holds the global store")
+	}
+	if isDir(filepath.Join(root, DirName)) {
+		return root, false, nil
+	}
+	if err := os.MkdirAll(filepath.Join(root, DirName), 0o700); err != nil {
+		return "", false, err
+	}
+	return root, true, nil
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
@@ -0,0 +1,85 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindInit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// the old global store in ~/.gotodo is not a workspace
+	if err := os.Mkdir(filepath.Join(home, DirName), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	deep := filepath.Join(home, "src", "api", "internal", "x")
+	if err := os.MkdirAll(deep, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if root, ok := Find(deep); ok {
+		t.Fatalf("found %s in a tree without workspaces", root)
+	}
+	if _, _, err := Init(home); err == nil {
+		t.Fatalf("Init(home) succeeded")
+	}
+
+	api := filepath.Join(home, "src", "api")
+	root, created, err := Init(api)
+	if err != nil || !created || root != api {
+		t.Fatalf("Init root=%s created=%v err=%v", root, created, err)
+	}
+	if _, created, _ := Init(api); created {
+		t.Fatalf("second Init created again")
+	}
+	if root, ok := Find(deep); !ok || root != api {
+		t.Fatalf("Find=%s,%v want=%s", root, ok, api)
+	}
+	if got := StorePath(root); got != filepath.Join(api, ".gotodo", "todos.json") {
+		t.Fatalf("StorePath=%s", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	r := Registry{Path: filepath.Join(dir, "data", "workspaces.json")}
+
+	if roots, err := r.Roots(); err != nil || len(roots) != 0 {
+		t.Fatalf("empty Roots=%v err=%v", roots, err)
+	}
+
+	var want []string
+	for _, name := range []string{"web", "api", "gone"} {
+		root, _, err := Init(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(root); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(root); err != nil {
+			t.Fatal(err)
+		}
+		if name != "gone" {
+			want = append(want, root)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "gone")); err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(want)
+	if roots, err := r.Roots(); err != nil || !slices.Equal(roots, want) {
+		t.Fatalf("Roots=%v err=%v want=%v", roots, err, want)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	got := Labels([]string{"/a/API", "/b/api", "/c/My Notes", "/d/!!", "/e/api"})
+	want := []string{"api", "api-2", "my-notes", "workspace", "api-3"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Labels=%q want=%q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
+)
+
+// heartbeat keeps idle connections open through proxies.
+const heartbeat = 15 * time.Second
+
+// eventData is the JSON payload of one server-sent event. Todo is the
+// todo's state when the event is sent, not when it happened.
+type eventData struct {
+	Event      string           `json:"event"`
+	TodoID     string           `json:"todoId"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Todo       *queries.TodoDTO `json:"todo,omitempty"`
+}
+
+// handleEvents streams domain events as text/event-stream, including
+// "todo.changed" for edits other processes made to the store. Event IDs are
+// "<epoch>-<seq>"; a client reconnecting with Last-Event-ID (or the
+// lastEventId query parameter) gets what it missed from the buffer, or a
+// "reset" event when that is no longer possible and it should reload.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	seq, resumable := s.parseEventID(lastID)
+
+	backlog, live, cancel, complete := s.Events.Subscribe(seq)
+	defer cancel()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 2000\n\n")
+	if lastID != "" && (!resumable || !complete) {
+		fmt.Fprintf(w, "id: %s\nevent: reset\ndata: {}\n\n", s.eventID(s.Events.LastID()))
+	}
+	for _, m := range backlog {
+		s.writeEvent(w, r, m)
+	}
+	flusher.Flush()
+
+	tick := time.NewTicker(heartbeat)
+	defer tick.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case m, ok := <-live:
+			if !ok {
+				return // fell behind; the client reconnects and resumes
+			}
+			s.writeEvent(w, r, m)
+		case <-tick.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *Server) writeEvent(w http.ResponseWriter, r *http.Request, m events.Message) {
+	data := eventData{Event: m.Event, TodoID: m.TodoID.String(), OccurredAt: m.OccurredAt}
+	if res := s.Get.Execute(r.Context(), m.TodoID); res.Err == nil {
+		data.Todo = &res.Value
+	}
+	b, _ := json.Marshal(data)
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", s.eventID(m.ID), m.Event, b)
+}
+
+func (s *Server) eventID(seq uint64) string {
+	return s.Events.Epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// parseEventID returns the sequence number of an ID issued by this
+// broker; IDs from another epoch are not resumable.
+func (s *Server) parseEventID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != s.Events.Epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	id, name string
+	data     eventData
+}
+
+// readEvents reads n events (skipping comments and retry lines).
+func readEvents(t *testing.T, sc *bufio.Scanner, n int) []sseEvent {
+	t.Helper()
+	var (
+		out []sseEvent
+		cur sseEvent
+	)
+	for len(out) < n && sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "":
+			if cur.name != "" {
+				out = append(out, cur)
+			}
+			cur = sseEvent{}
+		case strings.HasPrefix(line, "id: "):
+			cur.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			cur.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &cur.data); err != nil {
+				t.Fatalf("bad data %q: %v", line, err)
+			}
+		}
+	}
+	if len(out) < n {
+		t.Fatalf("got %d events want=%d (err=%v)", len(out), n, sc.Err())
+	}
+	return out
+}
+
+func stream(t *testing.T, ts *httptest.Server, lastID string) *bufio.Scanner {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/api/events", nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type=%q", ct)
+	}
+	return bufio.NewScanner(resp.Body)
+}
+
+func TestEvents_LiveAndResume(t *testing.T) {
+	ts := newTestServer(t)
+	live := stream(t, ts, "")
+
+	do(t, ts, "POST", "/api/todos", `{"title":"Stream me"}`)
+	do(t, ts, "POST", "/api/todos/t1/complete", "")
+
+	got := readEvents(t, live, 2)
+	if got[0].name != "todo.created" || got[1].name != "todo.completed" || got[1].data.TodoID != "t1" {
+		t.Fatalf("events=%+v", got)
+	}
+	if got[1].data.Todo == nil || got[1].data.Todo.Status != "done" {
+		t.Fatalf("todo payload=%+v", got[1].data.Todo)
+	}
+
+	// a client that saw only the first event catches up on reconnect
+	resumed := readEvents(t, stream(t, ts, got[0].id), 1)
+	if resumed[0].id != got[1].id || resumed[0].name != "todo.completed" {
+		t.Fatalf("resumed=%+v", resumed)
+	}
+
+	// an ID from another server run can't be resumed
+	if reset := readEvents(t, stream(t, ts, "old-3"), 1); reset[0].name != "reset" {
+		t.Fatalf("want reset, got %+v", reset)
+	}
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// LocalOnly guards h, served on addr, from other sites open in the user's
+// browser. The Host header must name addr, or localhost on its port when
+// addr is a loopback or wildcard address, which defeats DNS rebinding. A
+// request carrying an Origin must come from that same host, which together
+// with the JSON content type required on bodies defeats cross-site forms.
+func LocalOnly(addr string, h http.Handler) http.Handler {
+	hosts := map[string]bool{addr: true}
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); host == "" || host == "localhost" || ip != nil && (ip.IsLoopback() || ip.IsUnspecified()) {
+			for _, name := range []string{"localhost", "127.0.0.1", "::1"} {
+				hosts[net.JoinHostPort(name, port)] = true
+			}
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hosts[r.Host] {
+			writeError(w, http.StatusMisdirectedRequest, "unexpected Host "+r.Host)
+			return
+		}
+		if o := r.Header.Get("Origin"); o != "" {
+			u, err := url.Parse(o)
+			if err != nil || u.Host != r.Host || (u.Scheme != "http" && u.Scheme != "https") {
+				writeError(w, http.StatusForbidden, "cross-origin requests are not allowed")
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
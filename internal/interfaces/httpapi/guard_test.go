@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalOnly(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	h := LocalOnly("127.0.0.1:8089", requireJSON(ok))
+
+	tests := []struct {
+		name, method, host, origin, ctype, body string
+		want                                    int
+	}{
+		{"same host", "GET", "127.0.0.1:8089", "", "", "", http.StatusNoContent},
+		{"localhost alias", "GET", "localhost:8089", "", "", "", http.StatusNoContent},
+		{"rebound name", "GET", "evil.example:8089", "", "", "", http.StatusMisdirectedRequest},
+		{"other port", "GET", "localhost:9000", "", "", "", http.StatusMisdirectedRequest},
+		{"same origin", "POST", "localhost:8089", "http://localhost:8089", "application/json", "{}", http.StatusNoContent},
+		{"foreign origin", "POST", "127.0.0.1:8089", "http://evil.example", "application/json", "{}", http.StatusForbidden},
+		{"null origin", "POST", "127.0.0.1:8089", "null", "", "", http.StatusForbidden},
+		{"form body", "POST", "127.0.0.1:8089", "", "text/plain", "{}", http.StatusUnsupportedMediaType},
+		{"json with charset", "PATCH", "127.0.0.1:8089", "", "application/json; charset=utf-8", "{}", http.StatusNoContent},
+		{"no body", "POST", "127.0.0.1:8089", "", "", "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/todos", strings.NewReader(tt.body))
+		req.Host = tt.host
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.ctype != "" {
+			req.Header.Set("Content-Type", tt.ctype)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status=%d want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+// Package httpapi exposes the use cases as a local JSON API.
+//
+// Every todo response carries an ETag; mutating requests may send it back
+// in If-Match to fail with 412 instead of overwriting someone else's change.
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// maxBody bounds request bodies; todos are small.
+const maxBody = 1 << 20
+
+type Server struct {
+	// Commands
+	Add        commands.AddTodo
+	Edit       commands.EditTodo
+	Complete   commands.CompleteTodo
+	Reopen     commands.ReopenTodo
+	Archive    commands.ArchiveTodo
+	Restore    commands.RestoreTodo
+	Delete     commands.SoftDeleteTodo
+	HardDelete commands.HardDeleteTodo
+
+	// Queries
+	List     queries.ListTodos
+	Get      queries.GetTodo
+	Stats    queries.Stats
+	Projects queries.ListProjects // optional; enables GET /api/projects
+
+	Events *events.Broker // optional; enables GET /api/events
+	Logger *log.Logger    // optional
+
+	// mu makes the If-Match check and the write one step for this process.
+	mu sync.Mutex
+}
+
+// Handler returns the API routes, all under /api/.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/todos", s.handleList)
+	mux.HandleFunc("POST /api/todos", s.handleCreate)
+	mux.HandleFunc("GET /api/todos/{id}", s.handleGet)
+	mux.HandleFunc("PATCH /api/todos/{id}", s.handleEdit)
+	mux.HandleFunc("DELETE /api/todos/{id}", s.handleDelete)
+	mux.HandleFunc("POST /api/todos/{id}/complete", s.action(s.Complete.Execute))
+	mux.HandleFunc("POST /api/todos/{id}/reopen", s.action(s.Reopen.Execute))
+	mux.HandleFunc("POST /api/todos/{id}/archive", s.action(s.Archive.Execute))
+	mux.HandleFunc("POST /api/todos/{id}/restore", s.action(s.Restore.Execute))
+	mux.HandleFunc("GET /api/stats", s.handleStats)
+	if s.Projects.Projects != nil {
+		mux.HandleFunc("GET /api/projects", s.handleProjects)
+	}
+	if s.Events != nil {
+		mux.HandleFunc("GET /api/events", s.handleEvents)
+	}
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPI)
+	})
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no such endpoint")
+	})
+	return requireJSON(mux)
+}
+
+// requireJSON rejects POST, PATCH and DELETE bodies that are not
+// application/json, which a cross-site HTML form cannot send.
+func requireJSON(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPatch, http.MethodDelete:
+			if r.ContentLength == 0 {
+				break
+			}
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "request bodies must be application/json")
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	spec, err := ParseListSpec(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res := s.List.Execute(r.Context(), spec)
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Value)
+}
+
+type createBody struct {
+	Title    string   `json:"title"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags"`
+	DueDate  *string  `json:"dueDate"`
+	Project  string   `json:"project"`
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var in createBody
+	if !decode(w, r, &in) {
+		return
+	}
+
+	res := s.Add.Execute(r.Context(), commands.AddTodoInput{
+		Title: in.Title, Priority: in.Priority, Tags: in.Tags, DueDate: in.DueDate, Project: in.Project,
+	})
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	w.Header().Set("Location", "/api/todos/"+res.Value.ID.String())
+	writeTodo(w, http.StatusCreated, queries.ToDTO(res.Value))
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	res := s.Get.Execute(r.Context(), todo.TodoID(r.PathValue("id")))
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	if tag := ETag(res.Value); matchETag(r.Header.Get("If-None-Match"), tag) {
+		w.Header().Set("ETag", tag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeTodo(w, http.StatusOK, res.Value)
+}
+
+func (s *Server) handleEdit(w http.ResponseWriter, r *http.Request) {
+	var raw map[string]json.RawMessage
+	if !decode(w, r, &raw) {
+		return
+	}
+
+	in := commands.EditTodoInput{ID: todo.TodoID(r.PathValue("id"))}
+	for k, v := range raw {
+		var err error
+		switch k {
+		case "title":
+			err = json.Unmarshal(v, &in.Title)
+		case "priority":
+			err = json.Unmarshal(v, &in.Priority)
+		case "tags":
+			err = json.Unmarshal(v, &in.Tags)
+		case "dueDate":
+			var due *string // null clears the due date
+			err = json.Unmarshal(v, &due)
+			in.DueDate = &due
+		case "scheduled":
+			var when *string // null clears it
+			err = json.Unmarshal(v, &when)
+			in.Scheduled = &when
+		case "remindAt":
+			var when *string
+			err = json.Unmarshal(v, &when)
+			in.RemindAt = &when
+		case "estimate":
+			err = json.Unmarshal(v, &in.Estimate)
+		case "project":
+			err = json.Unmarshal(v, &in.Project)
+		default:
+			err = errors.New("unknown field")
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", k, err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.precondition(w, r, in.ID) {
+		return
+	}
+	s.writeResult(w, s.Edit.Execute(r.Context(), in))
+}
+
+// handleDelete soft-deletes; ?hard=true removes the todo for good.
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := todo.TodoID(r.PathValue("id"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.precondition(w, r, id) {
+		return
+	}
+
+	if hard, _ := strconv.ParseBool(r.URL.Query().Get("hard")); hard {
+		if res := s.HardDelete.Execute(r.Context(), id); res.Err != nil {
+			s.fail(w, res.Err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.writeResult(w, s.Delete.Execute(r.Context(), id))
+}
+
+func (s *Server) action(run func(context.Context, todo.TodoID) result.Result[todo.Todo]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := todo.TodoID(r.PathValue("id"))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.precondition(w, r, id) {
+			return
+		}
+		s.writeResult(w, run(r.Context(), id))
+	}
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	res := s.Stats.Execute(r.Context())
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Value)
+}
+
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	res := s.Projects.Execute(r.Context())
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Value)
+}
+
+// precondition enforces If-Match. Requests without it always proceed.
+func (s *Server) precondition(w http.ResponseWriter, r *http.Request, id todo.TodoID) bool {
+	want := r.Header.Get("If-Match")
+	if want == "" {
+		return true
+	}
+	cur := s.Get.Execute(r.Context(), id)
+	if cur.Err != nil {
+		s.fail(w, cur.Err)
+		return false
+	}
+	if tag := ETag(cur.Value); !matchETag(want, tag) {
+		w.Header().Set("ETag", tag)
+		writeError(w, http.StatusPreconditionFailed, "todo was modified (ETag mismatch)")
+		return false
+	}
+	return true
+}
+
+func (s *Server) writeResult(w http.ResponseWriter, res result.Result[todo.Todo]) {
+	if res.Err != nil {
+		s.fail(w, res.Err)
+		return
+	}
+	writeTodo(w, http.StatusOK, queries.ToDTO(res.Value))
+}
+
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	code := StatusCode(err)
+	if code == http.StatusInternalServerError && s.Logger != nil {
+		s.Logger.Printf("httpapi: %v", err)
+	}
+	writeError(w, code, err.Error())
+}
+
+// StatusCode maps application errors to HTTP status codes.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, appErr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, appErr.ErrValidation), errors.Is(err, appErr.ErrVetoed):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, appErr.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ETag is a strong validator over the todo's full representation.
+func ETag(dto queries.TodoDTO) string {
+	b, _ := json.Marshal(dto)
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// matchETag checks an If-Match / If-None-Match header value against tag.
+func matchETag(header, tag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseListSpec reads ListSpec from query parameters:
+// status, tag, project, q, sort, order, limit, offset, deleted and
+// scheduled (include todos that have not started yet). An empty project
+// selects the inbox.
+func ParseListSpec(q map[string][]string) (ports.ListSpec, error) {
+	get := func(k string) string {
+		if v := q[k]; len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+
+	var spec ports.ListSpec
+	if v := get("status"); v != "" {
+		st := todo.Status(v)
+		if !st.Valid() {
+			return spec, fmt.Errorf("invalid status %q", v)
+		}
+		spec.Status = &st
+	}
+	if v := get("tag"); v != "" {
+		spec.Tag = &v
+	}
+	if _, ok := q["project"]; ok {
+		p, err := todo.NewProject(get("project"))
+		if err != nil {
+			return spec, fmt.Errorf("invalid project %q", get("project"))
+		}
+		spec.Project = &p
+	}
+	if v := get("q"); v != "" {
+		spec.Search = &v
+	}
+
+	if f := ports.SortField(get("sort")); f != "" {
+		if !f.Valid() {
+			return spec, fmt.Errorf("invalid sort %q", f)
+		}
+		spec.SortBy = f
+	}
+	if o := ports.SortOrder(get("order")); o != "" {
+		if !o.Valid() {
+			return spec, fmt.Errorf("invalid order %q", o)
+		}
+		spec.SortOrder = o
+	}
+
+	for k, dst := range map[string]*int{"limit": &spec.Limit, "offset": &spec.Offset} {
+		if v := get(k); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return spec, fmt.Errorf("invalid %s %q", k, v)
+			}
+			*dst = n
+		}
+	}
+	for k, dst := range map[string]*bool{"deleted": &spec.IncludeDeleted, "scheduled": &spec.IncludeUnstarted} {
+		if v := get(k); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return spec, fmt.Errorf("invalid %s %q", k, v)
+			}
+			*dst = b
+		}
+	}
+	return spec, nil
+}
+
+func decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeTodo(w http.ResponseWriter, code int, dto queries.TodoDTO) {
+	w.Header().Set("ETag", ETag(dto))
+	writeJSON(w, code, dto)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
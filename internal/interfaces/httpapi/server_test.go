@@ -0,0 +1,262 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/events"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("t%d", s.n))
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	repo := jsonstore.NewRepository(filepath.Join(t.TempDir(), "todos.json"))
+	clk := fixedClock{time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}
+	pub := events.NewBroker(4)
+
+	s := &Server{
+		Add:        commands.AddTodo{Repo: repo, Clock: clk, IDGen: &seqIDs{}, Publisher: pub},
+		Edit:       commands.EditTodo{Repo: repo, Clock: clk, Publisher: pub},
+		Complete:   commands.CompleteTodo{Repo: repo, Clock: clk, Publisher: pub},
+		Reopen:     commands.ReopenTodo{Repo: repo, Clock: clk, Publisher: pub},
+		Archive:    commands.ArchiveTodo{Repo: repo, Clock: clk, Publisher: pub},
+		Restore:    commands.RestoreTodo{Repo: repo, Clock: clk, Publisher: pub},
+		Delete:     commands.SoftDeleteTodo{Repo: repo, Clock: clk, Publisher: pub},
+		HardDelete: commands.HardDeleteTodo{Repo: repo},
+		List:       queries.ListTodos{Repo: repo},
+		Get:        queries.GetTodo{Repo: repo},
+		Stats:      queries.Stats{Repo: repo, Clock: clk},
+		Projects:   queries.ListProjects{Repo: repo, Projects: repo, Clock: clk},
+		Events:     pub,
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string, header ...string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func TestServer_CRUDAndActions(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, created := do(t, ts, "POST", "/api/todos", `{"title":"Write docs","priority":"high","tags":["Work"],"dueDate":"2026-10-20"}`)
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Location") != "/api/todos/t1" {
+		t.Fatalf("create status=%d location=%q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if created["priority"] != "high" || created["dueDate"] != "2026-10-20" {
+		t.Fatalf("created=%v", created)
+	}
+
+	resp, edited := do(t, ts, "PATCH", "/api/todos/t1", `{"title":"Write more docs","dueDate":null}`)
+	if resp.StatusCode != http.StatusOK || edited["title"] != "Write more docs" || edited["dueDate"] != nil {
+		t.Fatalf("edit status=%d body=%v", resp.StatusCode, edited)
+	}
+	// relative dates are resolved against the clock and echoed back
+	if resp, edited := do(t, ts, "PATCH", "/api/todos/t1", `{"dueDate":"next fri"}`); edited["dueDate"] != "2026-10-30" {
+		t.Fatalf("relative due status=%d body=%v", resp.StatusCode, edited)
+	}
+	if resp, _ := do(t, ts, "PATCH", "/api/todos/t1", `{"dueDate":"someday"}`); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("bad due status=%d", resp.StatusCode)
+	}
+
+	for _, step := range []struct{ action, status string }{
+		{"complete", "done"}, {"reopen", "active"}, {"complete", "done"}, {"archive", "archived"}, {"restore", "active"},
+	} {
+		resp, got := do(t, ts, "POST", "/api/todos/t1/"+step.action, "")
+		if resp.StatusCode != http.StatusOK || got["status"] != step.status {
+			t.Fatalf("%s status=%d body=%v", step.action, resp.StatusCode, got)
+		}
+	}
+
+	resp, stats := do(t, ts, "GET", "/api/stats", "")
+	if resp.StatusCode != http.StatusOK || stats["active"] != float64(1) {
+		t.Fatalf("stats=%v", stats)
+	}
+
+	if resp, _ := do(t, ts, "DELETE", "/api/todos/t1", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete status=%d", resp.StatusCode)
+	}
+	if resp, _ := do(t, ts, "DELETE", "/api/todos/t1?hard=true", ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("hard delete status=%d", resp.StatusCode)
+	}
+}
+
+func TestServer_ErrorMapping(t *testing.T) {
+	ts := newTestServer(t)
+	do(t, ts, "POST", "/api/todos", `{"title":"Once"}`)
+
+	tests := []struct {
+		method, path, body string
+		want               int
+	}{
+		{"GET", "/api/todos/nope", "", http.StatusNotFound},
+		{"POST", "/api/todos", `{"title":""}`, http.StatusUnprocessableEntity},
+		{"POST", "/api/todos", `{"title":"x","colour":"red"}`, http.StatusBadRequest},
+		{"POST", "/api/todos/t1/archive", "", http.StatusUnprocessableEntity}, // only done todos archive
+		{"PATCH", "/api/todos/t1", `{"priority":"urgent"}`, http.StatusUnprocessableEntity},
+		{"GET", "/api/todos?status=bogus", "", http.StatusBadRequest},
+		{"GET", "/api/nothing", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, body := do(t, ts, tt.method, tt.path, tt.body)
+		if resp.StatusCode != tt.want || body["error"] == nil {
+			t.Fatalf("%s %s status=%d want=%d body=%v", tt.method, tt.path, resp.StatusCode, tt.want, body)
+		}
+	}
+}
+
+func TestServer_ConditionalRequests(t *testing.T) {
+	ts := newTestServer(t)
+	resp, _ := do(t, ts, "POST", "/api/todos", `{"title":"Shared"}`)
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatalf("no ETag on create")
+	}
+
+	if resp, _ := do(t, ts, "GET", "/api/todos/t1", "", "If-None-Match", etag); resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("If-None-Match status=%d want=304", resp.StatusCode)
+	}
+
+	resp, _ = do(t, ts, "PATCH", "/api/todos/t1", `{"title":"Mine"}`, "If-Match", etag)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Fatalf("first write status=%d etag=%q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+
+	// a second client still holding the old ETag loses
+	resp, _ = do(t, ts, "POST", "/api/todos/t1/complete", "", "If-Match", etag)
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("stale write status=%d want=412", resp.StatusCode)
+	}
+}
+
+func TestParseListSpec(t *testing.T) {
+	spec, err := ParseListSpec(map[string][]string{
+		"status": {"done"}, "tag": {"work"}, "q": {"docs"}, "sort": {"due"},
+		"order": {"desc"}, "limit": {"10"}, "offset": {"5"}, "deleted": {"true"},
+	})
+	if err != nil {
+		t.Fatalf("ParseListSpec err=%v", err)
+	}
+	if *spec.Status != todo.StatusDone || *spec.Tag != "work" || *spec.Search != "docs" ||
+		spec.SortBy != "due" || spec.SortOrder != "desc" || spec.Limit != 10 || spec.Offset != 5 || !spec.IncludeDeleted {
+		t.Fatalf("spec=%+v", spec)
+	}
+
+	for _, bad := range []map[string][]string{
+		{"sort": {"random"}}, {"order": {"up"}}, {"limit": {"-1"}}, {"deleted": {"maybe"}},
+	} {
+		if _, err := ParseListSpec(bad); err == nil {
+			t.Fatalf("ParseListSpec(%v) err=nil", bad)
+		}
+	}
+}
+
+func TestOpenAPIDocumentIsValidJSON(t *testing.T) {
+	var doc struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPI, &doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	if doc.OpenAPI == "" || doc.Paths["/api/todos/{id}"] == nil {
+		t.Fatalf("doc=%+v", doc)
+	}
+}
+
+func TestServer_Projects(t *testing.T) {
+	ts := newTestServer(t)
+
+	do(t, ts, "POST", "/api/todos", `{"title":"Inbox item"}`)
+	resp, created := do(t, ts, "POST", "/api/todos", `{"title":"Report","project":"Work"}`)
+	if resp.StatusCode != http.StatusCreated || created["project"] != "work" {
+		t.Fatalf("create status=%d body=%v", resp.StatusCode, created)
+	}
+	if resp, _ := do(t, ts, "POST", "/api/todos", `{"title":"Bad","project":"no spaces"}`); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("bad project status=%d", resp.StatusCode)
+	}
+
+	list := func(query string) []string {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + "/api/todos?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var todos []queries.TodoDTO
+		if err := json.NewDecoder(resp.Body).Decode(&todos); err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, td := range todos {
+			out = append(out, td.Title)
+		}
+		return out
+	}
+	if got := list("project=work"); len(got) != 1 || got[0] != "Report" {
+		t.Fatalf("work=%v", got)
+	}
+	if got := list("project="); len(got) != 1 || got[0] != "Inbox item" {
+		t.Fatalf("inbox=%v", got)
+	}
+
+	// moving to the inbox
+	if resp, moved := do(t, ts, "PATCH", "/api/todos/t2", `{"project":""}`); resp.StatusCode != http.StatusOK || moved["project"] != nil {
+		t.Fatalf("move status=%d body=%v", resp.StatusCode, moved)
+	}
+
+	resp, err := ts.Client().Get(ts.URL + "/api/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var projects []queries.ProjectDTO
+	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+		t.Fatal(err)
+	}
+	// "work" is no longer in use and was never registered
+	if len(projects) != 1 || projects[0].Name != "" || projects[0].Active != 2 {
+		t.Fatalf("projects=%+v", projects)
+	}
+}
@@ -0,0 +1,377 @@
+// Package jsonrpc is a small JSON-RPC 2.0 server for stdio transports.
+//
+// Messages are framed either one per line or with LSP-style
+// Content-Length headers; the framing is detected from the first message
+// and used for replies. Requests run concurrently, each with a context
+// that is cancelled by "$/cancelRequest" or when the connection ends.
+// A batch is answered with one array once all of its requests are done.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const Version = "2.0"
+
+// Standard error codes, plus LSP's RequestCancelled.
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeRequestCancelled = -32800
+)
+
+// CancelMethod is the notification that cancels an in-flight request.
+const CancelMethod = "$/cancelRequest"
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("jsonrpc %d: %s", e.Code, e.Message) }
+
+// Errorf builds an *Error that handlers can return as-is.
+func Errorf(code int, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// Handler serves one method. Returning an *Error sends it unchanged; any
+// other error goes through Server.MapError.
+type Handler func(ctx context.Context, params json.RawMessage) (any, error)
+
+type Server struct {
+	// MapError turns handler errors into protocol errors. Default:
+	// CodeInternalError with the error text.
+	MapError func(error) *Error
+
+	methods map[string]Handler
+
+	mu       sync.Mutex // guards the fields below and serializes writes
+	w        io.Writer
+	headers  bool // Content-Length framing
+	inFlight map[string]context.CancelFunc
+}
+
+func NewServer() *Server {
+	return &Server{methods: map[string]Handler{}, inFlight: map[string]context.CancelFunc{}}
+}
+
+// Register adds a method. Not safe to call once Serve has started.
+func (s *Server) Register(method string, h Handler) { s.methods[method] = h }
+
+// Notify sends a server-to-client notification. It is a no-op before
+// Serve has started.
+func (s *Server) Notify(method string, params any) error {
+	return s.write(notification{JSONRPC: Version, Method: method, Params: params})
+}
+
+// Serve reads requests from r until EOF or ctx ends and writes replies to
+// w. It waits for running handlers before returning.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	br := bufio.NewReader(r)
+	headers, err := detectHeaders(br)
+	if err != nil {
+		return ignoreEOF(err)
+	}
+	s.mu.Lock()
+	s.w, s.headers = w, headers
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	msgs := make(chan []byte)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := readMessage(br, headers)
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errc:
+			return ignoreEOF(err)
+		case msg := <-msgs:
+			s.dispatch(ctx, msg, &wg)
+		}
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, msg []byte, wg *sync.WaitGroup) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 {
+		return
+	}
+	if msg[0] == '[' {
+		s.batch(ctx, msg, wg)
+		return
+	}
+	s.handle(ctx, msg, wg, func(r response) { _ = s.write(r) })
+}
+
+// batch runs every request of a batch and sends their replies together
+// as one array, or nothing when all of them are notifications.
+func (s *Server) batch(ctx context.Context, msg []byte, wg *sync.WaitGroup) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil {
+		_ = s.write(newResponse(nil, nil, Errorf(CodeParseError, "parse error: %v", err)))
+		return
+	}
+	if len(batch) == 0 {
+		_ = s.write(newResponse(nil, nil, Errorf(CodeInvalidRequest, "empty batch")))
+		return
+	}
+
+	var (
+		mu      sync.Mutex
+		replies []response
+		running sync.WaitGroup
+	)
+	collect := func(r response) {
+		mu.Lock()
+		defer mu.Unlock()
+		replies = append(replies, r)
+	}
+	for _, m := range batch {
+		s.handle(ctx, m, &running, collect)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		running.Wait()
+		if len(replies) > 0 {
+			_ = s.write(replies)
+		}
+	}()
+}
+
+// handle runs one request, passing its reply to send; notifications get
+// none. Handlers run on wg.
+func (s *Server) handle(ctx context.Context, msg []byte, wg *sync.WaitGroup, send func(response)) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		if json.Valid(msg) {
+			send(newResponse(nil, nil, Errorf(CodeInvalidRequest, "invalid request")))
+		} else {
+			send(newResponse(nil, nil, Errorf(CodeParseError, "parse error: %v", err)))
+		}
+		return
+	}
+	if req.JSONRPC != Version || req.Method == "" {
+		send(newResponse(req.ID, nil, Errorf(CodeInvalidRequest, "invalid request")))
+		return
+	}
+
+	if req.Method == CancelMethod {
+		var p struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(req.Params, &p) == nil {
+			s.cancel(p.ID)
+		}
+		return
+	}
+
+	h, ok := s.methods[req.Method]
+	if !ok {
+		if req.ID != nil {
+			send(newResponse(req.ID, nil, Errorf(CodeMethodNotFound, "method not found: %s", req.Method)))
+		}
+		return
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	key := string(req.ID)
+	if req.ID != nil {
+		s.mu.Lock()
+		s.inFlight[key] = cancel
+		s.mu.Unlock()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+
+		res, err := call(h, rctx, req.Params)
+
+		if req.ID == nil {
+			return // notification: no reply
+		}
+		s.mu.Lock()
+		delete(s.inFlight, key)
+		s.mu.Unlock()
+
+		send(newResponse(req.ID, res, s.toError(err)))
+	}()
+}
+
+// call runs h, turning a panic into an internal error so one bad request
+// doesn't take the editor's connection down.
+func call(h Handler, ctx context.Context, params json.RawMessage) (res any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			res, err = nil, Errorf(CodeInternalError, "panic: %v", p)
+		}
+	}()
+	return h(ctx, params)
+}
+
+func (s *Server) cancel(id json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inFlight[string(bytes.TrimSpace(id))]; ok {
+		cancel()
+	}
+}
+
+func (s *Server) toError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	if errors.Is(err, context.Canceled) {
+		return Errorf(CodeRequestCancelled, "request cancelled")
+	}
+	if s.MapError != nil {
+		if e := s.MapError(err); e != nil {
+			return e
+		}
+	}
+	return Errorf(CodeInternalError, "%v", err)
+}
+
+func newResponse(id json.RawMessage, result any, rpcErr *Error) response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	resp := response{JSONRPC: Version, ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		if result == nil {
+			result = struct{}{} // "result" is required on success
+		}
+		resp.Result = result
+	}
+	return resp
+}
+
+func (s *Server) write(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == nil {
+		return nil
+	}
+	if s.headers {
+		_, err = fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	} else {
+		_, err = fmt.Fprintf(s.w, "%s\n", b)
+	}
+	return err
+}
+
+// detectHeaders peeks past leading whitespace for a "Content-Length" header.
+func detectHeaders(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return false, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		_, _ = br.ReadByte()
+	}
+	b, _ := br.Peek(len("content-length"))
+	return strings.EqualFold(string(b), "content-length"), nil
+}
+
+func readMessage(br *bufio.Reader, headers bool) ([]byte, error) {
+	if !headers {
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				return line, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	h, err := textproto.NewReader(br).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(h.Get("Content-Length"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("jsonrpc: bad Content-Length %q", h.Get("Content-Length"))
+	}
+	buf := make([]byte, n)
+	_, err = io.ReadFull(br, buf)
+	return buf, err
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
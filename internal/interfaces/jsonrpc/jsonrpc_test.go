@@ -0,0 +1,173 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func echoServer() *Server {
+	s := NewServer()
+	s.Register("echo", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return params, nil
+	})
+	s.Register("fail", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+	s.Register("wait", func(ctx context.Context, params json.RawMessage) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	s.MapError = func(err error) *Error { return &Error{Code: -32000, Message: "mapped: " + err.Error()} }
+	return s
+}
+
+type reply struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+	Method string          `json:"method"`
+}
+
+func replies(t *testing.T, out string) map[string]reply {
+	t.Helper()
+	got := map[string]reply{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var r reply
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("bad reply %q: %v", line, err)
+		}
+		got[string(r.ID)] = r
+	}
+	return got
+}
+
+func TestServe_LineFraming(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"a":1}}`,
+		`{"jsonrpc":"2.0","id":"two","method":"missing"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"fail"}`,
+		`{"jsonrpc":"2.0","method":"echo"}`,
+		`not json`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := echoServer().Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve err=%v", err)
+	}
+	got := replies(t, out.String())
+
+	if len(got) != 4 {
+		t.Fatalf("got %d replies want=4 (notification must not be answered):\n%s", len(got), out.String())
+	}
+	if string(got["1"].Result) != `{"a":1}` {
+		t.Fatalf("echo=%s", got["1"].Result)
+	}
+	if got[`"two"`].Error.Code != CodeMethodNotFound {
+		t.Fatalf("missing=%+v", got[`"two"`].Error)
+	}
+	if e := got["3"].Error; e.Code != -32000 || e.Message != "mapped: boom" {
+		t.Fatalf("fail=%+v", e)
+	}
+	if got["null"].Error.Code != CodeParseError {
+		t.Fatalf("parse error=%+v", got["null"])
+	}
+}
+
+func TestServe_Batch(t *testing.T) {
+	in := strings.Join([]string{
+		`[{"jsonrpc":"2.0","id":4,"method":"echo","params":[4]},{"jsonrpc":"1.0","id":5,"method":"echo"},{"jsonrpc":"2.0","method":"echo"},1]`,
+		`[{"jsonrpc":"2.0","method":"echo"},{"jsonrpc":"2.0","method":"fail"}]`,
+		`[]`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := echoServer().Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve err=%v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want one array and one error (notification-only batches get nothing):\n%s", out.String())
+	}
+
+	// replies go out as they finish, so tell the two apart by shape
+	if strings.HasPrefix(lines[0], "{") {
+		lines[0], lines[1] = lines[1], lines[0]
+	}
+	var batch []reply
+	if err := json.Unmarshal([]byte(lines[0]), &batch); err != nil || len(batch) != 3 {
+		t.Fatalf("batch reply %q: %v", lines[0], err)
+	}
+	got := map[string]reply{}
+	for _, r := range batch {
+		got[string(r.ID)] = r
+	}
+	if string(got["4"].Result) != `[4]` || got["5"].Error.Code != CodeInvalidRequest || got["null"].Error.Code != CodeInvalidRequest {
+		t.Fatalf("batch=%+v", got)
+	}
+
+	var empty reply
+	if err := json.Unmarshal([]byte(lines[1]), &empty); err != nil || empty.Error == nil || empty.Error.Code != CodeInvalidRequest {
+		t.Fatalf("empty batch reply %q", lines[1])
+	}
+}
+
+func TestServe_HeaderFraming(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":"hi"}`
+	in := "Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body
+
+	var out bytes.Buffer
+	if err := echoServer().Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve err=%v", err)
+	}
+	head, payload, ok := strings.Cut(out.String(), "\r\n\r\n")
+	if !ok || !strings.HasPrefix(head, "Content-Length: ") || head[len("Content-Length: "):] != strconv.Itoa(len(payload)) {
+		t.Fatalf("out=%q", out.String())
+	}
+	if !strings.Contains(payload, `"result":"hi"`) {
+		t.Fatalf("payload=%s", payload)
+	}
+}
+
+func TestServe_CancelRequest(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := echoServer()
+
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(context.Background(), inR, outW); outW.Close() }()
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":7,"method":"wait"}`+"\n")
+	time.Sleep(20 * time.Millisecond) // let the handler start
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":7}}`+"\n")
+
+	rd := bufio.NewReader(outR)
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read err=%v", err)
+	}
+	var r reply
+	_ = json.Unmarshal([]byte(line), &r)
+	if string(r.ID) != "7" || r.Error == nil || r.Error.Code != CodeRequestCancelled {
+		t.Fatalf("reply=%s", line)
+	}
+
+	// notifications flow once serving
+	go s.Notify("ping", map[string]int{"n": 1})
+	line, _ = rd.ReadString('\n')
+	if !strings.Contains(line, `"method":"ping"`) {
+		t.Fatalf("notification=%s", line)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve err=%v", err)
+	}
+}
@@ -0,0 +1,492 @@
+// Package mcp serves the todo list over the Model Context Protocol (stdio
+// transport) so AI assistants can read and manage it.
+//
+// Tools wrap the use cases; resources expose single todos as
+// todo://todos/{id} and saved filters as todo://filters/{name}.
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/jsonrpc"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/rpcapi"
+)
+
+// ProtocolVersions are the MCP revisions understood, newest first.
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const (
+	todoURIPrefix   = "todo://todos/"
+	filterURIPrefix = "todo://filters/"
+)
+
+// DefaultFilters are available even without a filters file.
+var DefaultFilters = map[string]rpcapi.ListParams{
+	"active":   {Status: string(todo.StatusActive), Sort: "due"},
+	"done":     {Status: string(todo.StatusDone), Sort: "updated", Order: "desc"},
+	"archived": {Status: string(todo.StatusArchived)},
+	"all":      {},
+}
+
+type Server struct {
+	// Commands (unused in read-only mode)
+	Add      commands.AddTodo
+	Edit     commands.EditTodo
+	Complete commands.CompleteTodo
+
+	// Queries
+	List     queries.ListTodos
+	Get      queries.GetTodo
+	Stats    queries.Stats
+	Projects queries.ListProjects // optional; enables list_projects
+
+	// ReadOnly hides and refuses every tool that changes the list.
+	ReadOnly bool
+	// Filters are saved list queries exposed as resources; nil means
+	// DefaultFilters.
+	Filters map[string]rpcapi.ListParams
+
+	Version string // reported as serverInfo.version
+
+	once sync.Once
+	rpc  *jsonrpc.Server
+}
+
+// Serve answers requests on r/w until EOF or ctx ends.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.once.Do(s.register)
+	return s.rpc.Serve(ctx, r, w)
+}
+
+func (s *Server) register() {
+	s.rpc = jsonrpc.NewServer()
+	s.rpc.Register("initialize", s.initialize)
+	s.rpc.Register("notifications/initialized", func(context.Context, json.RawMessage) (any, error) { return nil, nil })
+	s.rpc.Register("ping", func(context.Context, json.RawMessage) (any, error) { return struct{}{}, nil })
+	s.rpc.Register("tools/list", s.listTools)
+	s.rpc.Register("tools/call", s.callTool)
+	s.rpc.Register("resources/list", s.listResources)
+	s.rpc.Register("resources/templates/list", s.listTemplates)
+	s.rpc.Register("resources/read", s.readResource)
+}
+
+func (s *Server) initialize(ctx context.Context, raw json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(raw, &p)
+
+	version := ProtocolVersions[0]
+	if slices.Contains(ProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+
+	instructions := "Manage the user's gotodo task list. Use list_todos to find todo IDs before changing them."
+	if s.ReadOnly {
+		instructions = "Read the user's gotodo task list. This server is read-only."
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools":     map[string]any{},
+			"resources": map[string]any{},
+		},
+		"serverInfo":   map[string]any{"name": "gotodo", "version": s.version()},
+		"instructions": instructions,
+	}, nil
+}
+
+func (s *Server) version() string {
+	if s.Version != "" {
+		return s.Version
+	}
+	return "dev"
+}
+
+// ---- tools ----
+
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Annotations map[string]any `json:"annotations,omitempty"`
+
+	readOnly bool
+	run      func(s *Server, ctx context.Context, args json.RawMessage) (any, error)
+}
+
+func object(props map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var (
+	str      = map[string]any{"type": "string"}
+	priority = map[string]any{"type": "string", "enum": []string{"low", "medium", "high"}}
+	tags     = map[string]any{"type": "array", "items": str}
+	dueDate  = map[string]any{"type": "string", "description": "YYYY-MM-DD or YYYY-MM-DDTHH:MM, or relative: today, tomorrow, fri 17:00, next fri, in 3 days, +2w, eow, eom"}
+	project  = map[string]any{"type": "string", "description": `project name, e.g. "work"; "" is the inbox`}
+)
+
+var tools = []tool{
+	{
+		Name:        "list_todos",
+		Description: "List todos, optionally filtered by status, tag, project or a title search. Todos scheduled to start later are left out unless scheduled is true.",
+		InputSchema: object(map[string]any{
+			"status":    map[string]any{"type": "string", "enum": []string{"active", "done", "archived"}},
+			"tag":       str,
+			"project":   project,
+			"q":         map[string]any{"type": "string", "description": "case-insensitive title search"},
+			"sort":      map[string]any{"type": "string", "enum": []string{"created", "due", "priority", "title", "updated"}},
+			"order":     map[string]any{"type": "string", "enum": []string{"asc", "desc"}},
+			"limit":     map[string]any{"type": "integer", "minimum": 0},
+			"scheduled": map[string]any{"type": "boolean", "description": "include todos that have not started yet"},
+		}),
+		readOnly: true,
+		run: func(s *Server, ctx context.Context, args json.RawMessage) (any, error) {
+			var p rpcapi.ListParams
+			if err := decode(args, &p); err != nil {
+				return nil, err
+			}
+			return s.list(ctx, p)
+		},
+	},
+	{
+		Name:        "get_stats",
+		Description: "Count todos by status, and active todos that are overdue, due today, due within a week, scheduled to start today or snoozed.",
+		InputSchema: object(map[string]any{}),
+		readOnly:    true,
+		run: func(s *Server, ctx context.Context, _ json.RawMessage) (any, error) {
+			res := s.Stats.Execute(ctx)
+			return res.Value, res.Err
+		},
+	},
+	{
+		Name:        "list_projects",
+		Description: "List projects (the inbox first, as \"\") with counts of active, done, archived and overdue todos.",
+		InputSchema: object(map[string]any{}),
+		readOnly:    true,
+		run: func(s *Server, ctx context.Context, _ json.RawMessage) (any, error) {
+			if s.Projects.Projects == nil {
+				return nil, errors.New("projects are not available")
+			}
+			res := s.Projects.Execute(ctx)
+			return res.Value, res.Err
+		},
+	},
+	{
+		Name:        "add_todo",
+		Description: "Create a todo.",
+		InputSchema: object(map[string]any{
+			"title": str, "priority": priority, "tags": tags, "dueDate": dueDate, "project": project,
+		}, "title"),
+		run: func(s *Server, ctx context.Context, args json.RawMessage) (any, error) {
+			var p struct {
+				Title    string   `json:"title"`
+				Priority string   `json:"priority"`
+				Tags     []string `json:"tags"`
+				DueDate  *string  `json:"dueDate"`
+				Project  string   `json:"project"`
+			}
+			if err := decode(args, &p); err != nil {
+				return nil, err
+			}
+			return dto(s.Add.Execute(ctx, commands.AddTodoInput{
+				Title: p.Title, Priority: p.Priority, Tags: p.Tags, DueDate: p.DueDate, Project: p.Project,
+			}))
+		},
+	},
+	{
+		Name:        "complete_todo",
+		Description: "Mark a todo as done.",
+		InputSchema: object(map[string]any{"id": str}, "id"),
+		run: func(s *Server, ctx context.Context, args json.RawMessage) (any, error) {
+			var p struct {
+				ID todo.TodoID `json:"id"`
+			}
+			if err := decode(args, &p); err != nil {
+				return nil, err
+			}
+			return dto(s.Complete.Execute(ctx, p.ID))
+		},
+	},
+	{
+		Name:        "edit_todo",
+		Description: "Change a todo's title, priority, tags, due date, scheduled start, reminder, estimate or project. Omitted fields are unchanged; null clears a date and \"\" an estimate. Scheduling a todo later (e.g. \"+3d\") snoozes it: it is hidden from lists until then.",
+		InputSchema: object(map[string]any{
+			"id": str, "title": str, "priority": priority, "tags": tags, "project": project,
+			"dueDate":   map[string]any{"type": []string{"string", "null"}, "description": "YYYY-MM-DD or relative (tomorrow, next fri, +2w, eom), or null to clear"},
+			"scheduled": map[string]any{"type": []string{"string", "null"}, "description": "when work can start, written like dueDate, or null to clear"},
+			"remindAt":  map[string]any{"type": []string{"string", "null"}, "description": "when to remind, written like dueDate but with a time (fri 9am, +2h), or null to clear"},
+			"estimate":  map[string]any{"type": "string", "description": "expected effort: a time (90m, 2h) or story points (3pt); \"\" clears it"},
+		}, "id"),
+		run: func(s *Server, ctx context.Context, args json.RawMessage) (any, error) {
+			var p struct {
+				ID       todo.TodoID     `json:"id"`
+				Title    *string         `json:"title"`
+				Priority *string         `json:"priority"`
+				Tags     *[]string       `json:"tags"`
+				DueDate  json.RawMessage `json:"dueDate"`
+				Project  *string         `json:"project"`
+
+				Scheduled json.RawMessage `json:"scheduled"`
+				RemindAt  json.RawMessage `json:"remindAt"`
+				Estimate  *string         `json:"estimate"`
+			}
+			if err := decode(args, &p); err != nil {
+				return nil, err
+			}
+			in := commands.EditTodoInput{ID: p.ID, Title: p.Title, Priority: p.Priority, Tags: p.Tags, Project: p.Project, Estimate: p.Estimate}
+			if p.DueDate != nil {
+				var due *string
+				if err := json.Unmarshal(p.DueDate, &due); err != nil {
+					return nil, fmt.Errorf("dueDate: %w", err)
+				}
+				in.DueDate = &due
+			}
+			if p.Scheduled != nil {
+				var when *string
+				if err := json.Unmarshal(p.Scheduled, &when); err != nil {
+					return nil, fmt.Errorf("scheduled: %w", err)
+				}
+				in.Scheduled = &when
+			}
+			if p.RemindAt != nil {
+				var when *string
+				if err := json.Unmarshal(p.RemindAt, &when); err != nil {
+					return nil, fmt.Errorf("remindAt: %w", err)
+				}
+				in.RemindAt = &when
+			}
+			return dto(s.Edit.Execute(ctx, in))
+		},
+	},
+}
+
+func init() {
+	for i := range tools {
+		tools[i].Annotations = map[string]any{"readOnlyHint": tools[i].readOnly}
+	}
+}
+
+func (s *Server) available() []tool {
+	if !s.ReadOnly {
+		return tools
+	}
+	var out []tool
+	for _, t := range tools {
+		if t.readOnly {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (s *Server) listTools(ctx context.Context, _ json.RawMessage) (any, error) {
+	return map[string]any{"tools": s.available()}, nil
+}
+
+// callTool reports tool failures in the result (isError) rather than as
+// protocol errors, so the model can see and react to them.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+
+	i := slices.IndexFunc(s.available(), func(t tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		if s.ReadOnly && slices.ContainsFunc(tools, func(t tool) bool { return t.Name == p.Name }) {
+			return toolError(fmt.Errorf("%s is disabled: server is read-only", p.Name)), nil
+		}
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "unknown tool %q", p.Name)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, err := s.available()[i].run(s, ctx, p.Arguments)
+	if err != nil {
+		return toolError(err), nil
+	}
+	text, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"content": []any{textContent(string(text))}, "isError": false}, nil
+}
+
+func toolError(err error) map[string]any {
+	return map[string]any{"content": []any{textContent("Error: " + err.Error())}, "isError": true}
+}
+
+func textContent(text string) map[string]any {
+	return map[string]any{"type": "text", "text": text}
+}
+
+// ---- resources ----
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+// LoadFilters reads saved filters ({"name": {"status": ..., "tag": ...}})
+// from path and layers them over DefaultFilters. A missing file is fine.
+func LoadFilters(path string) (map[string]rpcapi.ListParams, error) {
+	out := maps.Clone(DefaultFilters)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return out, nil
+		}
+		return nil, err
+	}
+	var saved map[string]rpcapi.ListParams
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, fmt.Errorf("mcp: %s: %w", path, err)
+	}
+	for name, f := range saved {
+		if _, err := f.Spec(); err != nil {
+			return nil, fmt.Errorf("mcp: %s: filter %q: %w", path, name, err)
+		}
+		out[name] = f
+	}
+	return out, nil
+}
+
+func (s *Server) filters() map[string]rpcapi.ListParams {
+	if s.Filters != nil {
+		return s.Filters
+	}
+	return DefaultFilters
+}
+
+func (s *Server) listResources(ctx context.Context, _ json.RawMessage) (any, error) {
+	var out []resource
+	for _, name := range slices.Sorted(maps.Keys(s.filters())) {
+		out = append(out, resource{
+			URI:         filterURIPrefix + name,
+			Name:        "Filter: " + name,
+			Description: "Todos matching the saved filter " + name,
+			MimeType:    "application/json",
+		})
+	}
+	return map[string]any{"resources": out}, nil
+}
+
+func (s *Server) listTemplates(ctx context.Context, _ json.RawMessage) (any, error) {
+	return map[string]any{"resourceTemplates": []any{
+		map[string]any{
+			"uriTemplate": todoURIPrefix + "{id}",
+			"name":        "Todo",
+			"description": "A single todo by ID",
+			"mimeType":    "application/json",
+		},
+		map[string]any{
+			"uriTemplate": filterURIPrefix + "{name}",
+			"name":        "Saved filter",
+			"description": "Todos matching a saved filter",
+			"mimeType":    "application/json",
+		},
+	}}, nil
+}
+
+func (s *Server) readResource(ctx context.Context, raw json.RawMessage) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+
+	var (
+		out any
+		err error
+	)
+	switch {
+	case strings.HasPrefix(p.URI, todoURIPrefix):
+		res := s.Get.Execute(ctx, todo.TodoID(strings.TrimPrefix(p.URI, todoURIPrefix)))
+		out, err = res.Value, res.Err
+	case strings.HasPrefix(p.URI, filterURIPrefix):
+		f, ok := s.filters()[strings.TrimPrefix(p.URI, filterURIPrefix)]
+		if !ok {
+			return nil, resourceNotFound(p.URI)
+		}
+		out, err = s.list(ctx, f)
+	default:
+		return nil, resourceNotFound(p.URI)
+	}
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil, resourceNotFound(p.URI)
+		}
+		return nil, err
+	}
+
+	text, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"contents": []any{map[string]any{
+		"uri": p.URI, "mimeType": "application/json", "text": string(text),
+	}}}, nil
+}
+
+// resourceNotFound uses the code MCP specifies for unknown resources.
+func resourceNotFound(uri string) error {
+	return &jsonrpc.Error{Code: -32002, Message: "resource not found", Data: map[string]string{"uri": uri}}
+}
+
+// ---- helpers ----
+
+func (s *Server) list(ctx context.Context, p rpcapi.ListParams) (any, error) {
+	spec, err := p.Spec()
+	if err != nil {
+		return nil, err
+	}
+	res := s.List.Execute(ctx, spec)
+	return res.Value, res.Err
+}
+
+func dto(res result.Result[todo.Todo]) (any, error) {
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return queries.ToDTO(res.Value), nil
+}
+
+func decode(raw json.RawMessage, dst any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/rpcapi"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("t%d", s.n))
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, []todo.Event) error { return nil }
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	repo := jsonstore.NewRepository(filepath.Join(t.TempDir(), "todos.json"))
+	clk := fixedClock{time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}
+	return &Server{
+		Add:      commands.AddTodo{Repo: repo, Clock: clk, IDGen: &seqIDs{}, Publisher: noopPublisher{}},
+		Edit:     commands.EditTodo{Repo: repo, Clock: clk, Publisher: noopPublisher{}},
+		Complete: commands.CompleteTodo{Repo: repo, Clock: clk, Publisher: noopPublisher{}},
+		List:     queries.ListTodos{Repo: repo},
+		Get:      queries.GetTodo{Repo: repo},
+		Stats:    queries.Stats{Repo: repo, Clock: clk},
+		Projects: queries.ListProjects{Repo: repo, Projects: repo, Clock: clk},
+	}
+}
+
+type reply struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// call sends one request and decodes its reply.
+func call(t *testing.T, s *Server, method, params string) reply {
+	t.Helper()
+	req := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":%s}`, method, params)
+	var buf bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(req+"\n"), &buf); err != nil {
+		t.Fatalf("Serve err=%v", err)
+	}
+	var r reply
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatalf("bad reply %q: %v", buf.String(), err)
+	}
+	return r
+}
+
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+func callTool(t *testing.T, s *Server, name, args string) toolResult {
+	t.Helper()
+	r := call(t, s, "tools/call", fmt.Sprintf(`{"name":%q,"arguments":%s}`, name, args))
+	if r.Error != nil {
+		t.Fatalf("tools/call %s error=%d", name, r.Error.Code)
+	}
+	var tr toolResult
+	if err := json.Unmarshal(r.Result, &tr); err != nil || len(tr.Content) != 1 {
+		t.Fatalf("tools/call %s result=%s", name, r.Result)
+	}
+	return tr
+}
+
+func TestInitialize(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct{ client, want string }{
+		{"2025-03-26", "2025-03-26"},
+		{"1999-01-01", ProtocolVersions[0]},
+	}
+	for _, tt := range tests {
+		r := call(t, s, "initialize", fmt.Sprintf(`{"protocolVersion":%q,"capabilities":{},"clientInfo":{"name":"test"}}`, tt.client))
+		var res struct {
+			ProtocolVersion string `json:"protocolVersion"`
+			Capabilities    map[string]any
+			ServerInfo      struct{ Name string }
+		}
+		if err := json.Unmarshal(r.Result, &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.ProtocolVersion != tt.want || res.ServerInfo.Name != "gotodo" || res.Capabilities["tools"] == nil {
+			t.Fatalf("client=%s result=%s", tt.client, r.Result)
+		}
+	}
+}
+
+func TestTools(t *testing.T) {
+	s := newTestServer(t)
+
+	tr := callTool(t, s, "add_todo", `{"title":"Write report","tags":["work"],"dueDate":"2026-10-18"}`)
+	var added queries.TodoDTO
+	if tr.IsError || json.Unmarshal([]byte(tr.Content[0].Text), &added) != nil || added.ID != "t1" || added.Priority != "low" {
+		t.Fatalf("add=%+v", tr)
+	}
+	callTool(t, s, "add_todo", `{"title":"Buy milk"}`)
+
+	tr = callTool(t, s, "edit_todo", `{"id":"t1","priority":"high","dueDate":null}`)
+	var edited queries.TodoDTO
+	_ = json.Unmarshal([]byte(tr.Content[0].Text), &edited)
+	if tr.IsError || edited.Priority != "high" || edited.DueDate != nil {
+		t.Fatalf("edit=%+v", tr)
+	}
+
+	if tr = callTool(t, s, "complete_todo", `{"id":"t2"}`); tr.IsError {
+		t.Fatalf("complete=%+v", tr)
+	}
+
+	tr = callTool(t, s, "list_todos", `{"status":"active"}`)
+	var list []queries.TodoDTO
+	_ = json.Unmarshal([]byte(tr.Content[0].Text), &list)
+	if len(list) != 1 || list[0].ID != "t1" {
+		t.Fatalf("list=%s", tr.Content[0].Text)
+	}
+
+	tr = callTool(t, s, "get_stats", `{}`)
+	var stats queries.StatsDTO
+	_ = json.Unmarshal([]byte(tr.Content[0].Text), &stats)
+	if stats.Active != 1 || stats.Done != 1 {
+		t.Fatalf("stats=%s", tr.Content[0].Text)
+	}
+
+	// use-case failures are tool errors, not protocol errors
+	for _, c := range []struct{ name, args string }{
+		{"complete_todo", `{"id":"missing"}`},
+		{"add_todo", `{"title":""}`},
+		{"list_todos", `{"status":"bogus"}`},
+		{"add_todo", `{"title":"x","colour":"red"}`},
+	} {
+		if tr := callTool(t, s, c.name, c.args); !tr.IsError {
+			t.Fatalf("%s %s: isError=false text=%s", c.name, c.args, tr.Content[0].Text)
+		}
+	}
+
+	if r := call(t, s, "tools/call", `{"name":"nope","arguments":{}}`); r.Error == nil {
+		t.Fatalf("unknown tool: want protocol error, got %s", r.Result)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	s := newTestServer(t)
+	s.ReadOnly = true
+
+	r := call(t, s, "tools/list", `{}`)
+	var res struct {
+		Tools []struct{ Name string }
+	}
+	_ = json.Unmarshal(r.Result, &res)
+	var names []string
+	for _, tl := range res.Tools {
+		names = append(names, tl.Name)
+	}
+	if got := strings.Join(names, ","); got != "list_todos,get_stats,list_projects" {
+		t.Fatalf("tools=%s", got)
+	}
+
+	if tr := callTool(t, s, "add_todo", `{"title":"x"}`); !tr.IsError || !strings.Contains(tr.Content[0].Text, "read-only") {
+		t.Fatalf("add in read-only=%+v", tr)
+	}
+	var list []queries.TodoDTO
+	tr := callTool(t, s, "list_todos", `{}`)
+	if _ = json.Unmarshal([]byte(tr.Content[0].Text), &list); len(list) != 0 {
+		t.Fatalf("read-only add created %v", list)
+	}
+}
+
+func TestResources(t *testing.T) {
+	s := newTestServer(t)
+	s.Filters = map[string]rpcapi.ListParams{"work": {Tag: "work"}}
+	callTool(t, s, "add_todo", `{"title":"Write report","tags":["work"]}`)
+	callTool(t, s, "add_todo", `{"title":"Buy milk"}`)
+
+	r := call(t, s, "resources/list", `{}`)
+	if !strings.Contains(string(r.Result), `"uri":"todo://filters/work"`) {
+		t.Fatalf("resources/list=%s", r.Result)
+	}
+	r = call(t, s, "resources/templates/list", `{}`)
+	if !strings.Contains(string(r.Result), `todo://todos/{id}`) {
+		t.Fatalf("templates=%s", r.Result)
+	}
+
+	read := func(uri string) (string, int) {
+		r := call(t, s, "resources/read", fmt.Sprintf(`{"uri":%q}`, uri))
+		if r.Error != nil {
+			return "", r.Error.Code
+		}
+		var res struct {
+			Contents []struct{ URI, MimeType, Text string }
+		}
+		_ = json.Unmarshal(r.Result, &res)
+		if len(res.Contents) != 1 || res.Contents[0].URI != uri || res.Contents[0].MimeType != "application/json" {
+			t.Fatalf("read %s=%s", uri, r.Result)
+		}
+		return res.Contents[0].Text, 0
+	}
+
+	text, _ := read("todo://todos/t2")
+	var one queries.TodoDTO
+	if _ = json.Unmarshal([]byte(text), &one); one.Title != "Buy milk" {
+		t.Fatalf("todo=%s", text)
+	}
+	text, _ = read("todo://filters/work")
+	var list []queries.TodoDTO
+	if _ = json.Unmarshal([]byte(text), &list); len(list) != 1 || list[0].ID != "t1" {
+		t.Fatalf("filter=%s", text)
+	}
+
+	for _, uri := range []string{"todo://todos/missing", "todo://filters/active", "https://example.com"} {
+		if _, code := read(uri); code != -32002 {
+			t.Fatalf("read %s code=%d want=-32002", uri, code)
+		}
+	}
+}
+
+func TestLoadFilters(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "filters.json")
+
+	got, err := LoadFilters(path)
+	if err != nil || len(got) != len(DefaultFilters) {
+		t.Fatalf("missing file: got=%v err=%v", got, err)
+	}
+
+	_ = os.WriteFile(path, []byte(`{"urgent":{"status":"active","sort":"priority","order":"desc"}}`), 0o600)
+	got, err = LoadFilters(path)
+	if err != nil || got["urgent"].Sort != "priority" || got["active"].Status != "active" {
+		t.Fatalf("got=%v err=%v", got, err)
+	}
+
+	_ = os.WriteFile(path, []byte(`{"bad":{"status":"someday"}}`), 0o600)
+	if _, err := LoadFilters(path); err == nil {
+		t.Fatalf("invalid filter: want error")
+	}
+}
+
+func TestProjects(t *testing.T) {
+	s := newTestServer(t)
+
+	callTool(t, s, "add_todo", `{"title":"Inbox item"}`)
+	if tr := callTool(t, s, "add_todo", `{"title":"Report","project":"work"}`); tr.IsError {
+		t.Fatalf("add=%+v", tr)
+	}
+	if tr := callTool(t, s, "edit_todo", `{"id":"t1","project":"home"}`); tr.IsError || !strings.Contains(tr.Content[0].Text, `"project": "home"`) {
+		t.Fatalf("edit=%+v", tr)
+	}
+
+	var list []queries.TodoDTO
+	tr := callTool(t, s, "list_todos", `{"project":"work"}`)
+	if _ = json.Unmarshal([]byte(tr.Content[0].Text), &list); len(list) != 1 || list[0].Title != "Report" {
+		t.Fatalf("list=%+v", tr)
+	}
+
+	var projects []queries.ProjectDTO
+	tr = callTool(t, s, "list_projects", `{}`)
+	if _ = json.Unmarshal([]byte(tr.Content[0].Text), &projects); len(projects) != 3 || projects[1].Name != "home" || projects[2].Active != 1 {
+		t.Fatalf("projects=%+v", tr)
+	}
+}
@@ -0,0 +1,346 @@
+// Package rpcapi exposes the use cases as JSON-RPC methods for editor
+// plugins. Methods are named "todo.<use case>" and take named params;
+// published events are pushed to the client as "todo.event" notifications.
+package rpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	appErr "github.com/rojanmagar2001/gotodo/internal/application/errors"
+	"github.com/rojanmagar2001/gotodo/internal/application/ports"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/application/result"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/interfaces/jsonrpc"
+)
+
+// Application error codes, in the range JSON-RPC reserves for servers.
+const (
+	CodeNotFound   = -32001
+	CodeValidation = -32002
+	CodeConflict   = -32003
+	CodeVetoed     = -32004
+)
+
+// EventMethod is the notification sent for every published domain event.
+const EventMethod = "todo.event"
+
+type Server struct {
+	// Commands
+	Add        commands.AddTodo
+	Edit       commands.EditTodo
+	Complete   commands.CompleteTodo
+	Reopen     commands.ReopenTodo
+	Archive    commands.ArchiveTodo
+	Restore    commands.RestoreTodo
+	Delete     commands.SoftDeleteTodo
+	HardDelete commands.HardDeleteTodo
+
+	// Queries
+	List     queries.ListTodos
+	Get      queries.GetTodo
+	Stats    queries.Stats
+	Projects queries.ListProjects // optional; enables todo.projects
+
+	once sync.Once
+	rpc  *jsonrpc.Server
+}
+
+// Serve answers requests on r/w until EOF or ctx ends.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.init()
+	return s.rpc.Serve(ctx, r, w)
+}
+
+// EventNotification is the params of a "todo.event" notification. Todo is
+// the state at the time the notification is sent.
+type EventNotification struct {
+	Event      string           `json:"event"`
+	TodoID     string           `json:"todoId"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	Todo       *queries.TodoDTO `json:"todo,omitempty"`
+}
+
+// Publish implements ports.EventPublisher so the server can be added to
+// the app's publishers; events become notifications to the client.
+func (s *Server) Publish(ctx context.Context, evs []todo.Event) error {
+	s.init()
+	for _, e := range evs {
+		n := EventNotification{Event: todo.EventName(e), TodoID: todo.EventTodoID(e).String(), OccurredAt: todo.EventTime(e)}
+		if res := s.Get.Execute(ctx, todo.EventTodoID(e)); res.Err == nil {
+			n.Todo = &res.Value
+		}
+		if err := s.rpc.Notify(EventMethod, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) init() { s.once.Do(s.register) }
+
+func (s *Server) register() {
+	s.rpc = jsonrpc.NewServer()
+	s.rpc.MapError = mapError
+
+	s.rpc.Register("todo.add", s.add)
+	s.rpc.Register("todo.edit", s.edit)
+	s.rpc.Register("todo.complete", byID(s.Complete.Execute))
+	s.rpc.Register("todo.reopen", byID(s.Reopen.Execute))
+	s.rpc.Register("todo.archive", byID(s.Archive.Execute))
+	s.rpc.Register("todo.restore", byID(s.Restore.Execute))
+	s.rpc.Register("todo.delete", byID(s.Delete.Execute))
+	s.rpc.Register("todo.hardDelete", s.hardDelete)
+	s.rpc.Register("todo.get", s.get)
+	s.rpc.Register("todo.list", s.list)
+	s.rpc.Register("todo.stats", s.stats)
+	if s.Projects.Projects != nil {
+		s.rpc.Register("todo.projects", s.projects)
+	}
+}
+
+type addParams struct {
+	Title    string   `json:"title"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags"`
+	DueDate  *string  `json:"dueDate"`
+	Project  string   `json:"project"`
+}
+
+func (s *Server) add(ctx context.Context, raw json.RawMessage) (any, error) {
+	var p addParams
+	if err := decode(raw, &p); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return dto(s.Add.Execute(ctx, commands.AddTodoInput{
+		Title: p.Title, Priority: p.Priority, Tags: p.Tags, DueDate: p.DueDate, Project: p.Project,
+	}))
+}
+
+// edit takes {id, title?, priority?, tags?, dueDate?, scheduled?,
+// remindAt?, estimate?, project?}; dates may be relative ("tomorrow",
+// "+2w"), null clears them, estimate "" clears it and project "" moves
+// the todo to the inbox.
+func (s *Server) edit(ctx context.Context, raw json.RawMessage) (any, error) {
+	var fields map[string]json.RawMessage
+	if err := decode(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	var in commands.EditTodoInput
+	for k, v := range fields {
+		var err error
+		switch k {
+		case "id":
+			err = json.Unmarshal(v, &in.ID)
+		case "title":
+			err = json.Unmarshal(v, &in.Title)
+		case "priority":
+			err = json.Unmarshal(v, &in.Priority)
+		case "tags":
+			err = json.Unmarshal(v, &in.Tags)
+		case "dueDate":
+			var due *string
+			err = json.Unmarshal(v, &due)
+			in.DueDate = &due
+		case "scheduled":
+			var when *string
+			err = json.Unmarshal(v, &when)
+			in.Scheduled = &when
+		case "remindAt":
+			var when *string
+			err = json.Unmarshal(v, &when)
+			in.RemindAt = &when
+		case "estimate":
+			err = json.Unmarshal(v, &in.Estimate)
+		case "project":
+			err = json.Unmarshal(v, &in.Project)
+		default:
+			err = errors.New("unknown field")
+		}
+		if err != nil {
+			return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "%s: %v", k, err)
+		}
+	}
+	if !in.ID.Valid() {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "id is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return dto(s.Edit.Execute(ctx, in))
+}
+
+type idParams struct {
+	ID todo.TodoID `json:"id"`
+}
+
+func parseID(raw json.RawMessage) (todo.TodoID, error) {
+	var p idParams
+	if err := decode(raw, &p); err != nil {
+		return "", err
+	}
+	if !p.ID.Valid() {
+		return "", jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "id is required")
+	}
+	return p.ID, nil
+}
+
+func byID(run func(context.Context, todo.TodoID) result.Result[todo.Todo]) jsonrpc.Handler {
+	return func(ctx context.Context, raw json.RawMessage) (any, error) {
+		id, err := parseID(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return dto(run(ctx, id))
+	}
+}
+
+func (s *Server) hardDelete(ctx context.Context, raw json.RawMessage) (any, error) {
+	id, err := parseID(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res := s.HardDelete.Execute(ctx, id)
+	return res.Value, res.Err
+}
+
+func (s *Server) get(ctx context.Context, raw json.RawMessage) (any, error) {
+	id, err := parseID(raw)
+	if err != nil {
+		return nil, err
+	}
+	res := s.Get.Execute(ctx, id)
+	return res.Value, res.Err
+}
+
+// ListParams mirrors ports.ListSpec. Project "" is the inbox; leave it
+// out for every project.
+type ListParams struct {
+	Status         string  `json:"status"`
+	Tag            string  `json:"tag"`
+	Project        *string `json:"project"`
+	Search         string  `json:"q"`
+	Sort           string  `json:"sort"`
+	Order          string  `json:"order"`
+	Limit          int     `json:"limit"`
+	Offset         int     `json:"offset"`
+	IncludeDeleted bool    `json:"deleted"`
+
+	IncludeUnstarted bool `json:"scheduled"` // todos that have not started yet
+}
+
+// Spec validates p and converts it.
+func (p ListParams) Spec() (ports.ListSpec, error) {
+	spec := ports.ListSpec{
+		SortBy:         ports.SortField(p.Sort),
+		SortOrder:      ports.SortOrder(p.Order),
+		Limit:          p.Limit,
+		Offset:         p.Offset,
+		IncludeDeleted: p.IncludeDeleted,
+
+		IncludeUnstarted: p.IncludeUnstarted,
+	}
+	if p.Status != "" {
+		st := todo.Status(p.Status)
+		if !st.Valid() {
+			return spec, fmt.Errorf("invalid status %q", p.Status)
+		}
+		spec.Status = &st
+	}
+	if p.Tag != "" {
+		spec.Tag = &p.Tag
+	}
+	if p.Project != nil {
+		pr, err := todo.NewProject(*p.Project)
+		if err != nil {
+			return spec, fmt.Errorf("invalid project %q", *p.Project)
+		}
+		spec.Project = &pr
+	}
+	if p.Search != "" {
+		spec.Search = &p.Search
+	}
+	if spec.SortBy != "" && !spec.SortBy.Valid() {
+		return spec, fmt.Errorf("invalid sort %q", p.Sort)
+	}
+	if spec.SortOrder != "" && !spec.SortOrder.Valid() {
+		return spec, fmt.Errorf("invalid order %q", p.Order)
+	}
+	if p.Limit < 0 || p.Offset < 0 {
+		return spec, errors.New("limit and offset must not be negative")
+	}
+	return spec, nil
+}
+
+func (s *Server) list(ctx context.Context, raw json.RawMessage) (any, error) {
+	var p ListParams
+	if err := decode(raw, &p); err != nil {
+		return nil, err
+	}
+	spec, err := p.Spec()
+	if err != nil {
+		return nil, jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "%v", err)
+	}
+	res := s.List.Execute(ctx, spec)
+	return res.Value, res.Err
+}
+
+func (s *Server) stats(ctx context.Context, _ json.RawMessage) (any, error) {
+	res := s.Stats.Execute(ctx)
+	return res.Value, res.Err
+}
+
+func (s *Server) projects(ctx context.Context, _ json.RawMessage) (any, error) {
+	res := s.Projects.Execute(ctx)
+	return res.Value, res.Err
+}
+
+func dto(res result.Result[todo.Todo]) (any, error) {
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return queries.ToDTO(res.Value), nil
+}
+
+// decode accepts missing params as an empty object.
+func decode(raw json.RawMessage, dst any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return jsonrpc.Errorf(jsonrpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func mapError(err error) *jsonrpc.Error {
+	code := jsonrpc.CodeInternalError
+	switch {
+	case errors.Is(err, appErr.ErrNotFound):
+		code = CodeNotFound
+	case errors.Is(err, appErr.ErrValidation):
+		code = CodeValidation
+	case errors.Is(err, appErr.ErrConflict):
+		code = CodeConflict
+	case errors.Is(err, appErr.ErrVetoed):
+		code = CodeVetoed
+	}
+	return &jsonrpc.Error{Code: code, Message: err.Error()}
+}
@@ -0,0 +1,181 @@
+package rpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+	"github.com/rojanmagar2001/gotodo/internal/infrastructure/jsonstore"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+type seqIDs struct{ n int }
+
+func (s *seqIDs) NewTodoID() todo.TodoID {
+	s.n++
+	return todo.TodoID(fmt.Sprintf("t%d", s.n))
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	repo := jsonstore.NewRepository(filepath.Join(t.TempDir(), "todos.json"))
+	clk := fixedClock{time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}
+
+	s := &Server{
+		List:  queries.ListTodos{Repo: repo},
+		Get:   queries.GetTodo{Repo: repo},
+		Stats: queries.Stats{Repo: repo, Clock: clk},
+
+		Projects: queries.ListProjects{Repo: repo, Projects: repo, Clock: clk},
+	}
+	s.Add = commands.AddTodo{Repo: repo, Clock: clk, IDGen: &seqIDs{}, Publisher: s}
+	s.Edit = commands.EditTodo{Repo: repo, Clock: clk, Publisher: s}
+	s.Complete = commands.CompleteTodo{Repo: repo, Clock: clk, Publisher: s}
+	s.HardDelete = commands.HardDeleteTodo{Repo: repo}
+	return s
+}
+
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+	Params EventNotification `json:"params"`
+}
+
+// session sends requests one at a time so replies come back in order.
+func session(t *testing.T, s *Server, reqs ...string) []message {
+	t.Helper()
+	var out []message
+	for _, req := range reqs {
+		var buf bytes.Buffer
+		if err := s.Serve(context.Background(), strings.NewReader(req+"\n"), &buf); err != nil {
+			t.Fatalf("Serve err=%v", err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var m message
+			if err := json.Unmarshal([]byte(line), &m); err != nil {
+				t.Fatalf("bad message %q: %v", line, err)
+			}
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func TestMethodsAndNotifications(t *testing.T) {
+	s := newTestServer(t)
+	msgs := session(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"todo.add","params":{"title":"Review PR","tags":["work"]}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"todo.edit","params":{"id":"t1","priority":"high","dueDate":"2026-10-21"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"todo.complete","params":{"id":"t1"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"todo.list","params":{"status":"done"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"todo.stats"}`,
+	)
+
+	var events []string
+	results := map[string]json.RawMessage{}
+	for _, m := range msgs {
+		if m.Method == EventMethod {
+			events = append(events, m.Params.Event)
+			continue
+		}
+		if m.Error != nil {
+			t.Fatalf("request %s failed: code %d", m.ID, m.Error.Code)
+		}
+		results[string(m.ID)] = m.Result
+	}
+
+	if strings.Join(events, ",") != "todo.created,todo.completed" {
+		t.Fatalf("events=%v", events)
+	}
+
+	var edited queries.TodoDTO
+	_ = json.Unmarshal(results["2"], &edited)
+	if edited.Priority != "high" || edited.DueDate == nil || *edited.DueDate != "2026-10-21" {
+		t.Fatalf("edited=%+v", edited)
+	}
+	var listed []queries.TodoDTO
+	_ = json.Unmarshal(results["4"], &listed)
+	if len(listed) != 1 || listed[0].Status != "done" {
+		t.Fatalf("listed=%+v", listed)
+	}
+	var stats queries.StatsDTO
+	_ = json.Unmarshal(results["5"], &stats)
+	if stats.Done != 1 {
+		t.Fatalf("stats=%+v", stats)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		req  string
+		code int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.get","params":{"id":"missing"}}`, CodeNotFound},
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.add","params":{"title":"  "}}`, CodeValidation},
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.add","params":{"title":1}}`, -32602},
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.complete","params":{}}`, -32602},
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.list","params":{"sort":"random"}}`, -32602},
+		{`{"jsonrpc":"2.0","id":1,"method":"todo.edit","params":{"id":"t1","colour":"red"}}`, -32602},
+	}
+	for _, tt := range tests {
+		msgs := session(t, s, tt.req)
+		if len(msgs) != 1 || msgs[0].Error == nil || msgs[0].Error.Code != tt.code {
+			t.Fatalf("%s: got %+v want code %d", tt.req, msgs, tt.code)
+		}
+	}
+}
+
+func TestProjects(t *testing.T) {
+	s := newTestServer(t)
+	msgs := session(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"todo.add","params":{"title":"Inbox item"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"todo.add","params":{"title":"Report","project":"work"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"todo.edit","params":{"id":"t2","project":"home"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"todo.list","params":{"project":""}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"todo.projects"}`,
+		`{"jsonrpc":"2.0","id":6,"method":"todo.list","params":{"project":"Not Valid"}}`,
+	)
+
+	var events []string
+	results := map[string]message{}
+	for _, m := range msgs {
+		if m.Method == EventMethod {
+			events = append(events, m.Params.Event)
+			continue
+		}
+		results[string(m.ID)] = m
+	}
+	if strings.Join(events, ",") != "todo.created,todo.created,todo.moved" {
+		t.Fatalf("events=%v", events)
+	}
+
+	var inbox []queries.TodoDTO
+	_ = json.Unmarshal(results["4"].Result, &inbox)
+	if len(inbox) != 1 || inbox[0].Title != "Inbox item" {
+		t.Fatalf("inbox=%+v", inbox)
+	}
+	var projects []queries.ProjectDTO
+	_ = json.Unmarshal(results["5"].Result, &projects)
+	if len(projects) != 2 || projects[1].Name != "home" || projects[1].Active != 1 {
+		t.Fatalf("projects=%+v", projects)
+	}
+	if m := results["6"]; m.Error == nil {
+		t.Fatalf("invalid project accepted: %s", m.Result)
+	}
+}
@@ -9,9 +9,18 @@ type App struct {
 	// Commands
 	Add      commands.AddTodo
 	Complete commands.CompleteTodo
+	Start    commands.StartTimer // optional, with Timer
+	Stop     commands.StopTimer
 
 	// Queries
-	List  queries.ListTodos
-	Get   queries.GetTodo
-	Stats queries.Stats
+	List     queries.ListTodos
+	Get      queries.GetTodo
+	Stats    queries.Stats
+	Projects queries.ListProjects // optional; enables the project switcher
+	Timer    queries.RunningTimer // optional; shows and toggles the timer
+
+	// Presentation; zero values mean the defaults
+	Keys       *Keymap
+	Theme      Theme
+	DateFormat string // Go layout for due dates, default 2006-01-02
 }
@@ -1,4 +1,75 @@
 package tui
 
-// Keep keybindings centralized as the UI grows.
-// We'll replace this with bubbles/key later.
+import "slices"
+
+// Keymap keeps keybindings centralized as the UI grows. Each action can
+// have several keys, named as tea.KeyMsg.String() names them.
+type Keymap struct {
+	Quit     []string
+	Up       []string
+	Down     []string
+	Complete []string
+	Reload   []string
+	Project  []string // cycle through the projects
+	Add      []string // open the quick-add line
+	Timer    []string // start or stop the timer on the selected todo
+}
+
+func DefaultKeymap() Keymap {
+	return Keymap{
+		Quit:     []string{"q", "ctrl+c"},
+		Up:       []string{"k", "up"},
+		Down:     []string{"j", "down"},
+		Complete: []string{"x", " "},
+		Reload:   []string{"r"},
+		Project:  []string{"p"},
+		Add:      []string{"a"},
+		Timer:    []string{"t"},
+	}
+}
+
+// Bind replaces the keys of action (quit, up, down, complete, reload,
+// project, add or timer); unknown actions are ignored.
+func (k Keymap) Bind(action string, keys []string) Keymap {
+	switch action {
+	case "quit":
+		k.Quit = keys
+	case "up":
+		k.Up = keys
+	case "down":
+		k.Down = keys
+	case "complete":
+		k.Complete = keys
+	case "reload":
+		k.Reload = keys
+	case "project":
+		k.Project = keys
+	case "add":
+		k.Add = keys
+	case "timer":
+		k.Timer = keys
+	}
+	return k
+}
+
+func matches(keys []string, key string) bool { return slices.Contains(keys, key) }
+
+// help is the first key of each action, for the footer.
+func (k Keymap) help() string {
+	first := func(keys []string) string {
+		if len(keys) == 0 {
+			return "-"
+		}
+		if keys[0] == " " {
+			return "space"
+		}
+		return keys[0]
+	}
+	return first(k.Up) + "/" + first(k.Down) + ": move  " +
+		first(k.Add) + ": add  " +
+		first(k.Complete) + ": complete  " +
+		first(k.Reload) + ": reload  " +
+		first(k.Project) + ": project  " +
+		first(k.Timer) + ": timer  " +
+		first(k.Quit) + ": quit"
+}
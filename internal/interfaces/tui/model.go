@@ -1,16 +1,46 @@
 package tui
 
-import "github.com/rojanmagar2001/gotodo/internal/application/queries"
+import (
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
+)
 
 type Model struct {
-	app App
+	app  App
+	keys Keymap
 
 	// UI state
-	todos []queries.TodoDTO
-	err   error
-	ready bool
+	todos    []queries.TodoDTO
+	projects []string // "" is the inbox
+	project  int      // 0 shows every project, i shows projects[i-1]
+	cursor   int
+	adding   bool   // the quick-add line is open
+	input    string // what has been typed into it
+	status   string // the outcome of the last add
+	timer    *queries.TimerDTO
+	now      time.Time // for the timer, updated every tick
+	err      error
+	ready    bool
 }
 
 func NewModel(app App) Model {
-	return Model{app: app}
+	keys := DefaultKeymap()
+	if app.Keys != nil {
+		keys = *app.Keys
+	}
+	if app.DateFormat == "" {
+		app.DateFormat = "2006-01-02"
+	}
+	return Model{app: app, keys: keys}
+}
+
+// filter is the project being shown, nil for all of them.
+func (m Model) filter() *todo.Project {
+	if m.project == 0 || m.project > len(m.projects) {
+		return nil
+	}
+	p := todo.Project(m.projects[m.project-1])
+	return &p
 }
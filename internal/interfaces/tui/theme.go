@@ -0,0 +1,38 @@
+package tui
+
+import "os"
+
+// Theme holds ANSI SGR parameters (e.g. "1;36") for each part of the
+// screen. The zero Theme prints plain text.
+type Theme struct {
+	Header   string
+	Selected string
+	Done     string
+	Muted    string
+	Token    string // recognized words in the quick-add line
+}
+
+// ThemeNamed returns the theme called auto, dark, light or plain. auto
+// sticks to bold, dim and reverse video, which read on any background,
+// and turns into plain when NO_COLOR is set.
+func ThemeNamed(name string) Theme {
+	switch name {
+	case "dark":
+		return Theme{Header: "1;36", Selected: "1;97;44", Done: "32", Muted: "90", Token: "33"}
+	case "light":
+		return Theme{Header: "1;34", Selected: "1;30;106", Done: "32", Muted: "37", Token: "35"}
+	case "plain":
+		return Theme{}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return Theme{}
+	}
+	return Theme{Header: "1", Selected: "7", Done: "2", Muted: "2", Token: "4"}
+}
+
+func paint(sgr, s string) string {
+	if sgr == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
@@ -2,37 +2,187 @@ package tui
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
 	"github.com/rojanmagar2001/gotodo/internal/application/ports"
 	"github.com/rojanmagar2001/gotodo/internal/application/queries"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
 )
 
 type todosLoadedMsg struct {
-	todos []queries.TodoDTO
-	err   error
+	todos    []queries.TodoDTO
+	projects []string
+	timer    *queries.TimerDTO
+	err      error
 }
 
-func (m Model) Init() tea.Cmd { return m.loadTodosCmd() }
+// tickMsg keeps the running timer's time current.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m Model) Init() tea.Cmd { return tea.Batch(m.loadTodosCmd(), tick()) }
 
 func (m Model) loadTodosCmd() tea.Cmd {
 	return func() tea.Msg {
-		res := m.app.List.Execute(context.Background(), ports.ListSpec{})
-		return todosLoadedMsg{todos: res.Value, err: res.Err}
+		ctx := context.Background()
+		var projects []string
+		if m.app.Projects.Projects != nil {
+			res := m.app.Projects.Execute(ctx)
+			if res.Err != nil {
+				return todosLoadedMsg{todos: m.todos, projects: m.projects, err: res.Err}
+			}
+			for _, p := range res.Value {
+				projects = append(projects, p.Name)
+			}
+		}
+		var timer *queries.TimerDTO
+		if m.app.Timer.Repo != nil {
+			res := m.app.Timer.Execute(ctx)
+			if res.Err != nil {
+				return todosLoadedMsg{todos: m.todos, projects: m.projects, err: res.Err}
+			}
+			timer = res.Value
+		}
+		res := m.app.List.Execute(ctx, ports.ListSpec{Project: m.filter()})
+		return todosLoadedMsg{todos: res.Value, projects: projects, timer: timer, err: res.Err}
 	}
 }
 
+// toggleTimerCmd stops the timer if it runs on id, and otherwise starts
+// it there (stopping it wherever else it runs).
+func (m Model) toggleTimerCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		var err error
+		if m.timer != nil && m.timer.Todo.ID == id {
+			err = m.app.Stop.Execute(ctx).Err
+		} else {
+			err = m.app.Start.Execute(ctx, todo.TodoID(id)).Err
+		}
+		if err != nil {
+			return addedMsg{status: "Timer: " + err.Error()}
+		}
+		return m.loadTodosCmd()()
+	}
+}
+
+func (m Model) completeCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		res := m.app.Complete.Execute(context.Background(), todo.TodoID(id))
+		if res.Err != nil {
+			return todosLoadedMsg{todos: m.todos, projects: m.projects, err: res.Err}
+		}
+		return m.loadTodosCmd()()
+	}
+}
+
+type addedMsg struct {
+	status string
+}
+
+// addCmd adds the quick-add line; without a project of its own the todo
+// goes to the project being shown.
+func (m Model) addCmd(line string) tea.Cmd {
+	return func() tea.Msg {
+		in, _ := commands.ParseQuickAdd(line)
+		if in.Title == "" {
+			return addedMsg{status: "Not added: the title is empty"}
+		}
+		if p := m.filter(); p != nil && in.Project == "" {
+			in.Project = p.String()
+		}
+		res := m.app.Add.Execute(context.Background(), in)
+		if res.Err != nil {
+			return addedMsg{status: "Not added: " + res.Err.Error()}
+		}
+		status := "Added " + res.Value.Title.String()
+		if res.Value.DueDate != nil {
+			status += ", due " + m.formatDate(res.Value.DueDate.String())
+		}
+		return addedMsg{status: status}
+	}
+}
+
+// updateInput handles keys while the quick-add line is open.
+func (m Model) updateInput(x tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch x.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.adding, m.input = false, ""
+	case tea.KeyEnter:
+		line := m.input
+		m.adding, m.input = false, ""
+		return m, m.addCmd(line)
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		m.input = ""
+	case tea.KeySpace:
+		m.input += " "
+	case tea.KeyRunes:
+		m.input += string(x.Runes)
+	}
+	return m, nil
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch x := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.ready = true
+	case tickMsg:
+		m.now = time.Time(x)
+		return m, tick()
 	case todosLoadedMsg:
 		m.todos = x.todos
+		m.timer = x.timer
+		m.now = time.Now()
 		m.err = x.err
+		if cur := m.filter(); cur != nil {
+			// keep showing the same project if it moved in the list
+			m.project = slices.Index(x.projects, cur.String()) + 1
+		}
+		m.projects = x.projects
+		m.cursor = min(m.cursor, max(len(m.todos)-1, 0))
+	case addedMsg:
+		m.status = x.status
+		return m, m.loadTodosCmd()
 	case tea.KeyMsg:
-		switch x.String() {
-		case "q", "ctrl+c":
+		if m.adding {
+			return m.updateInput(x)
+		}
+		key := x.String()
+		switch {
+		case key == "ctrl+c" || matches(m.keys.Quit, key):
 			return m, tea.Quit
+		case matches(m.keys.Add, key):
+			m.adding, m.status = true, ""
+		case matches(m.keys.Up, key):
+			m.cursor = max(m.cursor-1, 0)
+		case matches(m.keys.Down, key):
+			m.cursor = min(m.cursor+1, max(len(m.todos)-1, 0))
+		case matches(m.keys.Reload, key):
+			return m, m.loadTodosCmd()
+		case matches(m.keys.Project, key) && len(m.projects) > 0:
+			m.project = (m.project + 1) % (len(m.projects) + 1)
+			m.cursor = 0
+			return m, m.loadTodosCmd()
+		case matches(m.keys.Timer, key) && m.app.Timer.Repo != nil:
+			if m.cursor < len(m.todos) {
+				return m, m.toggleTimerCmd(m.todos[m.cursor].ID)
+			}
+		case matches(m.keys.Complete, key):
+			if m.cursor < len(m.todos) && m.todos[m.cursor].Status == string(todo.StatusActive) {
+				return m, m.completeCmd(m.todos[m.cursor].ID)
+			}
 		}
 	}
 	return m, nil
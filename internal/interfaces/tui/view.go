@@ -1,30 +1,104 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
+	"time"
+
+	"github.com/rojanmagar2001/gotodo/internal/application/commands"
+	"github.com/rojanmagar2001/gotodo/internal/domain/todo"
 )
 
 func (m Model) View() string {
+	th := m.app.Theme
 	if m.err != nil {
 		return "Error: " + m.err.Error() + "\n\nPress q to quit.\n"
 	}
 
 	var b strings.Builder
-	b.WriteString("Todo (Milestone 6)\n")
+	header := "Todo (Milestone 6)"
+	if p := m.filter(); p != nil {
+		name := p.String()
+		if p.IsInbox() {
+			name = "inbox"
+		}
+		header += " · " + name
+	} else if len(m.projects) > 0 {
+		header += " · all projects"
+	}
+	if m.timer != nil {
+		header += " · ⏱ " + formatElapsed(m.now.Sub(m.timer.Since)) + " " + m.timer.Todo.Title
+	}
+	b.WriteString(paint(th.Header, header) + "\n")
 	b.WriteString("------------------\n\n")
 
 	if len(m.todos) == 0 {
 		b.WriteString("(no todos yet)\n")
 	} else {
-		for _, td := range m.todos {
-			b.WriteString("- ")
-			b.WriteString(td.Title)
-			b.WriteString(" [")
-			b.WriteString(td.Status)
-			b.WriteString("]\n")
+		for i, td := range m.todos {
+			line := "- " + td.Title + " [" + td.Status + "]"
+			if td.DueDate != nil {
+				line += " due " + m.formatDate(*td.DueDate)
+			}
+			if m.timer != nil && m.timer.Todo.ID == td.ID {
+				line += " ⏱"
+			}
+			switch {
+			case i == m.cursor:
+				line = paint(th.Selected, line)
+			case td.Status != string(todo.StatusActive):
+				line = paint(th.Done, line)
+			}
+			b.WriteString(line + "\n")
 		}
 	}
 
-	b.WriteString("\nq: quit\n")
+	b.WriteString("\n")
+	switch {
+	case m.adding:
+		b.WriteString("Add: " + m.highlight(m.input) + "█\n")
+		b.WriteString(paint(th.Muted, "#tag !high due:fri ^parent project:name  enter: add  esc: cancel") + "\n")
+	case m.status != "":
+		b.WriteString(m.status + "\n")
+		fallthrough
+	default:
+		b.WriteString(paint(th.Muted, m.keys.help()) + "\n")
+	}
 	return b.String()
 }
+
+// highlight paints the words of a quick-add line that are not title text.
+func (m Model) highlight(line string) string {
+	_, toks := commands.ParseQuickAdd(line)
+	var b strings.Builder
+	at := 0
+	for _, tok := range toks {
+		b.WriteString(line[at:tok.Start])
+		word := line[tok.Start:tok.End]
+		if tok.Kind != commands.QuickTitle {
+			word = paint(m.app.Theme.Token, word)
+		}
+		b.WriteString(word)
+		at = tok.End
+	}
+	b.WriteString(line[at:])
+	return b.String()
+}
+
+// formatDate shows a due date in the configured layout, with its time.
+func (m Model) formatDate(s string) string {
+	d, err := todo.ParseDueDate(s)
+	if err != nil {
+		return s
+	}
+	if d.HasTime() {
+		return d.In(time.UTC).Format(m.app.DateFormat + " 15:04")
+	}
+	return d.AsTimeUTC().Format(m.app.DateFormat)
+}
+
+// formatElapsed shows the running timer's time, like 1:05.
+func formatElapsed(d time.Duration) string {
+	mins := int(max(d, 0) / time.Minute)
+	return fmt.Sprintf("%d:%02d", mins/60, mins%60)
+}
@@ -0,0 +1,355 @@
+// gotodo web UI. Plain DOM, no dependencies: everything goes through the
+// JSON API under /api and live updates arrive on /api/events.
+"use strict";
+
+const $ = (sel, root = document) => root.querySelector(sel);
+
+const state = {
+  todos: [],
+  selected: 0,
+  filter: { status: "active", q: "", tag: "", sort: "due" },
+};
+
+// ---- API ----
+
+async function api(method, path, body, headers = {}) {
+  const opts = { method, headers: { ...headers } };
+  if (body !== undefined) {
+    opts.headers["Content-Type"] = "application/json";
+    opts.body = JSON.stringify(body);
+  }
+  const res = await fetch("/api" + path, opts);
+  if (res.status === 204) return { res, data: null };
+  const data = await res.json().catch(() => null);
+  if (!res.ok) {
+    const err = new Error((data && data.error) || res.statusText);
+    err.status = res.status;
+    throw err;
+  }
+  return { res, data };
+}
+
+function showError(err) {
+  const el = $("#error");
+  el.textContent = err ? String(err.message || err) : "";
+  el.hidden = !err;
+}
+
+// ---- loading and rendering ----
+
+let loading = null;
+
+async function load() {
+  const q = new URLSearchParams();
+  for (const [k, v] of Object.entries(state.filter)) {
+    if (v) q.set(k, v);
+  }
+  const selectedID = state.todos[state.selected] && state.todos[state.selected].id;
+  try {
+    const [list, stats] = await Promise.all([
+      api("GET", "/todos?" + q),
+      api("GET", "/stats"),
+    ]);
+    state.todos = list.data || [];
+    const keep = state.todos.findIndex((t) => t.id === selectedID);
+    state.selected = keep >= 0 ? keep : Math.min(state.selected, Math.max(state.todos.length - 1, 0));
+    render();
+    renderStats(stats.data);
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// reload coalesces bursts (typing, a flurry of events) into one request.
+function reload() {
+  clearTimeout(loading);
+  loading = setTimeout(load, 50);
+}
+
+// now is the local time as YYYY-MM-DDTHH:MM, comparable with due dates
+function now() {
+  const d = new Date();
+  const pad = (n) => String(n).padStart(2, "0");
+  return `${d.getFullYear()}-${pad(d.getMonth() + 1)}-${pad(d.getDate())}T${pad(d.getHours())}:${pad(d.getMinutes())}`;
+}
+
+// overdue: a due time has passed, or a due day has ended
+function isOverdue(due, at) {
+  return due.length > 10 ? due <= at : due < at.slice(0, 10);
+}
+
+function el(tag, cls, text) {
+  const e = document.createElement(tag);
+  if (cls) e.className = cls;
+  if (text !== undefined) e.textContent = text;
+  return e;
+}
+
+function render() {
+  const list = $("#list");
+  list.replaceChildren();
+  const at = now();
+
+  state.todos.forEach((t, i) => {
+    const li = el("li", t.status);
+    li.dataset.index = i;
+    if (i === state.selected) li.classList.add("selected");
+
+    const check = el("input");
+    check.type = "checkbox";
+    check.checked = t.status !== "active";
+    check.disabled = t.status === "archived";
+    check.title = "Complete (x)";
+    check.addEventListener("change", () => toggle(t));
+
+    const prio = el("span", "prio " + t.priority);
+    prio.title = t.priority + " priority";
+
+    li.append(check, prio, el("span", "title", t.title));
+    for (const tag of t.tags || []) li.append(el("span", "tag", "#" + tag));
+    if (t.dueDate) {
+      const overdue = t.status === "active" && isOverdue(t.dueDate, at);
+      li.append(el("span", "due" + (overdue ? " overdue" : ""), t.dueDate.replace("T", " ")));
+    }
+
+    const actions = el("span", "actions");
+    const edit = el("button", "", "Edit");
+    edit.type = "button";
+    edit.addEventListener("click", () => openEdit(t));
+    actions.append(edit);
+    li.append(actions);
+
+    li.addEventListener("click", (ev) => {
+      if (ev.target.closest("button, input")) return;
+      select(i);
+    });
+    li.addEventListener("dblclick", () => openEdit(t));
+    list.append(li);
+  });
+
+  $("#empty").hidden = state.todos.length > 0;
+  for (const b of document.querySelectorAll(".tabs button")) {
+    b.setAttribute("aria-selected", String(b.dataset.status === state.filter.status));
+  }
+}
+
+function renderStats(s) {
+  if (!s) return;
+  $("#stats").textContent =
+    `${s.active} active · ${s.done} done · ${s.archived} archived` +
+    ` · ${s.overdue} overdue · ${s.dueToday} due today`;
+}
+
+function select(i) {
+  if (!state.todos.length) return;
+  state.selected = Math.max(0, Math.min(i, state.todos.length - 1));
+  const items = $("#list").children;
+  for (const li of items) li.classList.toggle("selected", Number(li.dataset.index) === state.selected);
+  const cur = items[state.selected];
+  if (cur) cur.scrollIntoView({ block: "nearest" });
+}
+
+function current() {
+  return state.todos[state.selected];
+}
+
+// ---- actions ----
+
+function splitTags(s) {
+  return s.split(",").map((t) => t.trim()).filter(Boolean);
+}
+
+async function run(fn) {
+  try {
+    await fn();
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+  reload();
+}
+
+function toggle(t) {
+  if (!t || t.status === "archived") return;
+  const verb = t.status === "done" ? "reopen" : "complete";
+  return run(() => api("POST", `/todos/${encodeURIComponent(t.id)}/${verb}`));
+}
+
+function archive(t) {
+  if (!t) return;
+  if (t.status === "active") {
+    showError("Only done todos can be archived.");
+    return;
+  }
+  const verb = t.status === "archived" ? "restore" : "archive";
+  return run(() => api("POST", `/todos/${encodeURIComponent(t.id)}/${verb}`));
+}
+
+function remove(t) {
+  if (!t || !confirm(`Delete "${t.title}"?`)) return;
+  return run(() => api("DELETE", `/todos/${encodeURIComponent(t.id)}`));
+}
+
+$("#add").addEventListener("submit", (ev) => {
+  ev.preventDefault();
+  const f = ev.target;
+  const body = {
+    title: f.elements.title.value,
+    priority: f.elements.priority.value,
+    tags: splitTags(f.elements.tags.value),
+  };
+  if (f.elements.dueDate.value) body.dueDate = f.elements.dueDate.value;
+  run(async () => {
+    await api("POST", "/todos", body);
+    f.reset();
+  });
+});
+
+// ---- editing ----
+
+const dialog = $("#edit");
+let editing = null; // {id, etag, dueTime}
+
+async function openEdit(t) {
+  if (!t) return;
+  try {
+    // fetch the current version and its ETag so a concurrent change
+    // elsewhere is detected instead of overwritten
+    const { res, data } = await api("GET", `/todos/${encodeURIComponent(t.id)}`);
+    // the date input drops a due time; keep it unless the date is cleared
+    editing = { id: data.id, etag: res.headers.get("ETag"), dueTime: (data.dueDate || "").slice(10) };
+    const f = $("form", dialog);
+    f.elements.title.value = data.title;
+    f.elements.priority.value = data.priority;
+    f.elements.tags.value = (data.tags || []).join(", ");
+    f.elements.dueDate.value = (data.dueDate || "").slice(0, 10);
+    $(".error", dialog).hidden = true;
+    dialog.returnValue = ""; // Esc keeps the previous value
+    dialog.showModal();
+    f.elements.title.focus();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+dialog.addEventListener("close", async () => {
+  if (dialog.returnValue !== "save" || !editing) return;
+  const f = $("form", dialog);
+  const body = {
+    title: f.elements.title.value,
+    priority: f.elements.priority.value,
+    tags: splitTags(f.elements.tags.value),
+    dueDate: f.elements.dueDate.value ? f.elements.dueDate.value + editing.dueTime : null,
+  };
+  const headers = editing.etag ? { "If-Match": editing.etag } : {};
+  try {
+    await api("PATCH", `/todos/${encodeURIComponent(editing.id)}`, body, headers);
+    editing = null;
+  } catch (err) {
+    const msg = err.status === 412 ? "This todo changed elsewhere; review and save again." : err.message;
+    const t = { id: editing.id };
+    await openEdit(t);
+    const p = $(".error", dialog);
+    p.textContent = msg;
+    p.hidden = false;
+  }
+  reload();
+});
+
+// ---- filters ----
+
+for (const b of document.querySelectorAll(".tabs button")) {
+  b.addEventListener("click", () => setStatus(b.dataset.status));
+}
+
+function setStatus(status) {
+  state.filter.status = status;
+  state.selected = 0;
+  reload();
+}
+
+$("#search").addEventListener("input", (ev) => {
+  state.filter.q = ev.target.value.trim();
+  reload();
+});
+$("#tag").addEventListener("input", (ev) => {
+  state.filter.tag = ev.target.value.trim();
+  reload();
+});
+$("#sort").addEventListener("change", (ev) => {
+  state.filter.sort = ev.target.value;
+  reload();
+});
+
+// ---- keyboard ----
+
+const keys = {
+  j: () => select(state.selected + 1),
+  ArrowDown: () => select(state.selected + 1),
+  k: () => select(state.selected - 1),
+  ArrowUp: () => select(state.selected - 1),
+  g: () => select(0),
+  G: () => select(state.todos.length - 1),
+  x: () => toggle(current()),
+  " ": () => toggle(current()),
+  e: () => openEdit(current()),
+  Enter: () => openEdit(current()),
+  a: () => $("#add").elements.title.focus(),
+  A: () => archive(current()),
+  d: () => remove(current()),
+  "/": () => $("#search").focus(),
+  r: () => load(),
+  1: () => setStatus("active"),
+  2: () => setStatus("done"),
+  3: () => setStatus("archived"),
+  4: () => setStatus(""),
+  "?": () => $("#help").showModal(),
+};
+
+document.addEventListener("keydown", (ev) => {
+  if (ev.ctrlKey || ev.metaKey || ev.altKey) return;
+  if (document.querySelector("dialog[open]")) return; // dialogs handle their own keys
+
+  const field = ev.target.closest("input, select, textarea");
+  if (field) {
+    if (ev.key === "Escape") field.blur();
+    return;
+  }
+
+  const fn = keys[ev.key];
+  if (fn) {
+    ev.preventDefault();
+    fn();
+  }
+});
+
+// ---- live updates ----
+
+const EVENTS = [
+  "todo.created", "todo.title_changed", "todo.completed", "todo.reopened",
+  "todo.archived", "todo.restored", "todo.deleted", "todo.moved", "todo.scheduled",
+  "todo.reminder_set", "todo.timer_started", "todo.timer_stopped", "todo.time_logged",
+  "todo.estimated", "todo.changed", "reset",
+];
+
+function connect() {
+  if (!window.EventSource) return;
+  const live = $("#live");
+  // EventSource resends Last-Event-ID on reconnect, so nothing is missed
+  // while the server buffer still has it; "reset" means reload instead.
+  const es = new EventSource("/api/events");
+  es.onopen = () => {
+    live.textContent = "live";
+    live.classList.add("on");
+    reload(); // catch up with anything from before the stream opened
+  };
+  es.onerror = () => {
+    live.textContent = "reconnecting…";
+    live.classList.remove("on");
+  };
+  for (const name of EVENTS) es.addEventListener(name, reload);
+}
+
+load();
+connect();
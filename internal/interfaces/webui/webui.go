@@ -0,0 +1,31 @@
+// Package webui is the browser front end for `todo serve`: a single page
+// embedded in the binary that talks to the JSON API under /api and follows
+// /api/events for live updates. It loads nothing from the network besides
+// the API, so it works offline.
+package webui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the UI's files. Mount it at "/" next to the API.
+func Handler() http.Handler {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embed pattern guarantees the directory
+	}
+	files := http.FileServerFS(sub)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		// same-origin only: keeps the page honest about working offline
+		h.Set("Content-Security-Policy", "default-src 'self'; img-src 'self' data:")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	})
+}
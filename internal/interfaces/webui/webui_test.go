@@ -0,0 +1,65 @@
+package webui
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHandlerServesAssets(t *testing.T) {
+	h := Handler()
+
+	tests := []struct {
+		path, contentType, contains string
+	}{
+		{"/", "text/html", `<script src="app.js"`},
+		{"/app.js", "javascript", `new EventSource("/api/events")`},
+		{"/app.css", "text/css", ".list"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s code=%d", tt.path, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, tt.contentType) {
+			t.Fatalf("GET %s content-type=%q want=%q", tt.path, ct, tt.contentType)
+		}
+		if !strings.Contains(rec.Body.String(), tt.contains) {
+			t.Fatalf("GET %s body missing %q", tt.path, tt.contains)
+		}
+		if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+			t.Fatalf("GET %s csp=%q", tt.path, csp)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope.js", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /nope.js code=%d want=404", rec.Code)
+	}
+}
+
+// The UI must work without network access, so nothing may point off-site.
+func TestAssetsAreSelfContained(t *testing.T) {
+	remote := regexp.MustCompile(`(?i)(https?:)?//[a-z0-9.-]+\.[a-z]{2,}`)
+	err := fs.WalkDir(static, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := static.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if m := remote.Find(b); m != nil {
+			t.Errorf("%s references %s", path, m)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}